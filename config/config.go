@@ -1,6 +1,7 @@
 package config
 
 import (
+	"fmt"
 	"products-api-with-jwt/models"
 	"time"
 
@@ -11,13 +12,74 @@ import (
 
 // SetupDatabase initializes the SQLite database
 func SetupDatabase() (*gorm.DB, error) {
-	db, err := gorm.Open(sqlite.Open("test.db"), &gorm.Config{})
+	// busy_timeout menunggu lock alih-alih langsung gagal, txlock=immediate mengambil write lock
+	// di awal transaksi sehingga update stok yang bersamaan tidak saling deadlock
+	db, err := gorm.Open(sqlite.Open("test.db?_busy_timeout=5000&_txlock=immediate"), &gorm.Config{})
 	if err != nil {
 		return db, err
 	}
 
-	// Migrate tables for User and Product models
-	db.AutoMigrate(&models.User{}, &models.Product{}, &models.LoggingHistory{})
+	// Barcode variant dulu disimpan sebagai string kosong, ubah ke NULL sebelum unique index dibuat
+	if db.Migrator().HasTable(&models.ProductVariant{}) {
+		db.Exec("UPDATE product_variants SET barcode = NULL WHERE barcode = ''")
+	}
+
+	// Kolom harga dulu berupa float dalam rupiah. Kolom minor unit yang baru diisi dari kolom lama
+	// (IDR punya 2 digit minor unit), lalu kolom lama dihapus agar tidak lagi dipakai.
+	moneyColumns := []struct {
+		model    interface{}
+		from, to string
+	}{
+		{&models.Product{}, "harga", "harga_minor"},
+		{&models.PriceChange{}, "old_harga", "old_harga_minor"},
+		{&models.PriceChange{}, "new_harga", "new_harga_minor"},
+		{&models.ScheduledPrice{}, "harga", "harga_minor"},
+		{&models.ScheduledPrice{}, "previous_harga", "previous_harga_minor"},
+		{&models.ProductVariant{}, "price_override", "price_override_minor"},
+	}
+
+	// Unique index stok warehouse lama hanya berisi warehouse dan produk, sehingga stok variant tidak bisa
+	// disimpan. Index-nya diganti, datanya tetap.
+	if db.Migrator().HasIndex(&models.WarehouseStock{}, "idx_warehouse_product") {
+		if err := db.Migrator().DropIndex(&models.WarehouseStock{}, "idx_warehouse_product"); err != nil {
+			return db, err
+		}
+	}
+
+	// Migrate tables for all models
+	db.AutoMigrate(&models.User{}, &models.Product{}, &models.LoggingHistory{}, &models.StockMovement{}, &models.Reservation{},
+		&models.Warehouse{}, &models.WarehouseStock{}, &models.Category{}, &models.Tag{},
+		&models.ProductOption{}, &models.ProductVariant{}, &models.ProductImage{},
+		&models.PriceChange{}, &models.ScheduledPrice{}, &models.ExchangeRate{}, &models.ProductCurrencyPrice{},
+		&models.Promotion{}, &models.ProductTranslation{},
+		&models.AttributeDefinition{}, &models.BundleComponent{}, &models.ProductRelation{},
+		&models.Supplier{}, &models.SupplierProduct{}, &models.PurchaseOrder{}, &models.PurchaseOrderLine{},
+		&models.Order{}, &models.OrderLine{}, &models.Cart{}, &models.CartItem{},
+		&models.TaxClass{}, &models.TaxRate{}, &models.Review{},
+		&models.PriceList{}, &models.PriceListAssignment{}, &models.PriceListRule{})
+
+	for _, column := range moneyColumns {
+		if !db.Migrator().HasColumn(column.model, column.from) {
+			continue
+		}
+		stmt := &gorm.Statement{DB: db}
+		if err := stmt.Parse(column.model); err != nil {
+			return db, err
+		}
+		err := db.Exec(fmt.Sprintf("UPDATE %s SET %s = CAST(ROUND(%s * 100) AS INTEGER) WHERE %s IS NOT NULL",
+			stmt.Schema.Table, column.to, column.from, column.from)).Error
+		if err != nil {
+			return db, err
+		}
+		if err := db.Migrator().DropColumn(column.model, column.from); err != nil {
+			return db, err
+		}
+	}
+
+	// Produk yang sudah punya gambar sebelum kolom has_had_images ada ditandai dari tabel gambarnya
+	if err := db.Exec("UPDATE products SET has_had_images = true WHERE has_had_images = false AND id IN (SELECT product_id FROM product_images)").Error; err != nil {
+		return db, err
+	}
 
 	// Populate initial data
 	populateInitialData(db)
@@ -45,13 +107,19 @@ func populateInitialData(db *gorm.DB) {
 	if count == 0 {
 		// Add example products
 		products := []models.Product{
-			{NamaProduk: "Produk A", Deskripsi: "Deskripsi Produk A", Harga: 1000, Stok: 10},
-			{NamaProduk: "Produk B", Deskripsi: "Deskripsi Produk B", Harga: 2000, Stok: 15},
-			{NamaProduk: "Produk C", Deskripsi: "Deskripsi Produk C", Harga: 3000, Stok: 20},
+			{NamaProduk: "Produk A", Deskripsi: "Deskripsi Produk A", HargaMinor: 100000, Currency: models.DefaultCurrency, Status: models.ProductPublished, Stok: 10},
+			{NamaProduk: "Produk B", Deskripsi: "Deskripsi Produk B", HargaMinor: 200000, Currency: models.DefaultCurrency, Status: models.ProductPublished, Stok: 15},
+			{NamaProduk: "Produk C", Deskripsi: "Deskripsi Produk C", HargaMinor: 300000, Currency: models.DefaultCurrency, Status: models.ProductPublished, Stok: 20},
 		}
 		db.Create(&products)
 	}
 
+	db.Model(&models.Warehouse{}).Count(&count)
+	if count == 0 {
+		// Add default warehouse, existing stock is assigned here on first reconcile
+		db.Create(&models.Warehouse{Code: "MAIN", Name: "Main Warehouse", IsDefault: true})
+	}
+
 	db.Model(&models.LoggingHistory{}).Count(&count)
 	if count == 0 {
 		// Example data: Populate with a couple of sample records
@@ -0,0 +1,32 @@
+package config
+
+import (
+	"log"
+	"os"
+	"strconv"
+	"time"
+)
+
+// GetEnvInt membaca environment variable sebagai integer, dengan nilai default jika kosong atau tidak valid
+func GetEnvInt(key string, defaultValue int) int {
+	value, err := strconv.Atoi(os.Getenv(key))
+	if err != nil {
+		return defaultValue
+	}
+	return value
+}
+
+// GetEnvDuration membaca environment variable dengan format time.ParseDuration (misal "90s", "15m", "24h").
+// Nilai nol atau negatif tidak valid untuk interval scheduler maupun TTL, sehingga memakai nilai default.
+func GetEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	raw := os.Getenv(key)
+	value, err := time.ParseDuration(raw)
+	if err != nil {
+		return defaultValue
+	}
+	if value <= 0 {
+		log.Printf("%s=%s must be a positive duration, using %s", key, raw, defaultValue)
+		return defaultValue
+	}
+	return value
+}
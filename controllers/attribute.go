@@ -0,0 +1,201 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+
+	"products-api-with-jwt/models"
+	"products-api-with-jwt/services"
+	"products-api-with-jwt/validations"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AttributeController struct {
+	AttributeService *services.AttributeService
+}
+
+// NewAttributeController menginisialisasi AttributeController baru
+func NewAttributeController(attributeService *services.AttributeService) *AttributeController {
+	return &AttributeController{AttributeService: attributeService}
+}
+
+// GetCategoryAttributes godoc
+// @Summary Get attribute schemas of a category
+// @Description Get the attribute schemas that apply to products in a category: its own and those inherited from its parent categories. CategoryID tells where each schema is defined.
+// @Tags categories
+// @Security BearerAuth
+// @Param id path int true "Category ID"
+// @Produce json
+// @Success 200 {object} models.ApiResponse
+// @Failure 400 {object} models.ApiResponse
+// @Failure 404 {object} models.ApiResponse
+// @Failure 500 {object} models.ApiResponse
+// @Router /categories/{id}/attributes [get]
+func (ac *AttributeController) GetCategoryAttributes(c *gin.Context) {
+	categoryID, ok := parseCategoryID(c)
+	if !ok {
+		return
+	}
+
+	definitions, err := ac.AttributeService.GetCategoryAttributes(categoryID)
+	if err != nil {
+		ac.handleError(c, err, "Could not retrieve attributes")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ApiResponse{
+		Status:  "success",
+		Code:    http.StatusOK,
+		Message: "Attributes retrieved successfully",
+		Data:    definitions,
+		Count:   len(definitions),
+	})
+}
+
+// SetCategoryAttribute godoc
+// @Summary Set an attribute schema of a category
+// @Description Create or replace an attribute schema in a category (admin only). The schema applies to products in the category and its sub-categories. Values already stored on products are validated again when those products are updated.
+// @Tags categories
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "Category ID"
+// @Param code path string true "Attribute code (lowercase letters, digits and underscores)"
+// @Param attribute body models.AttributeDefinitionInput true "Attribute schema"
+// @Success 200 {object} models.ApiResponse
+// @Failure 400 {object} models.ApiResponse
+// @Failure 403 {object} models.ApiResponse
+// @Failure 404 {object} models.ApiResponse
+// @Failure 409 {object} models.ApiResponse
+// @Failure 500 {object} models.ApiResponse
+// @Router /categories/{id}/attributes/{code} [put]
+func (ac *AttributeController) SetCategoryAttribute(c *gin.Context) {
+	categoryID, ok := parseCategoryID(c)
+	if !ok {
+		return
+	}
+	code, ok := parseAttributeCode(c)
+	if !ok {
+		return
+	}
+
+	var input models.AttributeDefinitionInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Validation failed",
+			Data:    nil,
+			Errors:  validations.FieldErrors(err),
+		})
+		return
+	}
+
+	definition, err := ac.AttributeService.SetCategoryAttribute(categoryID, code, &input)
+	if err != nil {
+		ac.handleError(c, err, "Could not save attribute")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ApiResponse{
+		Status:  "success",
+		Code:    http.StatusOK,
+		Message: "Attribute saved successfully",
+		Data:    definition,
+	})
+}
+
+// DeleteCategoryAttribute godoc
+// @Summary Delete an attribute schema of a category
+// @Description Delete an attribute schema from a category (admin only). Values stored on products are kept until the products are updated.
+// @Tags categories
+// @Security BearerAuth
+// @Param id path int true "Category ID"
+// @Param code path string true "Attribute code"
+// @Produce json
+// @Success 200 {object} models.ApiResponse
+// @Failure 400 {object} models.ApiResponse
+// @Failure 403 {object} models.ApiResponse
+// @Failure 404 {object} models.ApiResponse
+// @Failure 500 {object} models.ApiResponse
+// @Router /categories/{id}/attributes/{code} [delete]
+func (ac *AttributeController) DeleteCategoryAttribute(c *gin.Context) {
+	categoryID, ok := parseCategoryID(c)
+	if !ok {
+		return
+	}
+	code, ok := parseAttributeCode(c)
+	if !ok {
+		return
+	}
+
+	if err := ac.AttributeService.DeleteCategoryAttribute(categoryID, code); err != nil {
+		ac.handleError(c, err, "Could not delete attribute")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ApiResponse{
+		Status:  "success",
+		Code:    http.StatusOK,
+		Message: "Attribute deleted successfully",
+		Data:    nil,
+	})
+}
+
+func (ac *AttributeController) handleError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, services.ErrCategoryNotFound):
+		c.JSON(http.StatusNotFound, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusNotFound,
+			Message: "Category not found",
+			Data:    nil,
+		})
+	case errors.Is(err, services.ErrAttributeNotFound):
+		c.JSON(http.StatusNotFound, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusNotFound,
+			Message: "Attribute not found",
+			Data:    nil,
+		})
+	case errors.Is(err, services.ErrAttributeConflict):
+		c.JSON(http.StatusConflict, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusConflict,
+			Message: "Validation failed",
+			Data:    nil,
+			Errors: []models.FieldError{{
+				Field:   "type",
+				Code:    models.ValidationInvalidValue,
+				Message: err.Error(),
+			}},
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusInternalServerError,
+			Message: fallback,
+			Data:    nil,
+		})
+	}
+}
+
+func parseAttributeCode(c *gin.Context) (string, bool) {
+	code := c.Param("code")
+	if !models.ValidAttributeCode(code) {
+		c.JSON(http.StatusBadRequest, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Validation failed",
+			Data:    nil,
+			Errors: []models.FieldError{{
+				Field:   "code",
+				Code:    models.ValidationInvalidValue,
+				Message: "must start with a lowercase letter and contain only lowercase letters, digits and underscores (at most 50 characters)",
+			}},
+		})
+		return "", false
+	}
+	return code, true
+}
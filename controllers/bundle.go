@@ -0,0 +1,137 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+
+	"products-api-with-jwt/models"
+	"products-api-with-jwt/services"
+	"products-api-with-jwt/validations"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetBundle godoc
+// @Summary Make a product a bundle
+// @Description Turn a product into a bundle of other products, or replace its components. A bundle has no stock of its own: its stock is the number of complete bundles its components can make, and sales, returns and reservations move the stock of every component in one transaction. With pricing "fixed" the bundle's own price is used; with "components" the price is the sum of the components' effective prices minus discount_percent. Components cannot be changed while the bundle has held reservations.
+// @Tags products
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "Product ID"
+// @Param bundle body models.BundleInput true "Bundle"
+// @Success 200 {object} models.ApiResponse
+// @Failure 400 {object} models.ApiResponse
+// @Failure 404 {object} models.ApiResponse
+// @Failure 409 {object} models.ApiResponse
+// @Failure 500 {object} models.ApiResponse
+// @Router /products/{id}/bundle [put]
+func (pc *ProductController) SetBundle(c *gin.Context) {
+	id, ok := parseProductID(c)
+	if !ok {
+		return
+	}
+
+	var input models.BundleInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Validation failed",
+			Data:    nil,
+			Errors:  validations.FieldErrors(err),
+		})
+		return
+	}
+
+	product, err := pc.ProductService.SetBundle(id, &input)
+	if err != nil {
+		handleBundleError(c, err, "Could not save bundle")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ApiResponse{
+		Status:  "success",
+		Code:    http.StatusOK,
+		Message: "Bundle saved successfully",
+		Data:    product,
+	})
+}
+
+// RemoveBundle godoc
+// @Summary Turn a bundle back into a regular product
+// @Description Remove all components from a bundle. The stock of the former components does not change.
+// @Tags products
+// @Security BearerAuth
+// @Param id path int true "Product ID"
+// @Produce json
+// @Success 200 {object} models.ApiResponse
+// @Failure 400 {object} models.ApiResponse
+// @Failure 404 {object} models.ApiResponse
+// @Failure 409 {object} models.ApiResponse
+// @Failure 500 {object} models.ApiResponse
+// @Router /products/{id}/bundle [delete]
+func (pc *ProductController) RemoveBundle(c *gin.Context) {
+	id, ok := parseProductID(c)
+	if !ok {
+		return
+	}
+
+	product, err := pc.ProductService.RemoveBundle(id)
+	if err != nil {
+		handleBundleError(c, err, "Could not remove bundle")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ApiResponse{
+		Status:  "success",
+		Code:    http.StatusOK,
+		Message: "Bundle removed successfully",
+		Data:    product,
+	})
+}
+
+func handleBundleError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, services.ErrProductNotFound):
+		c.JSON(http.StatusNotFound, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusNotFound,
+			Message: "Product not found",
+			Data:    nil,
+		})
+	case errors.Is(err, services.ErrNotBundle):
+		c.JSON(http.StatusNotFound, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusNotFound,
+			Message: "Bundle not found",
+			Data:    nil,
+		})
+	case errors.Is(err, services.ErrInvalidBundleComponent), errors.Is(err, services.ErrBundleCurrencyMismatch):
+		c.JSON(http.StatusBadRequest, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Validation failed",
+			Data:    nil,
+			Errors: []models.FieldError{{
+				Field:   "components",
+				Code:    models.ValidationInvalidValue,
+				Message: err.Error(),
+			}},
+		})
+	case errors.Is(err, services.ErrBundleNotAllowed), errors.Is(err, services.ErrBundleInUse):
+		c.JSON(http.StatusConflict, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusConflict,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusInternalServerError,
+			Message: fallback,
+			Data:    nil,
+		})
+	}
+}
@@ -0,0 +1,276 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"products-api-with-jwt/models"
+	"products-api-with-jwt/services"
+	"products-api-with-jwt/validations"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CartController struct {
+	CartService *services.CartService
+}
+
+// NewCartController menginisialisasi CartController baru
+func NewCartController(cartService *services.CartService) *CartController {
+	return &CartController{CartService: cartService}
+}
+
+// GetCart godoc
+// @Summary Get the cart
+// @Description Get the logged in user's cart. Prices, promotions and available stock are checked again on every read; items that cannot be checked out have a Problem and are left out of the totals.
+// @Tags cart
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} models.ApiResponse
+// @Failure 500 {object} models.ApiResponse
+// @Router /cart [get]
+func (cc *CartController) GetCart(c *gin.Context) {
+	cart, err := cc.CartService.GetCart(cartUserID(c))
+	if err != nil {
+		handleCartError(c, err, "Could not retrieve cart")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ApiResponse{
+		Status:  "success",
+		Code:    http.StatusOK,
+		Message: "Cart retrieved successfully",
+		Data:    cart,
+	})
+}
+
+// AddCartItem godoc
+// @Summary Add a product to the cart
+// @Description Add a published product to the cart, or increase its quantity if it is already in the cart. All products in the cart must use the same currency.
+// @Tags cart
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param item body models.CartItemInput true "Cart item"
+// @Success 200 {object} models.ApiResponse
+// @Failure 400 {object} models.ApiResponse
+// @Failure 404 {object} models.ApiResponse
+// @Failure 409 {object} models.ApiResponse
+// @Failure 500 {object} models.ApiResponse
+// @Router /cart/items [post]
+func (cc *CartController) AddCartItem(c *gin.Context) {
+	var input models.CartItemInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Validation failed",
+			Data:    nil,
+			Errors:  validations.FieldErrors(err),
+		})
+		return
+	}
+
+	cart, err := cc.CartService.AddItem(cartUserID(c), &input)
+	if err != nil {
+		handleCartError(c, err, "Could not add product to cart")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ApiResponse{
+		Status:  "success",
+		Code:    http.StatusOK,
+		Message: "Product added to cart successfully",
+		Data:    cart,
+	})
+}
+
+// UpdateCartItem godoc
+// @Summary Change the quantity of a cart item
+// @Description Replace the quantity of a product in the cart
+// @Tags cart
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param productId path int true "Product ID"
+// @Param item body models.CartQuantityInput true "Quantity"
+// @Success 200 {object} models.ApiResponse
+// @Failure 400 {object} models.ApiResponse
+// @Failure 404 {object} models.ApiResponse
+// @Failure 409 {object} models.ApiResponse
+// @Failure 500 {object} models.ApiResponse
+// @Router /cart/items/{productId} [put]
+func (cc *CartController) UpdateCartItem(c *gin.Context) {
+	productID, ok := parseCartProductID(c)
+	if !ok {
+		return
+	}
+
+	var input models.CartQuantityInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Validation failed",
+			Data:    nil,
+			Errors:  validations.FieldErrors(err),
+		})
+		return
+	}
+
+	cart, err := cc.CartService.UpdateItem(cartUserID(c), productID, &input)
+	if err != nil {
+		handleCartError(c, err, "Could not update cart item")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ApiResponse{
+		Status:  "success",
+		Code:    http.StatusOK,
+		Message: "Cart item updated successfully",
+		Data:    cart,
+	})
+}
+
+// RemoveCartItem godoc
+// @Summary Remove a product from the cart
+// @Description Remove a product from the cart
+// @Tags cart
+// @Security BearerAuth
+// @Param productId path int true "Product ID"
+// @Produce json
+// @Success 200 {object} models.ApiResponse
+// @Failure 400 {object} models.ApiResponse
+// @Failure 404 {object} models.ApiResponse
+// @Failure 500 {object} models.ApiResponse
+// @Router /cart/items/{productId} [delete]
+func (cc *CartController) RemoveCartItem(c *gin.Context) {
+	productID, ok := parseCartProductID(c)
+	if !ok {
+		return
+	}
+
+	cart, err := cc.CartService.RemoveItem(cartUserID(c), productID)
+	if err != nil {
+		handleCartError(c, err, "Could not remove cart item")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ApiResponse{
+		Status:  "success",
+		Code:    http.StatusOK,
+		Message: "Cart item removed successfully",
+		Data:    cart,
+	})
+}
+
+// ClearCart godoc
+// @Summary Empty the cart
+// @Description Remove every product from the cart
+// @Tags cart
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} models.ApiResponse
+// @Failure 500 {object} models.ApiResponse
+// @Router /cart [delete]
+func (cc *CartController) ClearCart(c *gin.Context) {
+	if err := cc.CartService.ClearCart(cartUserID(c)); err != nil {
+		handleCartError(c, err, "Could not empty cart")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ApiResponse{
+		Status:  "success",
+		Code:    http.StatusOK,
+		Message: "Cart emptied successfully",
+		Data:    nil,
+	})
+}
+
+// Checkout godoc
+// @Summary Check out the cart
+// @Description Turn the cart into a pending order and empty the cart. Prices and stock are checked again when the order is created, exactly as in POST /orders.
+// @Tags cart
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param checkout body models.CheckoutInput false "Checkout"
+// @Success 201 {object} models.ApiResponse
+// @Failure 400 {object} models.ApiResponse
+// @Failure 404 {object} models.ApiResponse
+// @Failure 409 {object} models.ApiResponse
+// @Failure 500 {object} models.ApiResponse
+// @Router /cart/checkout [post]
+func (cc *CartController) Checkout(c *gin.Context) {
+	var input models.CheckoutInput
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&input); err != nil {
+			c.JSON(http.StatusBadRequest, models.ApiResponse{
+				Status:  "error",
+				Code:    http.StatusBadRequest,
+				Message: "Validation failed",
+				Data:    nil,
+				Errors:  validations.FieldErrors(err),
+			})
+			return
+		}
+	}
+
+	order, err := cc.CartService.Checkout(cartUserID(c), &input)
+	if err != nil {
+		handleCartError(c, err, "Could not check out cart")
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.ApiResponse{
+		Status:  "success",
+		Code:    http.StatusCreated,
+		Message: "Order created successfully",
+		Data:    order,
+	})
+}
+
+func cartUserID(c *gin.Context) int {
+	if user := currentUser(c); user != nil {
+		return user.ID
+	}
+	return 0
+}
+
+func parseCartProductID(c *gin.Context) (int, bool) {
+	id, err := strconv.Atoi(c.Param("productId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Invalid product ID",
+			Data:    nil,
+		})
+		return 0, false
+	}
+	return id, true
+}
+
+func handleCartError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, services.ErrCartItemNotFound):
+		c.JSON(http.StatusNotFound, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusNotFound,
+			Message: "Product is not in the cart",
+			Data:    nil,
+		})
+	case errors.Is(err, services.ErrCartEmpty):
+		c.JSON(http.StatusBadRequest, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Cart is empty",
+			Data:    nil,
+		})
+	case errors.Is(err, services.ErrProductNotAvailable), errors.Is(err, services.ErrOrderCurrencyMismatch):
+		orderValidationError(c, "product_id", err)
+	default:
+		handleOrderError(c, err, fallback)
+	}
+}
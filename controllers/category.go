@@ -0,0 +1,287 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"products-api-with-jwt/models"
+	"products-api-with-jwt/services"
+	"products-api-with-jwt/validations"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CategoryController struct {
+	CategoryService *services.CategoryService
+}
+
+// NewCategoryController menginisialisasi CategoryController baru
+func NewCategoryController(categoryService *services.CategoryService) *CategoryController {
+	return &CategoryController{CategoryService: categoryService}
+}
+
+// GetCategories godoc
+// @Summary Get all categories
+// @Description Get all categories as a flat list, or as a tree of root categories with tree=true
+// @Tags categories
+// @Security BearerAuth
+// @Param tree query bool false "Return categories nested under their parents"
+// @Produce json
+// @Success 200 {object} models.ApiResponse
+// @Failure 500 {object} models.ApiResponse
+// @Router /categories [get]
+func (cc *CategoryController) GetCategories(c *gin.Context) {
+	tree, _ := strconv.ParseBool(c.Query("tree"))
+
+	categories, err := cc.CategoryService.GetAllCategories(tree)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusInternalServerError,
+			Message: "Could not retrieve categories",
+			Data:    nil,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ApiResponse{
+		Status:  "success",
+		Code:    http.StatusOK,
+		Message: "Categories retrieved successfully",
+		Data:    categories,
+		Count:   len(categories),
+	})
+}
+
+// GetCategoryByID godoc
+// @Summary Get category by ID
+// @Description Get a category with all of its sub-categories
+// @Tags categories
+// @Security BearerAuth
+// @Param id path int true "Category ID"
+// @Produce json
+// @Success 200 {object} models.ApiResponse
+// @Failure 400 {object} models.ApiResponse
+// @Failure 404 {object} models.ApiResponse
+// @Router /categories/{id} [get]
+func (cc *CategoryController) GetCategoryByID(c *gin.Context) {
+	id, ok := parseCategoryID(c)
+	if !ok {
+		return
+	}
+
+	category, err := cc.CategoryService.GetCategoryByID(id)
+	if err != nil {
+		cc.handleError(c, err, "Could not retrieve category")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ApiResponse{
+		Status:  "success",
+		Code:    http.StatusOK,
+		Message: "Category retrieved successfully",
+		Data:    category,
+	})
+}
+
+// CreateCategory godoc
+// @Summary Create a new category
+// @Description Create a new category, optionally under a parent category (admin only)
+// @Tags categories
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param category body models.CategoryInput true "Category"
+// @Success 201 {object} models.ApiResponse
+// @Failure 400 {object} models.ApiResponse
+// @Failure 403 {object} models.ApiResponse
+// @Failure 409 {object} models.ApiResponse
+// @Failure 500 {object} models.ApiResponse
+// @Router /categories [post]
+func (cc *CategoryController) CreateCategory(c *gin.Context) {
+	var input models.CategoryInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Validation failed",
+			Data:    nil,
+			Errors:  validations.FieldErrors(err),
+		})
+		return
+	}
+
+	category, err := cc.CategoryService.CreateCategory(&input)
+	if err != nil {
+		cc.handleError(c, err, "Could not create category")
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.ApiResponse{
+		Status:  "success",
+		Code:    http.StatusCreated,
+		Message: "Category created successfully",
+		Data:    category,
+	})
+}
+
+// UpdateCategory godoc
+// @Summary Update a category by ID
+// @Description Rename a category or move it under another parent (admin only). A category cannot be moved under itself or its descendants.
+// @Tags categories
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "Category ID"
+// @Param category body models.CategoryInput true "Category"
+// @Success 200 {object} models.ApiResponse
+// @Failure 400 {object} models.ApiResponse
+// @Failure 403 {object} models.ApiResponse
+// @Failure 404 {object} models.ApiResponse
+// @Failure 409 {object} models.ApiResponse
+// @Failure 500 {object} models.ApiResponse
+// @Router /categories/{id} [put]
+func (cc *CategoryController) UpdateCategory(c *gin.Context) {
+	id, ok := parseCategoryID(c)
+	if !ok {
+		return
+	}
+
+	var input models.CategoryInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Validation failed",
+			Data:    nil,
+			Errors:  validations.FieldErrors(err),
+		})
+		return
+	}
+
+	category, err := cc.CategoryService.UpdateCategory(id, &input)
+	if err != nil {
+		cc.handleError(c, err, "Could not update category")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ApiResponse{
+		Status:  "success",
+		Code:    http.StatusOK,
+		Message: "Category updated successfully",
+		Data:    category,
+	})
+}
+
+// DeleteCategory godoc
+// @Summary Delete a category by ID
+// @Description Delete a category (admin only). Sub-categories move up to the deleted category's parent. A category that still has products can only be deleted with reassign_to, which moves its products to another category. Attribute schemas of the category are deleted with it.
+// @Tags categories
+// @Security BearerAuth
+// @Param id path int true "Category ID"
+// @Param reassign_to query int false "Category that receives the products of the deleted category"
+// @Produce json
+// @Success 200 {object} models.ApiResponse
+// @Failure 400 {object} models.ApiResponse
+// @Failure 403 {object} models.ApiResponse
+// @Failure 404 {object} models.ApiResponse
+// @Failure 409 {object} models.ApiResponse
+// @Failure 500 {object} models.ApiResponse
+// @Router /categories/{id} [delete]
+func (cc *CategoryController) DeleteCategory(c *gin.Context) {
+	id, ok := parseCategoryID(c)
+	if !ok {
+		return
+	}
+
+	reassignTo := 0
+	if value := c.Query("reassign_to"); value != "" {
+		var err error
+		if reassignTo, err = strconv.Atoi(value); err != nil {
+			c.JSON(http.StatusBadRequest, models.ApiResponse{
+				Status:  "error",
+				Code:    http.StatusBadRequest,
+				Message: "Invalid reassign_to category ID",
+				Data:    nil,
+			})
+			return
+		}
+	}
+
+	if err := cc.CategoryService.DeleteCategory(id, reassignTo); err != nil {
+		cc.handleError(c, err, "Could not delete category")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ApiResponse{
+		Status:  "success",
+		Code:    http.StatusOK,
+		Message: "Category deleted successfully",
+		Data:    nil,
+	})
+}
+
+func parseCategoryID(c *gin.Context) (int, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Invalid category ID",
+			Data:    nil,
+		})
+		return 0, false
+	}
+	return id, true
+}
+
+func (cc *CategoryController) handleError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, services.ErrCategoryNotFound):
+		c.JSON(http.StatusNotFound, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusNotFound,
+			Message: "Category not found",
+			Data:    nil,
+		})
+	case errors.Is(err, services.ErrParentCategoryNotFound), errors.Is(err, services.ErrCategoryCycle):
+		c.JSON(http.StatusBadRequest, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Validation failed",
+			Data:    nil,
+			Errors: []models.FieldError{{
+				Field:   "parent_id",
+				Code:    models.ValidationInvalidValue,
+				Message: err.Error(),
+			}},
+		})
+	case errors.Is(err, services.ErrDuplicateCategoryName):
+		c.JSON(http.StatusConflict, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusConflict,
+			Message: "Validation failed",
+			Data:    nil,
+			Errors: []models.FieldError{{
+				Field:   "name",
+				Code:    models.ValidationNotUnique,
+				Message: err.Error(),
+			}},
+		})
+	case errors.Is(err, services.ErrCategoryInUse):
+		c.JSON(http.StatusConflict, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusConflict,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusInternalServerError,
+			Message: fallback,
+			Data:    nil,
+		})
+	}
+}
@@ -0,0 +1,23 @@
+package controllers
+
+import (
+	"products-api-with-jwt/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// currentUser mengambil user yang sedang login dari context (diset oleh JWTAuthMiddleware)
+func currentUser(c *gin.Context) *models.User {
+	value, exists := c.Get("user")
+	if !exists {
+		return nil
+	}
+	user, _ := value.(*models.User)
+	return user
+}
+
+// isEditor memeriksa apakah user yang sedang login boleh melihat dan mengelola produk draft
+func isEditor(c *gin.Context) bool {
+	user := currentUser(c)
+	return user != nil && user.Role == "admin"
+}
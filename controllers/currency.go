@@ -0,0 +1,325 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"products-api-with-jwt/models"
+	"products-api-with-jwt/services"
+	"products-api-with-jwt/validations"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CurrencyController struct {
+	CurrencyService *services.CurrencyService
+}
+
+// NewCurrencyController menginisialisasi CurrencyController baru
+func NewCurrencyController(currencyService *services.CurrencyService) *CurrencyController {
+	return &CurrencyController{CurrencyService: currencyService}
+}
+
+// GetExchangeRates godoc
+// @Summary Get exchange rates
+// @Description Get all exchange rates. A rate means 1 unit of the from currency is worth rate units of the to currency; the inverse is used when only the opposite direction exists.
+// @Tags currencies
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} models.ApiResponse
+// @Failure 500 {object} models.ApiResponse
+// @Router /exchange-rates [get]
+func (cc *CurrencyController) GetExchangeRates(c *gin.Context) {
+	rates, err := cc.CurrencyService.GetExchangeRates()
+	if err != nil {
+		cc.handleError(c, err, "Could not retrieve exchange rates")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ApiResponse{
+		Status:  "success",
+		Code:    http.StatusOK,
+		Message: "Exchange rates retrieved successfully",
+		Data:    rates,
+		Count:   len(rates),
+	})
+}
+
+// SetExchangeRate godoc
+// @Summary Set an exchange rate
+// @Description Create or replace the rate from one currency to another (admin only)
+// @Tags currencies
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param from path string true "ISO 4217 currency converted from"
+// @Param to path string true "ISO 4217 currency converted to"
+// @Param rate body models.ExchangeRateInput true "Exchange rate"
+// @Success 200 {object} models.ApiResponse
+// @Failure 400 {object} models.ApiResponse
+// @Failure 403 {object} models.ApiResponse
+// @Failure 500 {object} models.ApiResponse
+// @Router /exchange-rates/{from}/{to} [put]
+func (cc *CurrencyController) SetExchangeRate(c *gin.Context) {
+	from, ok := parseCurrency(c, "from")
+	if !ok {
+		return
+	}
+	to, ok := parseCurrency(c, "to")
+	if !ok {
+		return
+	}
+
+	var input models.ExchangeRateInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Validation failed",
+			Data:    nil,
+			Errors:  validations.FieldErrors(err),
+		})
+		return
+	}
+
+	userID := 0
+	if user := currentUser(c); user != nil {
+		userID = user.ID
+	}
+
+	rate, err := cc.CurrencyService.SetExchangeRate(from, to, &input, userID)
+	if err != nil {
+		cc.handleError(c, err, "Could not save exchange rate")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ApiResponse{
+		Status:  "success",
+		Code:    http.StatusOK,
+		Message: "Exchange rate saved successfully",
+		Data:    rate,
+	})
+}
+
+// DeleteExchangeRate godoc
+// @Summary Delete an exchange rate
+// @Description Delete the rate from one currency to another (admin only)
+// @Tags currencies
+// @Security BearerAuth
+// @Param from path string true "ISO 4217 currency converted from"
+// @Param to path string true "ISO 4217 currency converted to"
+// @Produce json
+// @Success 200 {object} models.ApiResponse
+// @Failure 400 {object} models.ApiResponse
+// @Failure 403 {object} models.ApiResponse
+// @Failure 404 {object} models.ApiResponse
+// @Failure 500 {object} models.ApiResponse
+// @Router /exchange-rates/{from}/{to} [delete]
+func (cc *CurrencyController) DeleteExchangeRate(c *gin.Context) {
+	from, ok := parseCurrency(c, "from")
+	if !ok {
+		return
+	}
+	to, ok := parseCurrency(c, "to")
+	if !ok {
+		return
+	}
+
+	if err := cc.CurrencyService.DeleteExchangeRate(from, to); err != nil {
+		cc.handleError(c, err, "Could not delete exchange rate")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ApiResponse{
+		Status:  "success",
+		Code:    http.StatusOK,
+		Message: "Exchange rate deleted successfully",
+		Data:    nil,
+	})
+}
+
+// GetCurrencyPrices godoc
+// @Summary Get per-currency prices of a product
+// @Description Get the fixed prices of a product in other currencies. These take precedence over converted prices.
+// @Tags currencies
+// @Security BearerAuth
+// @Param id path int true "Product ID"
+// @Produce json
+// @Success 200 {object} models.ApiResponse
+// @Failure 400 {object} models.ApiResponse
+// @Failure 404 {object} models.ApiResponse
+// @Failure 500 {object} models.ApiResponse
+// @Router /products/{id}/currency-prices [get]
+func (cc *CurrencyController) GetCurrencyPrices(c *gin.Context) {
+	productID, ok := parseProductID(c)
+	if !ok {
+		return
+	}
+
+	prices, err := cc.CurrencyService.GetCurrencyPrices(productID)
+	if err != nil {
+		cc.handleError(c, err, "Could not retrieve currency prices")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ApiResponse{
+		Status:  "success",
+		Code:    http.StatusOK,
+		Message: "Currency prices retrieved successfully",
+		Data:    prices,
+		Count:   len(prices),
+	})
+}
+
+// SetCurrencyPrice godoc
+// @Summary Set a per-currency price of a product
+// @Description Create or replace the fixed price of a product in a currency other than its own. The amount is rounded half-up to the currency's minor unit.
+// @Tags currencies
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "Product ID"
+// @Param currency path string true "ISO 4217 currency"
+// @Param price body models.CurrencyPriceInput true "Price"
+// @Success 200 {object} models.ApiResponse
+// @Failure 400 {object} models.ApiResponse
+// @Failure 404 {object} models.ApiResponse
+// @Failure 500 {object} models.ApiResponse
+// @Router /products/{id}/currency-prices/{currency} [put]
+func (cc *CurrencyController) SetCurrencyPrice(c *gin.Context) {
+	productID, ok := parseProductID(c)
+	if !ok {
+		return
+	}
+	currency, ok := parseCurrency(c, "currency")
+	if !ok {
+		return
+	}
+
+	var input models.CurrencyPriceInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Validation failed",
+			Data:    nil,
+			Errors:  validations.FieldErrors(err),
+		})
+		return
+	}
+
+	price, err := cc.CurrencyService.SetCurrencyPrice(productID, currency, &input)
+	if err != nil {
+		cc.handleError(c, err, "Could not save currency price")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ApiResponse{
+		Status:  "success",
+		Code:    http.StatusOK,
+		Message: "Currency price saved successfully",
+		Data:    price,
+	})
+}
+
+// DeleteCurrencyPrice godoc
+// @Summary Delete a per-currency price of a product
+// @Description Delete the fixed price of a product in a currency, so its price is converted with the exchange rate again
+// @Tags currencies
+// @Security BearerAuth
+// @Param id path int true "Product ID"
+// @Param currency path string true "ISO 4217 currency"
+// @Produce json
+// @Success 200 {object} models.ApiResponse
+// @Failure 400 {object} models.ApiResponse
+// @Failure 404 {object} models.ApiResponse
+// @Failure 500 {object} models.ApiResponse
+// @Router /products/{id}/currency-prices/{currency} [delete]
+func (cc *CurrencyController) DeleteCurrencyPrice(c *gin.Context) {
+	productID, ok := parseProductID(c)
+	if !ok {
+		return
+	}
+	currency, ok := parseCurrency(c, "currency")
+	if !ok {
+		return
+	}
+
+	if err := cc.CurrencyService.DeleteCurrencyPrice(productID, currency); err != nil {
+		cc.handleError(c, err, "Could not delete currency price")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ApiResponse{
+		Status:  "success",
+		Code:    http.StatusOK,
+		Message: "Currency price deleted successfully",
+		Data:    nil,
+	})
+}
+
+func (cc *CurrencyController) handleError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, services.ErrProductNotFound):
+		c.JSON(http.StatusNotFound, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusNotFound,
+			Message: "Product not found",
+			Data:    nil,
+		})
+	case errors.Is(err, services.ErrExchangeRateNotFound):
+		c.JSON(http.StatusNotFound, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusNotFound,
+			Message: "Exchange rate not found",
+			Data:    nil,
+		})
+	case errors.Is(err, services.ErrCurrencyPriceNotFound):
+		c.JSON(http.StatusNotFound, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusNotFound,
+			Message: "Currency price not found",
+			Data:    nil,
+		})
+	case errors.Is(err, services.ErrSameCurrency):
+		c.JSON(http.StatusBadRequest, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Validation failed",
+			Data:    nil,
+			Errors: []models.FieldError{{
+				Field:   "currency",
+				Code:    models.ValidationInvalidValue,
+				Message: err.Error(),
+			}},
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusInternalServerError,
+			Message: fallback,
+			Data:    nil,
+		})
+	}
+}
+
+// parseCurrency membaca kode mata uang dari path parameter dan memastikan didukung
+func parseCurrency(c *gin.Context, param string) (string, bool) {
+	currency := strings.ToUpper(c.Param(param))
+	if _, ok := models.CurrencyExponents[currency]; !ok {
+		c.JSON(http.StatusBadRequest, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Validation failed",
+			Data:    nil,
+			Errors: []models.FieldError{{
+				Field:   param,
+				Code:    models.ValidationInvalidValue,
+				Message: "must be one of: " + strings.Join(models.SupportedCurrencies(), " "),
+			}},
+		})
+		return "", false
+	}
+	return currency, true
+}
@@ -0,0 +1,327 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"products-api-with-jwt/models"
+	"products-api-with-jwt/services"
+	"products-api-with-jwt/validations"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ImageController struct {
+	ImageService *services.ImageService
+}
+
+// NewImageController menginisialisasi ImageController baru
+func NewImageController(imageService *services.ImageService) *ImageController {
+	return &ImageController{ImageService: imageService}
+}
+
+// GetProductImages godoc
+// @Summary Get product images
+// @Description Get the images of a product in display order. URL and ThumbnailURL are signed and expire after IMAGE_URL_TTL.
+// @Tags images
+// @Security BearerAuth
+// @Param id path int true "Product ID"
+// @Produce json
+// @Success 200 {object} models.ApiResponse
+// @Failure 400 {object} models.ApiResponse
+// @Failure 404 {object} models.ApiResponse
+// @Failure 500 {object} models.ApiResponse
+// @Router /products/{id}/images [get]
+func (ic *ImageController) GetProductImages(c *gin.Context) {
+	productID, ok := parseProductID(c)
+	if !ok {
+		return
+	}
+
+	images, err := ic.ImageService.GetImages(productID)
+	if err != nil {
+		ic.handleError(c, err, "Could not retrieve images")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ApiResponse{
+		Status:  "success",
+		Code:    http.StatusOK,
+		Message: "Images retrieved successfully",
+		Data:    images,
+		Count:   len(images),
+	})
+}
+
+// UploadProductImage godoc
+// @Summary Upload a product image
+// @Description Upload a JPEG, PNG or GIF image (at most IMAGE_MAX_SIZE bytes). A thumbnail is generated automatically. The first image of a product becomes its primary image.
+// @Tags images
+// @Security BearerAuth
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path int true "Product ID"
+// @Param image formData file true "Image file"
+// @Param is_primary formData bool false "Make this the primary image"
+// @Success 201 {object} models.ApiResponse
+// @Failure 400 {object} models.ApiResponse
+// @Failure 404 {object} models.ApiResponse
+// @Failure 413 {object} models.ApiResponse
+// @Failure 415 {object} models.ApiResponse
+// @Failure 500 {object} models.ApiResponse
+// @Router /products/{id}/images [post]
+func (ic *ImageController) UploadProductImage(c *gin.Context) {
+	productID, ok := parseProductID(c)
+	if !ok {
+		return
+	}
+
+	// Batasi body request agar upload yang terlalu besar tidak dibaca seluruhnya
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, ic.ImageService.MaxSize+1<<20)
+	file, err := c.FormFile("image")
+	if err != nil {
+		var maxBytesError *http.MaxBytesError
+		if errors.As(err, &maxBytesError) {
+			ic.handleError(c, services.ErrImageTooLarge, "")
+			return
+		}
+		c.JSON(http.StatusBadRequest, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Validation failed",
+			Data:    nil,
+			Errors: []models.FieldError{{
+				Field:   "image",
+				Code:    models.ValidationRequired,
+				Message: "is required",
+			}},
+		})
+		return
+	}
+	primary, _ := strconv.ParseBool(c.PostForm("is_primary"))
+
+	src, err := file.Open()
+	if err != nil {
+		ic.handleError(c, err, "Could not read uploaded image")
+		return
+	}
+	defer src.Close()
+
+	image, err := ic.ImageService.UploadImage(productID, file.Filename, src, primary)
+	if err != nil {
+		ic.handleError(c, err, "Could not upload image")
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.ApiResponse{
+		Status:  "success",
+		Code:    http.StatusCreated,
+		Message: "Image uploaded successfully",
+		Data:    image,
+	})
+}
+
+// UpdateProductImage godoc
+// @Summary Reorder a product image or make it primary
+// @Description Move an image to another position and/or mark it as the primary image. Other images are renumbered.
+// @Tags images
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "Product ID"
+// @Param imageId path int true "Image ID"
+// @Param image body models.UpdateImageInput true "Image"
+// @Success 200 {object} models.ApiResponse
+// @Failure 400 {object} models.ApiResponse
+// @Failure 404 {object} models.ApiResponse
+// @Failure 500 {object} models.ApiResponse
+// @Router /products/{id}/images/{imageId} [put]
+func (ic *ImageController) UpdateProductImage(c *gin.Context) {
+	productID, imageID, ok := parseImageIDs(c)
+	if !ok {
+		return
+	}
+
+	var input models.UpdateImageInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Validation failed",
+			Data:    nil,
+			Errors:  validations.FieldErrors(err),
+		})
+		return
+	}
+
+	image, err := ic.ImageService.UpdateImage(productID, imageID, &input)
+	if err != nil {
+		ic.handleError(c, err, "Could not update image")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ApiResponse{
+		Status:  "success",
+		Code:    http.StatusOK,
+		Message: "Image updated successfully",
+		Data:    image,
+	})
+}
+
+// DeleteProductImage godoc
+// @Summary Delete a product image
+// @Description Delete an image and its files. If it was the primary image, the next image becomes primary.
+// @Tags images
+// @Security BearerAuth
+// @Param id path int true "Product ID"
+// @Param imageId path int true "Image ID"
+// @Produce json
+// @Success 200 {object} models.ApiResponse
+// @Failure 400 {object} models.ApiResponse
+// @Failure 404 {object} models.ApiResponse
+// @Failure 500 {object} models.ApiResponse
+// @Router /products/{id}/images/{imageId} [delete]
+func (ic *ImageController) DeleteProductImage(c *gin.Context) {
+	productID, imageID, ok := parseImageIDs(c)
+	if !ok {
+		return
+	}
+
+	if err := ic.ImageService.DeleteImage(productID, imageID); err != nil {
+		ic.handleError(c, err, "Could not delete image")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ApiResponse{
+		Status:  "success",
+		Code:    http.StatusOK,
+		Message: "Image deleted successfully",
+		Data:    nil,
+	})
+}
+
+// ServeImage godoc
+// @Summary Download an image
+// @Description Serve an image or its thumbnail through a signed URL returned by the image endpoints. No JWT is needed; the signature and expiry are checked instead.
+// @Tags images
+// @Param imageId path int true "Image ID"
+// @Param version path string true "Image version" Enums(original, thumbnail)
+// @Param expires query int true "Expiry as a Unix timestamp"
+// @Param signature query string true "URL signature"
+// @Produce image/jpeg,image/png,image/gif
+// @Success 200 {file} binary
+// @Failure 403 {object} models.ApiResponse
+// @Failure 404 {object} models.ApiResponse
+// @Router /images/{imageId}/{version} [get]
+func (ic *ImageController) ServeImage(c *gin.Context) {
+	imageID, err := strconv.Atoi(c.Param("imageId"))
+	version := c.Param("version")
+	if err != nil || (version != services.ImageOriginal && version != services.ImageThumbnail) {
+		c.JSON(http.StatusNotFound, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusNotFound,
+			Message: "Image not found",
+			Data:    nil,
+		})
+		return
+	}
+	expires, _ := strconv.ParseInt(c.Query("expires"), 10, 64)
+
+	file, contentType, err := ic.ImageService.OpenImage(imageID, version, expires, c.Query("signature"))
+	if err != nil {
+		ic.handleError(c, err, "Could not open image")
+		return
+	}
+	defer file.Close()
+
+	c.Header("Cache-Control", "private, max-age=300")
+	c.DataFromReader(http.StatusOK, -1, contentType, file, nil)
+}
+
+func (ic *ImageController) handleError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, services.ErrProductNotFound):
+		c.JSON(http.StatusNotFound, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusNotFound,
+			Message: "Product not found",
+			Data:    nil,
+		})
+	case errors.Is(err, services.ErrImageNotFound):
+		c.JSON(http.StatusNotFound, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusNotFound,
+			Message: "Image not found",
+			Data:    nil,
+		})
+	case errors.Is(err, services.ErrInvalidImageSignature):
+		c.JSON(http.StatusForbidden, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusForbidden,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	case errors.Is(err, services.ErrImageTooLarge):
+		c.JSON(http.StatusRequestEntityTooLarge, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusRequestEntityTooLarge,
+			Message: "Validation failed",
+			Data:    nil,
+			Errors: []models.FieldError{{
+				Field:   "image",
+				Code:    models.ValidationOutOfRange,
+				Message: err.Error(),
+			}},
+		})
+	case errors.Is(err, services.ErrUnsupportedImageType):
+		c.JSON(http.StatusUnsupportedMediaType, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusUnsupportedMediaType,
+			Message: "Validation failed",
+			Data:    nil,
+			Errors: []models.FieldError{{
+				Field:   "image",
+				Code:    models.ValidationInvalidType,
+				Message: err.Error(),
+			}},
+		})
+	case errors.Is(err, services.ErrInvalidImage):
+		c.JSON(http.StatusBadRequest, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Validation failed",
+			Data:    nil,
+			Errors: []models.FieldError{{
+				Field:   "image",
+				Code:    models.ValidationInvalidValue,
+				Message: err.Error(),
+			}},
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusInternalServerError,
+			Message: fallback,
+			Data:    nil,
+		})
+	}
+}
+
+func parseImageIDs(c *gin.Context) (int, int, bool) {
+	productID, ok := parseProductID(c)
+	if !ok {
+		return 0, 0, false
+	}
+	imageID, err := strconv.Atoi(c.Param("imageId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Invalid image ID",
+			Data:    nil,
+		})
+		return 0, 0, false
+	}
+	return productID, imageID, true
+}
@@ -0,0 +1,296 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"slices"
+	"strconv"
+
+	"products-api-with-jwt/models"
+	"products-api-with-jwt/services"
+	"products-api-with-jwt/validations"
+
+	"github.com/gin-gonic/gin"
+)
+
+var orderStatuses = []string{
+	models.OrderPending, models.OrderPaid, models.OrderShipped, models.OrderCancelled, models.OrderRefunded,
+}
+
+type OrderController struct {
+	OrderService *services.OrderService
+}
+
+// NewOrderController menginisialisasi OrderController baru
+func NewOrderController(orderService *services.OrderService) *OrderController {
+	return &OrderController{OrderService: orderService}
+}
+
+// GetOrders godoc
+// @Summary Get orders
+// @Description Get orders, newest first. Users see their own orders; admins see all orders and can filter them by user_id.
+// @Tags orders
+// @Security BearerAuth
+// @Param status query string false "Status" Enums(pending, paid, shipped, cancelled, refunded)
+// @Param user_id query int false "User ID (admin only)"
+// @Produce json
+// @Success 200 {object} models.ApiResponse
+// @Failure 400 {object} models.ApiResponse
+// @Failure 500 {object} models.ApiResponse
+// @Router /orders [get]
+func (oc *OrderController) GetOrders(c *gin.Context) {
+	status := c.Query("status")
+	if status != "" && !slices.Contains(orderStatuses, status) {
+		orderValidationError(c, "status", errors.New("status must be one of pending, paid, shipped, cancelled, refunded"))
+		return
+	}
+
+	userID := orderOwnerID(c)
+	if value := c.Query("user_id"); value != "" && isEditor(c) {
+		var err error
+		if userID, err = strconv.Atoi(value); err != nil {
+			orderValidationError(c, "user_id", errors.New("user_id must be a number"))
+			return
+		}
+	}
+
+	orders, err := oc.OrderService.GetOrders(userID, status)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusInternalServerError,
+			Message: "Could not retrieve orders",
+			Data:    nil,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ApiResponse{
+		Status:  "success",
+		Code:    http.StatusOK,
+		Message: "Orders retrieved successfully",
+		Data:    orders,
+		Count:   len(orders),
+	})
+}
+
+// GetOrderByID godoc
+// @Summary Get order by ID
+// @Description Get an order with its lines. Users can only get their own orders.
+// @Tags orders
+// @Security BearerAuth
+// @Param id path int true "Order ID"
+// @Produce json
+// @Success 200 {object} models.ApiResponse
+// @Failure 400 {object} models.ApiResponse
+// @Failure 404 {object} models.ApiResponse
+// @Router /orders/{id} [get]
+func (oc *OrderController) GetOrderByID(c *gin.Context) {
+	id, ok := parseOrderID(c)
+	if !ok {
+		return
+	}
+
+	order, err := oc.OrderService.GetOrderByID(id, orderOwnerID(c))
+	if err != nil {
+		handleOrderError(c, err, "Could not retrieve order")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ApiResponse{
+		Status:  "success",
+		Code:    http.StatusOK,
+		Message: "Order retrieved successfully",
+		Data:    order,
+	})
+}
+
+// CreateOrder godoc
+// @Summary Create a new order
+// @Description Create a pending order for the logged in user. Prices, promotions and tax for the region are copied into the lines at order time, and stock is deducted in the same transaction as a sale with reference order:<id>. Returns 409 if any product has too little available stock.
+// @Tags orders
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param order body models.OrderInput true "Order"
+// @Success 201 {object} models.ApiResponse
+// @Failure 400 {object} models.ApiResponse
+// @Failure 404 {object} models.ApiResponse
+// @Failure 409 {object} models.ApiResponse
+// @Failure 500 {object} models.ApiResponse
+// @Router /orders [post]
+func (oc *OrderController) CreateOrder(c *gin.Context) {
+	var input models.OrderInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Validation failed",
+			Data:    nil,
+			Errors:  validations.FieldErrors(err),
+		})
+		return
+	}
+
+	userID := 0
+	if user := currentUser(c); user != nil {
+		userID = user.ID
+	}
+
+	order, err := oc.OrderService.CreateOrder(&input, userID)
+	if err != nil {
+		handleOrderError(c, err, "Could not create order")
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.ApiResponse{
+		Status:  "success",
+		Code:    http.StatusCreated,
+		Message: "Order created successfully",
+		Data:    order,
+	})
+}
+
+// ChangeOrderStatus godoc
+// @Summary Change the status of an order
+// @Description Move an order to its next status: pending to paid or cancelled, paid to shipped or refunded, shipped to refunded. Users can only cancel their own pending orders; other changes are admin only. Cancelling, or refunding before shipping, returns the stock.
+// @Tags orders
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "Order ID"
+// @Param status body models.OrderStatusInput true "New status"
+// @Success 200 {object} models.ApiResponse
+// @Failure 400 {object} models.ApiResponse
+// @Failure 403 {object} models.ApiResponse
+// @Failure 404 {object} models.ApiResponse
+// @Failure 409 {object} models.ApiResponse
+// @Failure 500 {object} models.ApiResponse
+// @Router /orders/{id}/status [put]
+func (oc *OrderController) ChangeOrderStatus(c *gin.Context) {
+	id, ok := parseOrderID(c)
+	if !ok {
+		return
+	}
+
+	var input models.OrderStatusInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Validation failed",
+			Data:    nil,
+			Errors:  validations.FieldErrors(err),
+		})
+		return
+	}
+
+	userID := 0
+	if user := currentUser(c); user != nil {
+		userID = user.ID
+	}
+
+	order, err := oc.OrderService.ChangeOrderStatus(id, input.Status, userID, isEditor(c))
+	if err != nil {
+		handleOrderError(c, err, "Could not change order status")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ApiResponse{
+		Status:  "success",
+		Code:    http.StatusOK,
+		Message: "Order status changed successfully",
+		Data:    order,
+	})
+}
+
+// orderOwnerID mengembalikan ID user yang order-nya boleh dilihat, 0 untuk admin yang boleh melihat semua order
+func orderOwnerID(c *gin.Context) int {
+	if isEditor(c) {
+		return 0
+	}
+	if user := currentUser(c); user != nil {
+		return user.ID
+	}
+	return -1
+}
+
+func parseOrderID(c *gin.Context) (int, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Invalid order ID",
+			Data:    nil,
+		})
+		return 0, false
+	}
+	return id, true
+}
+
+func handleOrderError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, services.ErrOrderNotFound):
+		c.JSON(http.StatusNotFound, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusNotFound,
+			Message: "Order not found",
+			Data:    nil,
+		})
+	case errors.Is(err, services.ErrProductNotFound):
+		c.JSON(http.StatusNotFound, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusNotFound,
+			Message: "Product not found",
+			Data:    nil,
+		})
+	case errors.Is(err, services.ErrInsufficientStock):
+		c.JSON(http.StatusConflict, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusConflict,
+			Message: "Insufficient stock",
+			Data:    nil,
+		})
+	case errors.Is(err, services.ErrInvalidOrderTransition):
+		c.JSON(http.StatusConflict, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusConflict,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	case errors.Is(err, services.ErrOrderStatusForbidden):
+		c.JSON(http.StatusForbidden, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusForbidden,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	case errors.Is(err, services.ErrProductNotAvailable), errors.Is(err, services.ErrOrderCurrencyMismatch),
+		errors.Is(err, services.ErrDuplicateOrderLine):
+		orderValidationError(c, "lines", err)
+	case errors.Is(err, services.ErrInvalidTaxRegion):
+		orderValidationError(c, "region", err)
+	default:
+		c.JSON(http.StatusInternalServerError, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusInternalServerError,
+			Message: fallback,
+			Data:    nil,
+		})
+	}
+}
+
+func orderValidationError(c *gin.Context, field string, err error) {
+	c.JSON(http.StatusBadRequest, models.ApiResponse{
+		Status:  "error",
+		Code:    http.StatusBadRequest,
+		Message: "Validation failed",
+		Data:    nil,
+		Errors: []models.FieldError{{
+			Field:   field,
+			Code:    models.ValidationInvalidValue,
+			Message: err.Error(),
+		}},
+	})
+}
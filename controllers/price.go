@@ -0,0 +1,234 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"products-api-with-jwt/models"
+	"products-api-with-jwt/services"
+	"products-api-with-jwt/validations"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PriceController struct {
+	PriceService *services.PriceService
+}
+
+// NewPriceController menginisialisasi PriceController baru
+func NewPriceController(priceService *services.PriceService) *PriceController {
+	return &PriceController{PriceService: priceService}
+}
+
+// GetPriceHistory godoc
+// @Summary Get price history
+// @Description Get every price change of a product, newest first, with who made it (UserID 0 is the scheduler) and its source
+// @Tags prices
+// @Security BearerAuth
+// @Param id path int true "Product ID"
+// @Produce json
+// @Success 200 {object} models.ApiResponse
+// @Failure 400 {object} models.ApiResponse
+// @Failure 404 {object} models.ApiResponse
+// @Failure 500 {object} models.ApiResponse
+// @Router /products/{id}/prices [get]
+func (pc *PriceController) GetPriceHistory(c *gin.Context) {
+	productID, ok := parseProductID(c)
+	if !ok {
+		return
+	}
+
+	changes, err := pc.PriceService.GetPriceHistory(productID)
+	if err != nil {
+		pc.handleError(c, err, "Could not retrieve price history")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ApiResponse{
+		Status:  "success",
+		Code:    http.StatusOK,
+		Message: "Price history retrieved successfully",
+		Data:    changes,
+		Count:   len(changes),
+	})
+}
+
+// GetScheduledPrices godoc
+// @Summary Get scheduled prices
+// @Description Get all scheduled prices of a product, including completed and cancelled ones
+// @Tags prices
+// @Security BearerAuth
+// @Param id path int true "Product ID"
+// @Produce json
+// @Success 200 {object} models.ApiResponse
+// @Failure 400 {object} models.ApiResponse
+// @Failure 404 {object} models.ApiResponse
+// @Failure 500 {object} models.ApiResponse
+// @Router /products/{id}/prices/scheduled [get]
+func (pc *PriceController) GetScheduledPrices(c *gin.Context) {
+	productID, ok := parseProductID(c)
+	if !ok {
+		return
+	}
+
+	schedules, err := pc.PriceService.GetScheduledPrices(productID)
+	if err != nil {
+		pc.handleError(c, err, "Could not retrieve scheduled prices")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ApiResponse{
+		Status:  "success",
+		Code:    http.StatusOK,
+		Message: "Scheduled prices retrieved successfully",
+		Data:    schedules,
+		Count:   len(schedules),
+	})
+}
+
+// SchedulePrice godoc
+// @Summary Schedule a price change
+// @Description Schedule a price from effective_from until effective_to (optional). When effective_to passes, the previous price is restored. Returns 409 when the window overlaps another pending or active schedule.
+// @Tags prices
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "Product ID"
+// @Param schedule body models.ScheduledPriceInput true "Scheduled price"
+// @Success 201 {object} models.ApiResponse
+// @Failure 400 {object} models.ApiResponse
+// @Failure 404 {object} models.ApiResponse
+// @Failure 409 {object} models.ApiResponse
+// @Failure 500 {object} models.ApiResponse
+// @Router /products/{id}/prices/scheduled [post]
+func (pc *PriceController) SchedulePrice(c *gin.Context) {
+	productID, ok := parseProductID(c)
+	if !ok {
+		return
+	}
+
+	var input models.ScheduledPriceInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Validation failed",
+			Data:    nil,
+			Errors:  validations.FieldErrors(err),
+		})
+		return
+	}
+
+	userID := 0
+	if user := currentUser(c); user != nil {
+		userID = user.ID
+	}
+
+	schedule, err := pc.PriceService.SchedulePrice(productID, &input, userID)
+	if err != nil {
+		pc.handleError(c, err, "Could not schedule price")
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.ApiResponse{
+		Status:  "success",
+		Code:    http.StatusCreated,
+		Message: "Price scheduled successfully",
+		Data:    schedule,
+	})
+}
+
+// CancelScheduledPrice godoc
+// @Summary Cancel a scheduled price
+// @Description Cancel a pending or active scheduled price. Cancelling an active schedule restores the previous price unless the price was changed manually in the meantime.
+// @Tags prices
+// @Security BearerAuth
+// @Param id path int true "Product ID"
+// @Param scheduleId path int true "Scheduled price ID"
+// @Produce json
+// @Success 200 {object} models.ApiResponse
+// @Failure 400 {object} models.ApiResponse
+// @Failure 404 {object} models.ApiResponse
+// @Failure 409 {object} models.ApiResponse
+// @Failure 500 {object} models.ApiResponse
+// @Router /products/{id}/prices/scheduled/{scheduleId} [delete]
+func (pc *PriceController) CancelScheduledPrice(c *gin.Context) {
+	productID, ok := parseProductID(c)
+	if !ok {
+		return
+	}
+	scheduleID, err := strconv.Atoi(c.Param("scheduleId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Invalid scheduled price ID",
+			Data:    nil,
+		})
+		return
+	}
+
+	userID := 0
+	if user := currentUser(c); user != nil {
+		userID = user.ID
+	}
+
+	schedule, err := pc.PriceService.CancelScheduledPrice(productID, scheduleID, userID)
+	if err != nil {
+		pc.handleError(c, err, "Could not cancel scheduled price")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ApiResponse{
+		Status:  "success",
+		Code:    http.StatusOK,
+		Message: "Scheduled price cancelled successfully",
+		Data:    schedule,
+	})
+}
+
+func (pc *PriceController) handleError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, services.ErrProductNotFound):
+		c.JSON(http.StatusNotFound, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusNotFound,
+			Message: "Product not found",
+			Data:    nil,
+		})
+	case errors.Is(err, services.ErrScheduledPriceNotFound):
+		c.JSON(http.StatusNotFound, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusNotFound,
+			Message: "Scheduled price not found",
+			Data:    nil,
+		})
+	case errors.Is(err, services.ErrScheduledPriceOverlap):
+		c.JSON(http.StatusConflict, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusConflict,
+			Message: "Validation failed",
+			Data:    nil,
+			Errors: []models.FieldError{{
+				Field:   "effective_from",
+				Code:    models.ValidationInvalidValue,
+				Message: err.Error(),
+			}},
+		})
+	case errors.Is(err, services.ErrScheduledPriceFinished):
+		c.JSON(http.StatusConflict, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusConflict,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusInternalServerError,
+			Message: fallback,
+			Data:    nil,
+		})
+	}
+}
@@ -0,0 +1,509 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"products-api-with-jwt/models"
+	"products-api-with-jwt/services"
+	"products-api-with-jwt/validations"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PriceListController struct {
+	PriceListService *services.PriceListService
+}
+
+// NewPriceListController menginisialisasi PriceListController baru
+func NewPriceListController(priceListService *services.PriceListService) *PriceListController {
+	return &PriceListController{PriceListService: priceListService}
+}
+
+// GetPriceLists godoc
+// @Summary Get all price lists
+// @Description Get all customer-group price lists with their assignments and rules (admin only)
+// @Tags price-lists
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} models.ApiResponse
+// @Failure 403 {object} models.ApiResponse
+// @Failure 500 {object} models.ApiResponse
+// @Router /price-lists [get]
+func (pc *PriceListController) GetPriceLists(c *gin.Context) {
+	priceLists, err := pc.PriceListService.GetAllPriceLists()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusInternalServerError,
+			Message: "Could not retrieve price lists",
+			Data:    nil,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ApiResponse{
+		Status:  "success",
+		Code:    http.StatusOK,
+		Message: "Price lists retrieved successfully",
+		Data:    priceLists,
+		Count:   len(priceLists),
+	})
+}
+
+// GetPriceListByID godoc
+// @Summary Get price list by ID
+// @Description Get a price list with its assignments and rules (admin only)
+// @Tags price-lists
+// @Security BearerAuth
+// @Param id path int true "Price list ID"
+// @Produce json
+// @Success 200 {object} models.ApiResponse
+// @Failure 400 {object} models.ApiResponse
+// @Failure 403 {object} models.ApiResponse
+// @Failure 404 {object} models.ApiResponse
+// @Router /price-lists/{id} [get]
+func (pc *PriceListController) GetPriceListByID(c *gin.Context) {
+	id, ok := parsePriceListID(c)
+	if !ok {
+		return
+	}
+
+	priceList, err := pc.PriceListService.GetPriceListByID(id)
+	if err != nil {
+		handlePriceListError(c, err, "Could not retrieve price list")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ApiResponse{
+		Status:  "success",
+		Code:    http.StatusOK,
+		Message: "Price list retrieved successfully",
+		Data:    priceList,
+	})
+}
+
+// CreatePriceList godoc
+// @Summary Create a price list
+// @Description Create a price list and assign it to customer groups and departments (admin only). A customer group or department can only be assigned to one price list; a user's customer group takes precedence over their department.
+// @Tags price-lists
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param priceList body models.PriceListInput true "Price list"
+// @Success 201 {object} models.ApiResponse
+// @Failure 400 {object} models.ApiResponse
+// @Failure 403 {object} models.ApiResponse
+// @Failure 409 {object} models.ApiResponse
+// @Failure 500 {object} models.ApiResponse
+// @Router /price-lists [post]
+func (pc *PriceListController) CreatePriceList(c *gin.Context) {
+	var input models.PriceListInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Validation failed",
+			Data:    nil,
+			Errors:  validations.FieldErrors(err),
+		})
+		return
+	}
+
+	priceList, err := pc.PriceListService.CreatePriceList(&input)
+	if err != nil {
+		handlePriceListError(c, err, "Could not create price list")
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.ApiResponse{
+		Status:  "success",
+		Code:    http.StatusCreated,
+		Message: "Price list created successfully",
+		Data:    priceList,
+	})
+}
+
+// UpdatePriceList godoc
+// @Summary Update a price list by ID
+// @Description Replace the name, status and assignments of a price list (admin only). Rules are kept.
+// @Tags price-lists
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "Price list ID"
+// @Param priceList body models.PriceListInput true "Price list"
+// @Success 200 {object} models.ApiResponse
+// @Failure 400 {object} models.ApiResponse
+// @Failure 403 {object} models.ApiResponse
+// @Failure 404 {object} models.ApiResponse
+// @Failure 409 {object} models.ApiResponse
+// @Failure 500 {object} models.ApiResponse
+// @Router /price-lists/{id} [put]
+func (pc *PriceListController) UpdatePriceList(c *gin.Context) {
+	id, ok := parsePriceListID(c)
+	if !ok {
+		return
+	}
+
+	var input models.PriceListInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Validation failed",
+			Data:    nil,
+			Errors:  validations.FieldErrors(err),
+		})
+		return
+	}
+
+	priceList, err := pc.PriceListService.UpdatePriceList(id, &input)
+	if err != nil {
+		handlePriceListError(c, err, "Could not update price list")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ApiResponse{
+		Status:  "success",
+		Code:    http.StatusOK,
+		Message: "Price list updated successfully",
+		Data:    priceList,
+	})
+}
+
+// DeletePriceList godoc
+// @Summary Delete a price list by ID
+// @Description Delete a price list with its assignments and rules (admin only). Orders keep the prices they were created with.
+// @Tags price-lists
+// @Security BearerAuth
+// @Param id path int true "Price list ID"
+// @Produce json
+// @Success 200 {object} models.ApiResponse
+// @Failure 400 {object} models.ApiResponse
+// @Failure 403 {object} models.ApiResponse
+// @Failure 404 {object} models.ApiResponse
+// @Failure 500 {object} models.ApiResponse
+// @Router /price-lists/{id} [delete]
+func (pc *PriceListController) DeletePriceList(c *gin.Context) {
+	id, ok := parsePriceListID(c)
+	if !ok {
+		return
+	}
+
+	if err := pc.PriceListService.DeletePriceList(id); err != nil {
+		handlePriceListError(c, err, "Could not delete price list")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ApiResponse{
+		Status:  "success",
+		Code:    http.StatusOK,
+		Message: "Price list deleted successfully",
+		Data:    nil,
+	})
+}
+
+// AddPriceListRule godoc
+// @Summary Add a price list rule
+// @Description Add a fixed unit price for a product, or a percentage off the catalog price of a product or category (including sub-categories), from min_quantity units (admin only). Product rules take precedence over category rules, then the highest min_quantity that the quantity reaches.
+// @Tags price-lists
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "Price list ID"
+// @Param rule body models.PriceListRuleInput true "Rule"
+// @Success 201 {object} models.ApiResponse
+// @Failure 400 {object} models.ApiResponse
+// @Failure 403 {object} models.ApiResponse
+// @Failure 404 {object} models.ApiResponse
+// @Failure 409 {object} models.ApiResponse
+// @Failure 500 {object} models.ApiResponse
+// @Router /price-lists/{id}/rules [post]
+func (pc *PriceListController) AddPriceListRule(c *gin.Context) {
+	id, ok := parsePriceListID(c)
+	if !ok {
+		return
+	}
+
+	var input models.PriceListRuleInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Validation failed",
+			Data:    nil,
+			Errors:  validations.FieldErrors(err),
+		})
+		return
+	}
+
+	rule, err := pc.PriceListService.AddRule(id, &input)
+	if err != nil {
+		handlePriceListError(c, err, "Could not add price list rule")
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.ApiResponse{
+		Status:  "success",
+		Code:    http.StatusCreated,
+		Message: "Price list rule added successfully",
+		Data:    rule,
+	})
+}
+
+// UpdatePriceListRule godoc
+// @Summary Update a price list rule
+// @Description Replace the target, tier and price of a price list rule (admin only)
+// @Tags price-lists
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "Price list ID"
+// @Param ruleId path int true "Rule ID"
+// @Param rule body models.PriceListRuleInput true "Rule"
+// @Success 200 {object} models.ApiResponse
+// @Failure 400 {object} models.ApiResponse
+// @Failure 403 {object} models.ApiResponse
+// @Failure 404 {object} models.ApiResponse
+// @Failure 409 {object} models.ApiResponse
+// @Failure 500 {object} models.ApiResponse
+// @Router /price-lists/{id}/rules/{ruleId} [put]
+func (pc *PriceListController) UpdatePriceListRule(c *gin.Context) {
+	id, ok := parsePriceListID(c)
+	if !ok {
+		return
+	}
+	ruleID, ok := parsePriceListRuleID(c)
+	if !ok {
+		return
+	}
+
+	var input models.PriceListRuleInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Validation failed",
+			Data:    nil,
+			Errors:  validations.FieldErrors(err),
+		})
+		return
+	}
+
+	rule, err := pc.PriceListService.UpdateRule(id, ruleID, &input)
+	if err != nil {
+		handlePriceListError(c, err, "Could not update price list rule")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ApiResponse{
+		Status:  "success",
+		Code:    http.StatusOK,
+		Message: "Price list rule updated successfully",
+		Data:    rule,
+	})
+}
+
+// DeletePriceListRule godoc
+// @Summary Delete a price list rule
+// @Description Delete a rule from a price list (admin only)
+// @Tags price-lists
+// @Security BearerAuth
+// @Param id path int true "Price list ID"
+// @Param ruleId path int true "Rule ID"
+// @Produce json
+// @Success 200 {object} models.ApiResponse
+// @Failure 400 {object} models.ApiResponse
+// @Failure 403 {object} models.ApiResponse
+// @Failure 404 {object} models.ApiResponse
+// @Failure 500 {object} models.ApiResponse
+// @Router /price-lists/{id}/rules/{ruleId} [delete]
+func (pc *PriceListController) DeletePriceListRule(c *gin.Context) {
+	id, ok := parsePriceListID(c)
+	if !ok {
+		return
+	}
+	ruleID, ok := parsePriceListRuleID(c)
+	if !ok {
+		return
+	}
+
+	if err := pc.PriceListService.DeleteRule(id, ruleID); err != nil {
+		handlePriceListError(c, err, "Could not delete price list rule")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ApiResponse{
+		Status:  "success",
+		Code:    http.StatusOK,
+		Message: "Price list rule deleted successfully",
+		Data:    nil,
+	})
+}
+
+// SetCustomerGroup godoc
+// @Summary Set a user's customer group
+// @Description Set the customer group that selects the user's price list, for example wholesale (admin only). An empty customer_group removes it, and the user's department price list applies instead.
+// @Tags price-lists
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "User ID"
+// @Param group body models.CustomerGroupInput true "Customer group"
+// @Success 200 {object} models.ApiResponse
+// @Failure 400 {object} models.ApiResponse
+// @Failure 403 {object} models.ApiResponse
+// @Failure 404 {object} models.ApiResponse
+// @Failure 500 {object} models.ApiResponse
+// @Router /users/{id}/customer-group [put]
+func (pc *PriceListController) SetCustomerGroup(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Invalid user ID",
+			Data:    nil,
+		})
+		return
+	}
+
+	var input models.CustomerGroupInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Validation failed",
+			Data:    nil,
+			Errors:  validations.FieldErrors(err),
+		})
+		return
+	}
+
+	user, err := pc.PriceListService.SetCustomerGroup(id, &input)
+	if err != nil {
+		handlePriceListError(c, err, "Could not update customer group")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ApiResponse{
+		Status:  "success",
+		Code:    http.StatusOK,
+		Message: "Customer group updated successfully",
+		Data:    user,
+	})
+}
+
+func parsePriceListID(c *gin.Context) (int, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Invalid price list ID",
+			Data:    nil,
+		})
+		return 0, false
+	}
+	return id, true
+}
+
+func parsePriceListRuleID(c *gin.Context) (int, bool) {
+	id, err := strconv.Atoi(c.Param("ruleId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Invalid price list rule ID",
+			Data:    nil,
+		})
+		return 0, false
+	}
+	return id, true
+}
+
+func handlePriceListError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, services.ErrPriceListNotFound):
+		c.JSON(http.StatusNotFound, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusNotFound,
+			Message: "Price list not found",
+			Data:    nil,
+		})
+	case errors.Is(err, services.ErrPriceListRuleNotFound):
+		c.JSON(http.StatusNotFound, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusNotFound,
+			Message: "Price list rule not found",
+			Data:    nil,
+		})
+	case errors.Is(err, services.ErrUserNotFound):
+		c.JSON(http.StatusNotFound, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusNotFound,
+			Message: "User not found",
+			Data:    nil,
+		})
+	case errors.Is(err, services.ErrDuplicatePriceListName):
+		c.JSON(http.StatusConflict, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusConflict,
+			Message: "Validation failed",
+			Data:    nil,
+			Errors: []models.FieldError{{
+				Field:   "name",
+				Code:    models.ValidationNotUnique,
+				Message: err.Error(),
+			}},
+		})
+	case errors.Is(err, services.ErrDuplicatePriceListRule):
+		c.JSON(http.StatusConflict, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusConflict,
+			Message: "Validation failed",
+			Data:    nil,
+			Errors: []models.FieldError{{
+				Field:   "min_quantity",
+				Code:    models.ValidationNotUnique,
+				Message: err.Error(),
+			}},
+		})
+	case errors.Is(err, services.ErrPriceListAssigned):
+		c.JSON(http.StatusConflict, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusConflict,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	case errors.Is(err, services.ErrPriceListRuleTarget), errors.Is(err, services.ErrProductNotFound):
+		priceListValidationError(c, "product_id", err)
+	case errors.Is(err, services.ErrCategoryNotFound):
+		priceListValidationError(c, "category_id", err)
+	case errors.Is(err, services.ErrFixedCategoryPrice):
+		priceListValidationError(c, "type", err)
+	default:
+		c.JSON(http.StatusInternalServerError, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusInternalServerError,
+			Message: fallback,
+			Data:    nil,
+		})
+	}
+}
+
+func priceListValidationError(c *gin.Context, field string, err error) {
+	c.JSON(http.StatusBadRequest, models.ApiResponse{
+		Status:  "error",
+		Code:    http.StatusBadRequest,
+		Message: "Validation failed",
+		Data:    nil,
+		Errors: []models.FieldError{{
+			Field:   field,
+			Code:    models.ValidationInvalidValue,
+			Message: err.Error(),
+		}},
+	})
+}
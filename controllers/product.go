@@ -1,11 +1,15 @@
 package controllers
 
 import (
+	"errors"
 	"net/http"
+	"slices"
 	"strconv"
+	"strings"
 
 	"products-api-with-jwt/models"
 	"products-api-with-jwt/services"
+	"products-api-with-jwt/validations"
 
 	"github.com/gin-gonic/gin"
 )
@@ -26,16 +30,35 @@ func NewProductController(productService *services.ProductService) *ProductContr
 
 // GetProducts godoc
 // @Summary Get all products
-// @Description Get a list of all products
+// @Description Get a list of all products, optionally filtered by category, tags and attribute values
 // @Tags products
 // @Security BearerAuth
+// @Param category query int false "Only products in this category"
+// @Param include_descendants query bool false "With category, also include products in its sub-categories"
+// @Param tags query string false "Comma separated tags to filter by"
+// @Param match query string false "Tag matching mode: any (default) or all" Enums(any, all)
+// @Param include query string false "Comma separated related data to embed (categories, components, locations, related, tags, variants)"
+// @Param currency query string false "ISO 4217 currency to show the effective price in (Price field). Products without an exchange rate keep their own currency."
+// @Param lang query string false "Locale of NamaProduk and Deskripsi (id, en). Takes precedence over Accept-Language; untranslated fields fall back to id."
+// @Param Accept-Language header string false "Preferred locales, e.g. en-US,en;q=0.9"
+// @Param status query string false "Comma separated statuses to filter by (draft, published, archived). Drafts are only visible to admins."
+// @Param attr.code query string false "Filter by attribute value: attr.<code>=a,b matches any of the values; attr.<code>.min= and attr.<code>.max= filter number attributes"
 // @Produce json
 // @Success 200 {object} models.ApiResponse
+// @Failure 400 {object} models.ApiResponse
 // @Failure 500 {object} models.ApiResponse
 // @Router /products [get]
 func (pc *ProductController) GetProducts(c *gin.Context) {
-	products, err := pc.ProductService.GetAllProducts()
+	query := parseProductQuery(c)
+	if !checkProductQuery(c, query) {
+		return
+	}
+
+	products, err := pc.ProductService.GetAllProducts(query)
 	if err != nil {
+		if attributeErrors(c, err) {
+			return
+		}
 		c.JSON(http.StatusInternalServerError, models.ApiResponse{
 			Status:  "error",
 			Code:    http.StatusInternalServerError,
@@ -45,6 +68,7 @@ func (pc *ProductController) GetProducts(c *gin.Context) {
 		return
 	}
 
+	c.Header("Content-Language", query.Locale)
 	c.JSON(http.StatusOK, models.ApiResponse{
 		Status:  "success",
 		Code:    http.StatusOK,
@@ -56,10 +80,14 @@ func (pc *ProductController) GetProducts(c *gin.Context) {
 
 // GetProductByID godoc
 // @Summary Get product by ID
-// @Description Get details of a product by its ID
+// @Description Get details of a product by its ID. Drafts are only visible to admins.
 // @Tags products
 // @Security BearerAuth
 // @Param id path int true "Product ID"
+// @Param include query string false "Comma separated related data to embed (categories, components, locations, related, suggestions, tags, variants). suggestions adds up to 5 similar products scored by shared categories, shared tags and a price within 25%."
+// @Param currency query string false "ISO 4217 currency to show the effective price in (Price field). Products without an exchange rate keep their own currency."
+// @Param lang query string false "Locale of NamaProduk and Deskripsi (id, en). Takes precedence over Accept-Language; untranslated fields fall back to id."
+// @Param Accept-Language header string false "Preferred locales, e.g. en-US,en;q=0.9"
 // @Produce json
 // @Success 200 {object} models.ApiResponse
 // @Failure 400 {object} models.ApiResponse
@@ -77,7 +105,12 @@ func (pc *ProductController) GetProductByID(c *gin.Context) {
 		return
 	}
 
-	product, err := pc.ProductService.GetProductByID(id)
+	query := parseProductQuery(c)
+	if !checkProductQuery(c, query) {
+		return
+	}
+
+	product, err := pc.ProductService.GetProductDetail(id, query)
 	if err != nil {
 		c.JSON(http.StatusNotFound, models.ApiResponse{
 			Status:  "error",
@@ -88,6 +121,7 @@ func (pc *ProductController) GetProductByID(c *gin.Context) {
 		return
 	}
 
+	c.Header("Content-Language", query.Locale)
 	c.JSON(http.StatusOK, models.ApiResponse{
 		Status:  "success",
 		Code:    http.StatusOK,
@@ -96,32 +130,179 @@ func (pc *ProductController) GetProductByID(c *gin.Context) {
 	})
 }
 
+// LookupProduct godoc
+// @Summary Look up a product by barcode or SKU
+// @Description Resolve a scanned barcode (EAN-13, EAN-8, UPC-A) or a SKU to its product. Codes of variants resolve to their product, and the matching variant is returned too.
+// @Tags products
+// @Security BearerAuth
+// @Param barcode query string false "Barcode to look up"
+// @Param sku query string false "SKU to look up"
+// @Produce json
+// @Success 200 {object} models.ApiResponse
+// @Failure 400 {object} models.ApiResponse
+// @Failure 404 {object} models.ApiResponse
+// @Failure 500 {object} models.ApiResponse
+// @Router /products/lookup [get]
+func (pc *ProductController) LookupProduct(c *gin.Context) {
+	barcode, sku := strings.TrimSpace(c.Query("barcode")), strings.TrimSpace(c.Query("sku"))
+	if (barcode == "") == (sku == "") {
+		c.JSON(http.StatusBadRequest, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Provide either barcode or sku",
+			Data:    nil,
+		})
+		return
+	}
+
+	column, code := "sku", sku
+	if barcode != "" {
+		if !validations.ValidBarcode(barcode) {
+			c.JSON(http.StatusBadRequest, models.ApiResponse{
+				Status:  "error",
+				Code:    http.StatusBadRequest,
+				Message: "Validation failed",
+				Data:    nil,
+				Errors: []models.FieldError{{
+					Field:   "barcode",
+					Code:    models.ValidationInvalidValue,
+					Message: "must be a valid EAN-13, EAN-8 or UPC-A barcode",
+				}},
+			})
+			return
+		}
+		column, code = "barcode", barcode
+	}
+
+	userID := 0
+	if user := currentUser(c); user != nil {
+		userID = user.ID
+	}
+
+	lookup, err := pc.ProductService.LookupProduct(column, code, isEditor(c), userID)
+	if err != nil {
+		if errors.Is(err, services.ErrProductNotFound) {
+			c.JSON(http.StatusNotFound, models.ApiResponse{
+				Status:  "error",
+				Code:    http.StatusNotFound,
+				Message: "Product not found",
+				Data:    nil,
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusInternalServerError,
+			Message: "Could not look up product",
+			Data:    nil,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ApiResponse{
+		Status:  "success",
+		Code:    http.StatusOK,
+		Message: "Product retrieved successfully",
+		Data:    lookup,
+	})
+}
+
 // CreateProduct godoc
 // @Summary Create a new product
-// @Description Create a new product with the given details
+// @Description Create a new product with the given details. New products start as drafts and must be published before non-admins can see them. Attributes are validated against the attribute schemas of the product's categories and their parents. Validation failures are returned as a list of field errors.
 // @Tags products
 // @Security BearerAuth
 // @Accept json
 // @Produce json
-// @Param product body models.Product true "Product"
+// @Param product body models.CreateProductInput true "Product"
 // @Success 201 {object} models.ApiResponse
 // @Failure 400 {object} models.ApiResponse
+// @Failure 409 {object} models.ApiResponse
 // @Failure 500 {object} models.ApiResponse
 // @Router /products [post]
 func (pc *ProductController) CreateProduct(c *gin.Context) {
-	var input models.Product
+	var input models.CreateProductInput
 	if err := c.ShouldBindJSON(&input); err != nil {
 		c.JSON(http.StatusBadRequest, models.ApiResponse{
 			Status:  "error",
 			Code:    http.StatusBadRequest,
-			Message: err.Error(),
+			Message: "Validation failed",
 			Data:    nil,
+			Errors:  validations.FieldErrors(err),
 		})
 		return
 	}
 
-	product, err := pc.ProductService.CreateProduct(&input)
+	// Default department mengikuti user yang membuat produk
+	userID := 0
+	if user := currentUser(c); user != nil {
+		userID = user.ID
+		if input.Department == "" {
+			input.Department = user.Department
+		}
+	}
+
+	product, err := pc.ProductService.CreateProduct(&input, userID)
 	if err != nil {
+		if attributeErrors(c, err) {
+			return
+		}
+		if errors.Is(err, services.ErrCategoryNotFound) {
+			c.JSON(http.StatusBadRequest, models.ApiResponse{
+				Status:  "error",
+				Code:    http.StatusBadRequest,
+				Message: "Validation failed",
+				Data:    nil,
+				Errors: []models.FieldError{{
+					Field:   "category_ids",
+					Code:    models.ValidationInvalidValue,
+					Message: err.Error(),
+				}},
+			})
+			return
+		}
+		if errors.Is(err, services.ErrTaxClassNotFound) {
+			c.JSON(http.StatusBadRequest, models.ApiResponse{
+				Status:  "error",
+				Code:    http.StatusBadRequest,
+				Message: "Validation failed",
+				Data:    nil,
+				Errors: []models.FieldError{{
+					Field:   "tax_class_id",
+					Code:    models.ValidationInvalidValue,
+					Message: err.Error(),
+				}},
+			})
+			return
+		}
+		if errors.Is(err, services.ErrDuplicateProductName) {
+			c.JSON(http.StatusConflict, models.ApiResponse{
+				Status:  "error",
+				Code:    http.StatusConflict,
+				Message: "Validation failed",
+				Data:    nil,
+				Errors: []models.FieldError{{
+					Field:   "nama_produk",
+					Code:    models.ValidationNotUnique,
+					Message: err.Error(),
+				}},
+			})
+			return
+		}
+		if field := duplicateCodeField(err); field != "" {
+			c.JSON(http.StatusConflict, models.ApiResponse{
+				Status:  "error",
+				Code:    http.StatusConflict,
+				Message: "Validation failed",
+				Data:    nil,
+				Errors: []models.FieldError{{
+					Field:   field,
+					Code:    models.ValidationNotUnique,
+					Message: err.Error(),
+				}},
+			})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, models.ApiResponse{
 			Status:  "error",
 			Code:    http.StatusInternalServerError,
@@ -141,16 +322,17 @@ func (pc *ProductController) CreateProduct(c *gin.Context) {
 
 // UpdateProduct godoc
 // @Summary Update a product by ID
-// @Description Update a product's information by its ID
+// @Description Update a product's information by its ID. Only the fields present in the body are changed. Attributes are validated again when attributes or categories change. Stock is changed through the stock adjustment endpoint.
 // @Tags products
 // @Security BearerAuth
 // @Accept json
 // @Produce json
 // @Param id path int true "Product ID"
-// @Param product body models.Product true "Product"
+// @Param product body models.UpdateProductInput true "Product"
 // @Success 200 {object} models.ApiResponse
 // @Failure 400 {object} models.ApiResponse
 // @Failure 404 {object} models.ApiResponse
+// @Failure 409 {object} models.ApiResponse
 // @Failure 500 {object} models.ApiResponse
 // @Router /products/{id} [put]
 func (pc *ProductController) UpdateProduct(c *gin.Context) {
@@ -165,25 +347,104 @@ func (pc *ProductController) UpdateProduct(c *gin.Context) {
 		return
 	}
 
-	var input models.Product
+	var input models.UpdateProductInput
 	if err := c.ShouldBindJSON(&input); err != nil {
 		c.JSON(http.StatusBadRequest, models.ApiResponse{
 			Status:  "error",
 			Code:    http.StatusBadRequest,
-			Message: err.Error(),
+			Message: "Validation failed",
 			Data:    nil,
+			Errors:  validations.FieldErrors(err),
 		})
 		return
 	}
 
-	updatedProduct, err := pc.ProductService.UpdateProduct(id, &input)
+	userID := 0
+	if user := currentUser(c); user != nil {
+		userID = user.ID
+	}
+
+	updatedProduct, err := pc.ProductService.UpdateProduct(id, &input, userID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.ApiResponse{
-			Status:  "error",
-			Code:    http.StatusInternalServerError,
-			Message: "Could not update product",
-			Data:    nil,
-		})
+		if attributeErrors(c, err) {
+			return
+		}
+		switch {
+		case errors.Is(err, services.ErrProductNotFound):
+			c.JSON(http.StatusNotFound, models.ApiResponse{
+				Status:  "error",
+				Code:    http.StatusNotFound,
+				Message: "Product not found",
+				Data:    nil,
+			})
+		case errors.Is(err, services.ErrCategoryNotFound):
+			c.JSON(http.StatusBadRequest, models.ApiResponse{
+				Status:  "error",
+				Code:    http.StatusBadRequest,
+				Message: "Validation failed",
+				Data:    nil,
+				Errors: []models.FieldError{{
+					Field:   "category_ids",
+					Code:    models.ValidationInvalidValue,
+					Message: err.Error(),
+				}},
+			})
+		case errors.Is(err, services.ErrTaxClassNotFound):
+			c.JSON(http.StatusBadRequest, models.ApiResponse{
+				Status:  "error",
+				Code:    http.StatusBadRequest,
+				Message: "Validation failed",
+				Data:    nil,
+				Errors: []models.FieldError{{
+					Field:   "tax_class_id",
+					Code:    models.ValidationInvalidValue,
+					Message: err.Error(),
+				}},
+			})
+		case errors.Is(err, services.ErrBundleReorderPoint):
+			c.JSON(http.StatusBadRequest, models.ApiResponse{
+				Status:  "error",
+				Code:    http.StatusBadRequest,
+				Message: "Validation failed",
+				Data:    nil,
+				Errors: []models.FieldError{{
+					Field:   "reorder_point",
+					Code:    models.ValidationInvalidValue,
+					Message: err.Error(),
+				}},
+			})
+		case errors.Is(err, services.ErrDuplicateProductName):
+			c.JSON(http.StatusConflict, models.ApiResponse{
+				Status:  "error",
+				Code:    http.StatusConflict,
+				Message: "Validation failed",
+				Data:    nil,
+				Errors: []models.FieldError{{
+					Field:   "nama_produk",
+					Code:    models.ValidationNotUnique,
+					Message: err.Error(),
+				}},
+			})
+		case duplicateCodeField(err) != "":
+			c.JSON(http.StatusConflict, models.ApiResponse{
+				Status:  "error",
+				Code:    http.StatusConflict,
+				Message: "Validation failed",
+				Data:    nil,
+				Errors: []models.FieldError{{
+					Field:   duplicateCodeField(err),
+					Code:    models.ValidationNotUnique,
+					Message: err.Error(),
+				}},
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, models.ApiResponse{
+				Status:  "error",
+				Code:    http.StatusInternalServerError,
+				Message: "Could not update product",
+				Data:    nil,
+			})
+		}
 		return
 	}
 
@@ -197,13 +458,14 @@ func (pc *ProductController) UpdateProduct(c *gin.Context) {
 
 // DeleteProduct godoc
 // @Summary Delete a product by ID
-// @Description Delete a product by its ID
+// @Description Soft delete a product by its ID. The product is moved to the trash and can be restored until it is purged.
 // @Tags products
 // @Security BearerAuth
 // @Param id path int true "Product ID"
 // @Produce json
 // @Success 200 {object} models.ApiResponse
 // @Failure 400 {object} models.ApiResponse
+// @Failure 404 {object} models.ApiResponse
 // @Failure 500 {object} models.ApiResponse
 // @Router /products/{id} [delete]
 func (pc *ProductController) DeleteProduct(c *gin.Context) {
@@ -219,6 +481,15 @@ func (pc *ProductController) DeleteProduct(c *gin.Context) {
 	}
 
 	if err := pc.ProductService.DeleteProduct(id); err != nil {
+		if errors.Is(err, services.ErrProductNotFound) {
+			c.JSON(http.StatusNotFound, models.ApiResponse{
+				Status:  "error",
+				Code:    http.StatusNotFound,
+				Message: "Product not found",
+				Data:    nil,
+			})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, models.ApiResponse{
 			Status:  "error",
 			Code:    http.StatusInternalServerError,
@@ -235,3 +506,226 @@ func (pc *ProductController) DeleteProduct(c *gin.Context) {
 		Data:    nil,
 	})
 }
+
+// GetDeletedProducts godoc
+// @Summary List deleted products
+// @Description Get the products currently in the trash (admin only)
+// @Tags products
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} models.ApiResponse
+// @Failure 403 {object} models.ApiResponse
+// @Failure 500 {object} models.ApiResponse
+// @Router /products/trash [get]
+func (pc *ProductController) GetDeletedProducts(c *gin.Context) {
+	products, err := pc.ProductService.GetDeletedProducts()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusInternalServerError,
+			Message: "Could not retrieve deleted products",
+			Data:    nil,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ApiResponse{
+		Status:  "success",
+		Code:    http.StatusOK,
+		Message: "Deleted products retrieved successfully",
+		Data:    products,
+		Count:   len(products),
+	})
+}
+
+// RestoreProduct godoc
+// @Summary Restore a deleted product
+// @Description Restore a product from the trash by its ID (admin only)
+// @Tags products
+// @Security BearerAuth
+// @Param id path int true "Product ID"
+// @Produce json
+// @Success 200 {object} models.ApiResponse
+// @Failure 400 {object} models.ApiResponse
+// @Failure 403 {object} models.ApiResponse
+// @Failure 404 {object} models.ApiResponse
+// @Failure 409 {object} models.ApiResponse
+// @Failure 500 {object} models.ApiResponse
+// @Router /products/{id}/restore [post]
+func (pc *ProductController) RestoreProduct(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Invalid product ID",
+			Data:    nil,
+		})
+		return
+	}
+
+	product, err := pc.ProductService.RestoreProduct(id)
+	if err != nil {
+		if errors.Is(err, services.ErrProductNotFound) {
+			c.JSON(http.StatusNotFound, models.ApiResponse{
+				Status:  "error",
+				Code:    http.StatusNotFound,
+				Message: "Deleted product not found",
+				Data:    nil,
+			})
+			return
+		}
+		if errors.Is(err, services.ErrDuplicateProductName) {
+			c.JSON(http.StatusConflict, models.ApiResponse{
+				Status:  "error",
+				Code:    http.StatusConflict,
+				Message: "Validation failed",
+				Data:    nil,
+				Errors: []models.FieldError{{
+					Field:   "nama_produk",
+					Code:    models.ValidationNotUnique,
+					Message: err.Error(),
+				}},
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusInternalServerError,
+			Message: "Could not restore product",
+			Data:    nil,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ApiResponse{
+		Status:  "success",
+		Code:    http.StatusOK,
+		Message: "Product restored successfully",
+		Data:    product,
+	})
+}
+
+// parseProductQuery membaca opsi query produk dari request
+func parseProductQuery(c *gin.Context) *models.ProductQuery {
+	query := &models.ProductQuery{Include: map[string]bool{}}
+	for _, name := range strings.Split(c.Query("include"), ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			query.Include[name] = true
+		}
+	}
+	query.CategoryID, _ = strconv.Atoi(c.Query("category"))
+	query.IncludeDescendants, _ = strconv.ParseBool(c.Query("include_descendants"))
+	if tags := c.Query("tags"); tags != "" {
+		query.Tags = services.NormalizeTags(strings.Split(tags, ","))
+	}
+	query.MatchAllTags = c.Query("match") == "all"
+	query.Currency = strings.ToUpper(strings.TrimSpace(c.Query("currency")))
+	for _, status := range strings.Split(c.Query("status"), ",") {
+		if status = strings.ToLower(strings.TrimSpace(status)); status != "" {
+			query.Statuses = append(query.Statuses, status)
+		}
+	}
+	query.IncludeDrafts = isEditor(c)
+	if user := currentUser(c); user != nil {
+		query.UserID = user.ID
+	}
+	for key, values := range c.Request.URL.Query() {
+		if code, ok := strings.CutPrefix(key, "attr."); ok && len(values) > 0 {
+			if query.Attributes == nil {
+				query.Attributes = map[string]string{}
+			}
+			query.Attributes[code] = values[0]
+		}
+	}
+	// ?lang= didahulukan dari Accept-Language. Nilai yang tidak dikenal ditolak oleh checkProductQuery.
+	if lang := c.Query("lang"); lang != "" {
+		var ok bool
+		if query.Locale, ok = matchLocale(lang); !ok {
+			query.Locale = lang
+		}
+	} else {
+		query.Locale = acceptLanguageLocale(c.GetHeader("Accept-Language"))
+	}
+	return query
+}
+
+// checkProductQuery menolak ?status=, ?lang= dan ?currency= yang tidak dikenal. Mengembalikan false jika response sudah dikirim.
+func checkProductQuery(c *gin.Context, query *models.ProductQuery) bool {
+	for _, status := range query.Statuses {
+		if status != models.ProductDraft && status != models.ProductPublished && status != models.ProductArchived {
+			c.JSON(http.StatusBadRequest, models.ApiResponse{
+				Status:  "error",
+				Code:    http.StatusBadRequest,
+				Message: "Validation failed",
+				Data:    nil,
+				Errors: []models.FieldError{{
+					Field:   "status",
+					Code:    models.ValidationInvalidValue,
+					Message: "must be one of: draft published archived",
+				}},
+			})
+			return false
+		}
+	}
+
+	if !slices.Contains(models.SupportedLocales, query.Locale) {
+		c.JSON(http.StatusBadRequest, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Validation failed",
+			Data:    nil,
+			Errors: []models.FieldError{{
+				Field:   "lang",
+				Code:    models.ValidationInvalidValue,
+				Message: "must be one of: " + strings.Join(models.SupportedLocales, " "),
+			}},
+		})
+		return false
+	}
+
+	if _, ok := models.CurrencyExponents[query.Currency]; query.Currency == "" || ok {
+		return true
+	}
+	c.JSON(http.StatusBadRequest, models.ApiResponse{
+		Status:  "error",
+		Code:    http.StatusBadRequest,
+		Message: "Validation failed",
+		Data:    nil,
+		Errors: []models.FieldError{{
+			Field:   "currency",
+			Code:    models.ValidationInvalidValue,
+			Message: "must be one of: " + strings.Join(models.SupportedCurrencies(), " "),
+		}},
+	})
+	return false
+}
+
+// attributeErrors mengirim 400 dengan satu error per attribute jika err berasal dari validasi attribute.
+// Mengembalikan false jika err bukan error attribute.
+func attributeErrors(c *gin.Context, err error) bool {
+	var invalid *services.AttributeValidationError
+	if !errors.As(err, &invalid) {
+		return false
+	}
+	c.JSON(http.StatusBadRequest, models.ApiResponse{
+		Status:  "error",
+		Code:    http.StatusBadRequest,
+		Message: "Validation failed",
+		Data:    nil,
+		Errors:  invalid.Fields,
+	})
+	return true
+}
+
+// duplicateCodeField mengembalikan field JSON untuk error SKU atau barcode duplikat, atau string kosong
+func duplicateCodeField(err error) string {
+	switch {
+	case errors.Is(err, services.ErrDuplicateSKU):
+		return "sku"
+	case errors.Is(err, services.ErrDuplicateBarcode):
+		return "barcode"
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,167 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+
+	"products-api-with-jwt/models"
+	"products-api-with-jwt/services"
+	"products-api-with-jwt/validations"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ChangeProductStatus godoc
+// @Summary Publish or archive a product
+// @Description Move a product to its next status: draft to published, or published to archived (admin only). Publishing requires a description, a price above 0 and at least one image; missing fields are returned as field errors with 422.
+// @Tags products
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "Product ID"
+// @Param status body models.ProductStatusInput true "New status"
+// @Success 200 {object} models.ApiResponse
+// @Failure 400 {object} models.ApiResponse
+// @Failure 403 {object} models.ApiResponse
+// @Failure 404 {object} models.ApiResponse
+// @Failure 409 {object} models.ApiResponse
+// @Failure 422 {object} models.ApiResponse
+// @Failure 500 {object} models.ApiResponse
+// @Router /products/{id}/status [put]
+func (pc *ProductController) ChangeProductStatus(c *gin.Context) {
+	id, ok := parseProductID(c)
+	if !ok {
+		return
+	}
+
+	var input models.ProductStatusInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Validation failed",
+			Data:    nil,
+			Errors:  validations.FieldErrors(err),
+		})
+		return
+	}
+
+	product, err := pc.ProductService.ChangeProductStatus(id, input.Status)
+	if err != nil {
+		handleProductStatusError(c, err, "Could not change product status")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ApiResponse{
+		Status:  "success",
+		Code:    http.StatusOK,
+		Message: "Product status changed successfully",
+		Data:    product,
+	})
+}
+
+// ScheduleProductStatus godoc
+// @Summary Schedule publishing and archiving of a product
+// @Description Set when a draft is published (publish_at) and when a published product is archived (unpublish_at). Null clears a schedule. A draft that is incomplete at publish_at stays a draft and its publish_at is cleared. (admin only)
+// @Tags products
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "Product ID"
+// @Param schedule body models.ProductStatusScheduleInput true "Schedule"
+// @Success 200 {object} models.ApiResponse
+// @Failure 400 {object} models.ApiResponse
+// @Failure 403 {object} models.ApiResponse
+// @Failure 404 {object} models.ApiResponse
+// @Failure 500 {object} models.ApiResponse
+// @Router /products/{id}/status/schedule [put]
+func (pc *ProductController) ScheduleProductStatus(c *gin.Context) {
+	id, ok := parseProductID(c)
+	if !ok {
+		return
+	}
+
+	var input models.ProductStatusScheduleInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Validation failed",
+			Data:    nil,
+			Errors:  validations.FieldErrors(err),
+		})
+		return
+	}
+
+	product, err := pc.ProductService.ScheduleProductStatus(id, &input)
+	if err != nil {
+		handleProductStatusError(c, err, "Could not schedule product status")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ApiResponse{
+		Status:  "success",
+		Code:    http.StatusOK,
+		Message: "Product status scheduled successfully",
+		Data:    product,
+	})
+}
+
+func handleProductStatusError(c *gin.Context, err error, fallback string) {
+	var incomplete *services.ProductIncompleteError
+	switch {
+	case errors.Is(err, services.ErrProductNotFound):
+		c.JSON(http.StatusNotFound, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusNotFound,
+			Message: "Product not found",
+			Data:    nil,
+		})
+	case errors.As(err, &incomplete):
+		c.JSON(http.StatusUnprocessableEntity, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusUnprocessableEntity,
+			Message: services.ErrProductIncomplete.Error(),
+			Data:    nil,
+			Errors:  incomplete.Fields,
+		})
+	case errors.Is(err, services.ErrInvalidStatusTransition):
+		c.JSON(http.StatusConflict, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusConflict,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	case errors.Is(err, services.ErrInvalidPublishSchedule):
+		c.JSON(http.StatusBadRequest, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Validation failed",
+			Data:    nil,
+			Errors: []models.FieldError{{
+				Field:   "publish_at",
+				Code:    models.ValidationInvalidValue,
+				Message: err.Error(),
+			}},
+		})
+	case errors.Is(err, services.ErrInvalidUnpublishSchedule):
+		c.JSON(http.StatusBadRequest, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Validation failed",
+			Data:    nil,
+			Errors: []models.FieldError{{
+				Field:   "unpublish_at",
+				Code:    models.ValidationInvalidValue,
+				Message: err.Error(),
+			}},
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusInternalServerError,
+			Message: fallback,
+			Data:    nil,
+		})
+	}
+}
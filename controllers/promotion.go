@@ -0,0 +1,384 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"products-api-with-jwt/models"
+	"products-api-with-jwt/services"
+	"products-api-with-jwt/validations"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PromotionController struct {
+	PromotionService *services.PromotionService
+}
+
+// NewPromotionController menginisialisasi PromotionController baru
+func NewPromotionController(promotionService *services.PromotionService) *PromotionController {
+	return &PromotionController{PromotionService: promotionService}
+}
+
+// GetPromotions godoc
+// @Summary Get all promotions
+// @Description Get all promotions in the order they are applied: highest priority first (admin only)
+// @Tags promotions
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} models.ApiResponse
+// @Failure 403 {object} models.ApiResponse
+// @Failure 500 {object} models.ApiResponse
+// @Router /promotions [get]
+func (pc *PromotionController) GetPromotions(c *gin.Context) {
+	promotions, err := pc.PromotionService.GetAllPromotions()
+	if err != nil {
+		pc.handleError(c, err, "Could not retrieve promotions")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ApiResponse{
+		Status:  "success",
+		Code:    http.StatusOK,
+		Message: "Promotions retrieved successfully",
+		Data:    promotions,
+		Count:   len(promotions),
+	})
+}
+
+// GetPromotionByID godoc
+// @Summary Get promotion by ID
+// @Description Get a promotion by its ID (admin only)
+// @Tags promotions
+// @Security BearerAuth
+// @Param id path int true "Promotion ID"
+// @Produce json
+// @Success 200 {object} models.ApiResponse
+// @Failure 400 {object} models.ApiResponse
+// @Failure 403 {object} models.ApiResponse
+// @Failure 404 {object} models.ApiResponse
+// @Router /promotions/{id} [get]
+func (pc *PromotionController) GetPromotionByID(c *gin.Context) {
+	id, ok := parsePromotionID(c)
+	if !ok {
+		return
+	}
+
+	promotion, err := pc.PromotionService.GetPromotionByID(id)
+	if err != nil {
+		pc.handleError(c, err, "Could not retrieve promotion")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ApiResponse{
+		Status:  "success",
+		Code:    http.StatusOK,
+		Message: "Promotion retrieved successfully",
+		Data:    promotion,
+	})
+}
+
+// CreatePromotion godoc
+// @Summary Create a promotion
+// @Description Create a percentage, fixed (amount off per unit) or buy_x_get_y promotion targeting products, categories (including sub-categories) or tags (admin only)
+// @Tags promotions
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param promotion body models.PromotionInput true "Promotion"
+// @Success 201 {object} models.ApiResponse
+// @Failure 400 {object} models.ApiResponse
+// @Failure 403 {object} models.ApiResponse
+// @Failure 500 {object} models.ApiResponse
+// @Router /promotions [post]
+func (pc *PromotionController) CreatePromotion(c *gin.Context) {
+	var input models.PromotionInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Validation failed",
+			Data:    nil,
+			Errors:  validations.FieldErrors(err),
+		})
+		return
+	}
+
+	promotion, err := pc.PromotionService.CreatePromotion(&input)
+	if err != nil {
+		pc.handleError(c, err, "Could not create promotion")
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.ApiResponse{
+		Status:  "success",
+		Code:    http.StatusCreated,
+		Message: "Promotion created successfully",
+		Data:    promotion,
+	})
+}
+
+// UpdatePromotion godoc
+// @Summary Update a promotion
+// @Description Replace a promotion with the given details (admin only)
+// @Tags promotions
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "Promotion ID"
+// @Param promotion body models.PromotionInput true "Promotion"
+// @Success 200 {object} models.ApiResponse
+// @Failure 400 {object} models.ApiResponse
+// @Failure 403 {object} models.ApiResponse
+// @Failure 404 {object} models.ApiResponse
+// @Failure 500 {object} models.ApiResponse
+// @Router /promotions/{id} [put]
+func (pc *PromotionController) UpdatePromotion(c *gin.Context) {
+	id, ok := parsePromotionID(c)
+	if !ok {
+		return
+	}
+
+	var input models.PromotionInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Validation failed",
+			Data:    nil,
+			Errors:  validations.FieldErrors(err),
+		})
+		return
+	}
+
+	promotion, err := pc.PromotionService.UpdatePromotion(id, &input)
+	if err != nil {
+		pc.handleError(c, err, "Could not update promotion")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ApiResponse{
+		Status:  "success",
+		Code:    http.StatusOK,
+		Message: "Promotion updated successfully",
+		Data:    promotion,
+	})
+}
+
+// DeletePromotion godoc
+// @Summary Delete a promotion
+// @Description Delete a promotion by its ID (admin only)
+// @Tags promotions
+// @Security BearerAuth
+// @Param id path int true "Promotion ID"
+// @Produce json
+// @Success 200 {object} models.ApiResponse
+// @Failure 400 {object} models.ApiResponse
+// @Failure 403 {object} models.ApiResponse
+// @Failure 404 {object} models.ApiResponse
+// @Failure 500 {object} models.ApiResponse
+// @Router /promotions/{id} [delete]
+func (pc *PromotionController) DeletePromotion(c *gin.Context) {
+	id, ok := parsePromotionID(c)
+	if !ok {
+		return
+	}
+
+	if err := pc.PromotionService.DeletePromotion(id); err != nil {
+		pc.handleError(c, err, "Could not delete promotion")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ApiResponse{
+		Status:  "success",
+		Code:    http.StatusOK,
+		Message: "Promotion deleted successfully",
+		Data:    nil,
+	})
+}
+
+// DryRunPromotions godoc
+// @Summary Dry-run price evaluation
+// @Description Evaluate the price of a product at a given time, optionally with an unsaved promotion included as if it existed, and with the price list of user_id. Nothing is saved. (admin only)
+// @Tags promotions
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param evaluation body models.PromotionDryRunInput true "Evaluation"
+// @Success 200 {object} models.ApiResponse
+// @Failure 400 {object} models.ApiResponse
+// @Failure 403 {object} models.ApiResponse
+// @Failure 404 {object} models.ApiResponse
+// @Failure 500 {object} models.ApiResponse
+// @Router /promotions/dry-run [post]
+func (pc *PromotionController) DryRunPromotions(c *gin.Context) {
+	var input models.PromotionDryRunInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Validation failed",
+			Data:    nil,
+			Errors:  validations.FieldErrors(err),
+		})
+		return
+	}
+
+	quote, err := pc.PromotionService.DryRun(&input)
+	if err != nil {
+		// Error validasi promo yang disimulasikan ditujukan ke field di dalam "promotion"
+		if field := promotionErrorField(err); field != "" {
+			promotionValidationError(c, "promotion."+field, err)
+			return
+		}
+		pc.handleError(c, err, "Could not evaluate price")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ApiResponse{
+		Status:  "success",
+		Code:    http.StatusOK,
+		Message: "Price evaluated successfully",
+		Data:    quote,
+	})
+}
+
+// GetProductPrice godoc
+// @Summary Get the final price of a product
+// @Description Evaluate the promotions that apply to a product right now and return the final price for qty units with a line-by-line breakdown. Promotions whose targets match but that were not applied are listed in Skipped with the reason. Tax is calculated for the region from the product's tax class, as NetMinor, TaxMinor and GrossMinor. The unit price comes from the caller's customer-group price list, including quantity tiers; ListPriceMinor is the catalog price. Only admins can price products that are not published.
+// @Tags promotions
+// @Security BearerAuth
+// @Param id path int true "Product ID"
+// @Param qty query int false "Quantity (default 1)"
+// @Param region query string false "Tax region, ISO 3166 code such as ID or ID-BA (default ID)"
+// @Produce json
+// @Success 200 {object} models.ApiResponse
+// @Failure 400 {object} models.ApiResponse
+// @Failure 404 {object} models.ApiResponse
+// @Failure 500 {object} models.ApiResponse
+// @Router /products/{id}/price [get]
+func (pc *PromotionController) GetProductPrice(c *gin.Context) {
+	productID, ok := parseProductID(c)
+	if !ok {
+		return
+	}
+	quantity := 1
+	if qty := c.Query("qty"); qty != "" {
+		var err error
+		if quantity, err = strconv.Atoi(qty); err != nil || quantity < 1 || quantity > 1000000 {
+			c.JSON(http.StatusBadRequest, models.ApiResponse{
+				Status:  "error",
+				Code:    http.StatusBadRequest,
+				Message: "Validation failed",
+				Data:    nil,
+				Errors: []models.FieldError{{
+					Field:   "qty",
+					Code:    models.ValidationOutOfRange,
+					Message: "must be a whole number between 1 and 1000000",
+				}},
+			})
+			return
+		}
+	}
+
+	userID := 0
+	if user := currentUser(c); user != nil {
+		userID = user.ID
+	}
+
+	quote, err := pc.PromotionService.QuotePrice(productID, quantity, c.Query("region"), userID, isEditor(c))
+	if err != nil {
+		pc.handleError(c, err, "Could not evaluate price")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ApiResponse{
+		Status:  "success",
+		Code:    http.StatusOK,
+		Message: "Price evaluated successfully",
+		Data:    quote,
+	})
+}
+
+func (pc *PromotionController) handleError(c *gin.Context, err error, fallback string) {
+	if field := promotionErrorField(err); field != "" {
+		promotionValidationError(c, field, err)
+		return
+	}
+
+	switch {
+	case errors.Is(err, services.ErrPromotionNotFound):
+		c.JSON(http.StatusNotFound, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusNotFound,
+			Message: "Promotion not found",
+			Data:    nil,
+		})
+	case errors.Is(err, services.ErrProductNotFound):
+		c.JSON(http.StatusNotFound, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusNotFound,
+			Message: "Product not found",
+			Data:    nil,
+		})
+	case errors.Is(err, services.ErrProductNotAvailable):
+		c.JSON(http.StatusBadRequest, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Only published products can be priced",
+			Data:    nil,
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusInternalServerError,
+			Message: fallback,
+			Data:    nil,
+		})
+	}
+}
+
+// promotionErrorField mengembalikan field JSON untuk error validasi promo dari service, atau string kosong
+func promotionErrorField(err error) string {
+	switch {
+	case errors.Is(err, services.ErrPromotionTargetsRequired), errors.Is(err, services.ErrInvalidPromotionProduct):
+		return "product_ids"
+	case errors.Is(err, services.ErrCategoryNotFound):
+		return "category_ids"
+	case errors.Is(err, services.ErrInvalidPromotionWindow):
+		return "ends_at"
+	case errors.Is(err, services.ErrInvalidTaxRegion):
+		return "region"
+	default:
+		return ""
+	}
+}
+
+func promotionValidationError(c *gin.Context, field string, err error) {
+	c.JSON(http.StatusBadRequest, models.ApiResponse{
+		Status:  "error",
+		Code:    http.StatusBadRequest,
+		Message: "Validation failed",
+		Data:    nil,
+		Errors: []models.FieldError{{
+			Field:   field,
+			Code:    models.ValidationInvalidValue,
+			Message: err.Error(),
+		}},
+	})
+}
+
+func parsePromotionID(c *gin.Context) (int, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Invalid promotion ID",
+			Data:    nil,
+		})
+		return 0, false
+	}
+	return id, true
+}
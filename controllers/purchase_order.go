@@ -0,0 +1,388 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"slices"
+	"strconv"
+
+	"products-api-with-jwt/models"
+	"products-api-with-jwt/services"
+	"products-api-with-jwt/validations"
+
+	"github.com/gin-gonic/gin"
+)
+
+var purchaseOrderStatuses = []string{
+	models.PurchaseOrderDraft, models.PurchaseOrderSent, models.PurchaseOrderPartiallyReceived,
+	models.PurchaseOrderReceived, models.PurchaseOrderCancelled,
+}
+
+type PurchaseOrderController struct {
+	PurchaseOrderService *services.PurchaseOrderService
+}
+
+// NewPurchaseOrderController menginisialisasi PurchaseOrderController baru
+func NewPurchaseOrderController(purchaseOrderService *services.PurchaseOrderService) *PurchaseOrderController {
+	return &PurchaseOrderController{PurchaseOrderService: purchaseOrderService}
+}
+
+// GetPurchaseOrders godoc
+// @Summary Get all purchase orders
+// @Description Get purchase orders, newest first, optionally filtered by status and supplier (admin only)
+// @Tags purchase-orders
+// @Security BearerAuth
+// @Param status query string false "Status" Enums(draft, sent, partially_received, received, cancelled)
+// @Param supplier_id query int false "Supplier ID"
+// @Produce json
+// @Success 200 {object} models.ApiResponse
+// @Failure 400 {object} models.ApiResponse
+// @Failure 403 {object} models.ApiResponse
+// @Failure 500 {object} models.ApiResponse
+// @Router /purchase-orders [get]
+func (pc *PurchaseOrderController) GetPurchaseOrders(c *gin.Context) {
+	status := c.Query("status")
+	if status != "" && !slices.Contains(purchaseOrderStatuses, status) {
+		supplierValidationError(c, "status", errors.New("status must be one of draft, sent, partially_received, received, cancelled"))
+		return
+	}
+	supplierID := 0
+	if value := c.Query("supplier_id"); value != "" {
+		var err error
+		if supplierID, err = strconv.Atoi(value); err != nil {
+			supplierValidationError(c, "supplier_id", errors.New("supplier_id must be a number"))
+			return
+		}
+	}
+
+	orders, err := pc.PurchaseOrderService.GetPurchaseOrders(status, supplierID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusInternalServerError,
+			Message: "Could not retrieve purchase orders",
+			Data:    nil,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ApiResponse{
+		Status:  "success",
+		Code:    http.StatusOK,
+		Message: "Purchase orders retrieved successfully",
+		Data:    orders,
+		Count:   len(orders),
+	})
+}
+
+// GetPurchaseOrderByID godoc
+// @Summary Get purchase order by ID
+// @Description Get a purchase order with its supplier and lines (admin only)
+// @Tags purchase-orders
+// @Security BearerAuth
+// @Param id path int true "Purchase order ID"
+// @Produce json
+// @Success 200 {object} models.ApiResponse
+// @Failure 400 {object} models.ApiResponse
+// @Failure 403 {object} models.ApiResponse
+// @Failure 404 {object} models.ApiResponse
+// @Router /purchase-orders/{id} [get]
+func (pc *PurchaseOrderController) GetPurchaseOrderByID(c *gin.Context) {
+	id, ok := parsePurchaseOrderID(c)
+	if !ok {
+		return
+	}
+
+	order, err := pc.PurchaseOrderService.GetPurchaseOrderByID(id)
+	if err != nil {
+		handlePurchaseOrderError(c, err, "Could not retrieve purchase order")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ApiResponse{
+		Status:  "success",
+		Code:    http.StatusOK,
+		Message: "Purchase order retrieved successfully",
+		Data:    order,
+	})
+}
+
+// CreatePurchaseOrder godoc
+// @Summary Create a new purchase order
+// @Description Create a draft purchase order in the supplier's currency (admin only). Lines without unit_cost use the product's cost at the supplier.
+// @Tags purchase-orders
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param order body models.PurchaseOrderInput true "Purchase order"
+// @Success 201 {object} models.ApiResponse
+// @Failure 400 {object} models.ApiResponse
+// @Failure 403 {object} models.ApiResponse
+// @Failure 404 {object} models.ApiResponse
+// @Failure 500 {object} models.ApiResponse
+// @Router /purchase-orders [post]
+func (pc *PurchaseOrderController) CreatePurchaseOrder(c *gin.Context) {
+	var input models.PurchaseOrderInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Validation failed",
+			Data:    nil,
+			Errors:  validations.FieldErrors(err),
+		})
+		return
+	}
+
+	userID := 0
+	if user := currentUser(c); user != nil {
+		userID = user.ID
+	}
+
+	order, err := pc.PurchaseOrderService.CreatePurchaseOrder(&input, userID)
+	if err != nil {
+		handlePurchaseOrderError(c, err, "Could not create purchase order")
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.ApiResponse{
+		Status:  "success",
+		Code:    http.StatusCreated,
+		Message: "Purchase order created successfully",
+		Data:    order,
+	})
+}
+
+// UpdatePurchaseOrder godoc
+// @Summary Update a purchase order by ID
+// @Description Replace the supplier, notes and lines of a draft purchase order (admin only)
+// @Tags purchase-orders
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "Purchase order ID"
+// @Param order body models.PurchaseOrderInput true "Purchase order"
+// @Success 200 {object} models.ApiResponse
+// @Failure 400 {object} models.ApiResponse
+// @Failure 403 {object} models.ApiResponse
+// @Failure 404 {object} models.ApiResponse
+// @Failure 409 {object} models.ApiResponse
+// @Failure 500 {object} models.ApiResponse
+// @Router /purchase-orders/{id} [put]
+func (pc *PurchaseOrderController) UpdatePurchaseOrder(c *gin.Context) {
+	id, ok := parsePurchaseOrderID(c)
+	if !ok {
+		return
+	}
+
+	var input models.PurchaseOrderInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Validation failed",
+			Data:    nil,
+			Errors:  validations.FieldErrors(err),
+		})
+		return
+	}
+
+	order, err := pc.PurchaseOrderService.UpdatePurchaseOrder(id, &input)
+	if err != nil {
+		handlePurchaseOrderError(c, err, "Could not update purchase order")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ApiResponse{
+		Status:  "success",
+		Code:    http.StatusOK,
+		Message: "Purchase order updated successfully",
+		Data:    order,
+	})
+}
+
+// SendPurchaseOrder godoc
+// @Summary Send a purchase order
+// @Description Mark a draft purchase order as sent to the supplier (admin only). The expected date is the longest lead time of its products at the supplier.
+// @Tags purchase-orders
+// @Security BearerAuth
+// @Param id path int true "Purchase order ID"
+// @Produce json
+// @Success 200 {object} models.ApiResponse
+// @Failure 400 {object} models.ApiResponse
+// @Failure 403 {object} models.ApiResponse
+// @Failure 404 {object} models.ApiResponse
+// @Failure 409 {object} models.ApiResponse
+// @Failure 500 {object} models.ApiResponse
+// @Router /purchase-orders/{id}/send [post]
+func (pc *PurchaseOrderController) SendPurchaseOrder(c *gin.Context) {
+	id, ok := parsePurchaseOrderID(c)
+	if !ok {
+		return
+	}
+
+	order, err := pc.PurchaseOrderService.SendPurchaseOrder(id)
+	if err != nil {
+		handlePurchaseOrderError(c, err, "Could not send purchase order")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ApiResponse{
+		Status:  "success",
+		Code:    http.StatusOK,
+		Message: "Purchase order sent successfully",
+		Data:    order,
+	})
+}
+
+// ReceivePurchaseOrder godoc
+// @Summary Receive goods of a purchase order
+// @Description Record goods received for a sent purchase order (admin only). Each line is posted as a receipt movement with reference po:<id> into the given or default warehouse. The order becomes partially_received, or received once every line is complete.
+// @Tags purchase-orders
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "Purchase order ID"
+// @Param receipt body models.ReceivePurchaseOrderInput true "Received quantities"
+// @Success 200 {object} models.ApiResponse
+// @Failure 400 {object} models.ApiResponse
+// @Failure 403 {object} models.ApiResponse
+// @Failure 404 {object} models.ApiResponse
+// @Failure 409 {object} models.ApiResponse
+// @Failure 500 {object} models.ApiResponse
+// @Router /purchase-orders/{id}/receive [post]
+func (pc *PurchaseOrderController) ReceivePurchaseOrder(c *gin.Context) {
+	id, ok := parsePurchaseOrderID(c)
+	if !ok {
+		return
+	}
+
+	var input models.ReceivePurchaseOrderInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Validation failed",
+			Data:    nil,
+			Errors:  validations.FieldErrors(err),
+		})
+		return
+	}
+
+	userID := 0
+	if user := currentUser(c); user != nil {
+		userID = user.ID
+	}
+
+	order, err := pc.PurchaseOrderService.ReceivePurchaseOrder(id, &input, userID)
+	if err != nil {
+		handlePurchaseOrderError(c, err, "Could not receive purchase order")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ApiResponse{
+		Status:  "success",
+		Code:    http.StatusOK,
+		Message: "Purchase order received successfully",
+		Data:    order,
+	})
+}
+
+// CancelPurchaseOrder godoc
+// @Summary Cancel a purchase order
+// @Description Cancel a draft or sent purchase order that has not received any goods (admin only)
+// @Tags purchase-orders
+// @Security BearerAuth
+// @Param id path int true "Purchase order ID"
+// @Produce json
+// @Success 200 {object} models.ApiResponse
+// @Failure 400 {object} models.ApiResponse
+// @Failure 403 {object} models.ApiResponse
+// @Failure 404 {object} models.ApiResponse
+// @Failure 409 {object} models.ApiResponse
+// @Failure 500 {object} models.ApiResponse
+// @Router /purchase-orders/{id}/cancel [post]
+func (pc *PurchaseOrderController) CancelPurchaseOrder(c *gin.Context) {
+	id, ok := parsePurchaseOrderID(c)
+	if !ok {
+		return
+	}
+
+	order, err := pc.PurchaseOrderService.CancelPurchaseOrder(id)
+	if err != nil {
+		handlePurchaseOrderError(c, err, "Could not cancel purchase order")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ApiResponse{
+		Status:  "success",
+		Code:    http.StatusOK,
+		Message: "Purchase order cancelled successfully",
+		Data:    order,
+	})
+}
+
+func parsePurchaseOrderID(c *gin.Context) (int, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Invalid purchase order ID",
+			Data:    nil,
+		})
+		return 0, false
+	}
+	return id, true
+}
+
+func handlePurchaseOrderError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, services.ErrPurchaseOrderNotFound):
+		c.JSON(http.StatusNotFound, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusNotFound,
+			Message: "Purchase order not found",
+			Data:    nil,
+		})
+	case errors.Is(err, services.ErrSupplierNotFound):
+		c.JSON(http.StatusNotFound, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusNotFound,
+			Message: "Supplier not found",
+			Data:    nil,
+		})
+	case errors.Is(err, services.ErrProductNotFound):
+		c.JSON(http.StatusNotFound, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusNotFound,
+			Message: "Product not found",
+			Data:    nil,
+		})
+	case errors.Is(err, services.ErrWarehouseNotFound):
+		c.JSON(http.StatusNotFound, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusNotFound,
+			Message: "Warehouse not found",
+			Data:    nil,
+		})
+	case errors.Is(err, services.ErrInvalidPurchaseOrderStatus):
+		c.JSON(http.StatusConflict, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusConflict,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	case errors.Is(err, services.ErrInvalidPurchaseOrderProduct), errors.Is(err, services.ErrUnitCostRequired),
+		errors.Is(err, services.ErrDuplicatePurchaseOrderLine), errors.Is(err, services.ErrPurchaseOrderLineNotFound),
+		errors.Is(err, services.ErrOverReceipt):
+		supplierValidationError(c, "lines", err)
+	default:
+		c.JSON(http.StatusInternalServerError, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusInternalServerError,
+			Message: fallback,
+			Data:    nil,
+		})
+	}
+}
@@ -0,0 +1,223 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"products-api-with-jwt/models"
+	"products-api-with-jwt/services"
+	"products-api-with-jwt/validations"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ReservationController struct {
+	ReservationService *services.ReservationService
+}
+
+// NewReservationController menginisialisasi ReservationController baru
+func NewReservationController(reservationService *services.ReservationService) *ReservationController {
+	return &ReservationController{ReservationService: reservationService}
+}
+
+// CreateReservation godoc
+// @Summary Reserve stock
+// @Description Hold a quantity of a product for a limited time. Reserving a bundle holds the stock of all of its components. Returns 409 when there is not enough available stock.
+// @Tags reservations
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "Product ID"
+// @Param reservation body models.ReservationInput true "Reservation"
+// @Success 201 {object} models.ApiResponse
+// @Failure 400 {object} models.ApiResponse
+// @Failure 404 {object} models.ApiResponse
+// @Failure 409 {object} models.ApiResponse
+// @Failure 500 {object} models.ApiResponse
+// @Router /products/{id}/reservations [post]
+func (rc *ReservationController) CreateReservation(c *gin.Context) {
+	productID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Invalid product ID",
+			Data:    nil,
+		})
+		return
+	}
+
+	var input models.ReservationInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Validation failed",
+			Data:    nil,
+			Errors:  validations.FieldErrors(err),
+		})
+		return
+	}
+
+	userID := 0
+	if user := currentUser(c); user != nil {
+		userID = user.ID
+	}
+
+	reservation, err := rc.ReservationService.Reserve(productID, &input, userID)
+	if err != nil {
+		rc.handleError(c, err, "Could not reserve stock")
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.ApiResponse{
+		Status:  "success",
+		Code:    http.StatusCreated,
+		Message: "Stock reserved successfully",
+		Data:    reservation,
+	})
+}
+
+// ConfirmReservation godoc
+// @Summary Confirm a reservation
+// @Description Turn a held reservation into a sale. The reserved quantity is deducted from stock through the stock ledger. Users can only confirm their own reservations; admins can confirm any.
+// @Tags reservations
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Product ID"
+// @Param reservationId path int true "Reservation ID"
+// @Success 200 {object} models.ApiResponse
+// @Failure 400 {object} models.ApiResponse
+// @Failure 404 {object} models.ApiResponse
+// @Failure 409 {object} models.ApiResponse
+// @Failure 500 {object} models.ApiResponse
+// @Router /products/{id}/reservations/{reservationId}/confirm [post]
+func (rc *ReservationController) ConfirmReservation(c *gin.Context) {
+	productID, reservationID, ok := parseReservationParams(c)
+	if !ok {
+		return
+	}
+
+	userID := 0
+	if user := currentUser(c); user != nil {
+		userID = user.ID
+	}
+
+	reservation, err := rc.ReservationService.Confirm(productID, reservationID, userID, isEditor(c))
+	if err != nil {
+		rc.handleError(c, err, "Could not confirm reservation")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ApiResponse{
+		Status:  "success",
+		Code:    http.StatusOK,
+		Message: "Reservation confirmed successfully",
+		Data:    reservation,
+	})
+}
+
+// ReleaseReservation godoc
+// @Summary Release a reservation
+// @Description Release a held reservation and return its quantity to available stock. Users can only release their own reservations; admins can release any.
+// @Tags reservations
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Product ID"
+// @Param reservationId path int true "Reservation ID"
+// @Success 200 {object} models.ApiResponse
+// @Failure 400 {object} models.ApiResponse
+// @Failure 404 {object} models.ApiResponse
+// @Failure 409 {object} models.ApiResponse
+// @Failure 500 {object} models.ApiResponse
+// @Router /products/{id}/reservations/{reservationId}/release [post]
+func (rc *ReservationController) ReleaseReservation(c *gin.Context) {
+	productID, reservationID, ok := parseReservationParams(c)
+	if !ok {
+		return
+	}
+
+	userID := 0
+	if user := currentUser(c); user != nil {
+		userID = user.ID
+	}
+
+	reservation, err := rc.ReservationService.Release(productID, reservationID, userID, isEditor(c))
+	if err != nil {
+		rc.handleError(c, err, "Could not release reservation")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ApiResponse{
+		Status:  "success",
+		Code:    http.StatusOK,
+		Message: "Reservation released successfully",
+		Data:    reservation,
+	})
+}
+
+func parseReservationParams(c *gin.Context) (int, int, bool) {
+	productID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Invalid product ID",
+			Data:    nil,
+		})
+		return 0, 0, false
+	}
+
+	reservationID, err := strconv.Atoi(c.Param("reservationId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Invalid reservation ID",
+			Data:    nil,
+		})
+		return 0, 0, false
+	}
+	return productID, reservationID, true
+}
+
+func (rc *ReservationController) handleError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, services.ErrProductNotFound):
+		c.JSON(http.StatusNotFound, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusNotFound,
+			Message: "Product not found",
+			Data:    nil,
+		})
+	case errors.Is(err, services.ErrReservationNotFound):
+		c.JSON(http.StatusNotFound, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusNotFound,
+			Message: "Reservation not found",
+			Data:    nil,
+		})
+	case errors.Is(err, services.ErrInsufficientStock):
+		c.JSON(http.StatusConflict, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusConflict,
+			Message: "Insufficient stock",
+			Data:    nil,
+		})
+	case errors.Is(err, services.ErrReservationNotActive):
+		c.JSON(http.StatusConflict, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusConflict,
+			Message: "Reservation is no longer held",
+			Data:    nil,
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusInternalServerError,
+			Message: fallback,
+			Data:    nil,
+		})
+	}
+}
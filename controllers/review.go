@@ -0,0 +1,374 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"slices"
+	"strconv"
+
+	"products-api-with-jwt/models"
+	"products-api-with-jwt/services"
+	"products-api-with-jwt/validations"
+
+	"github.com/gin-gonic/gin"
+)
+
+var reviewStatuses = []string{models.ReviewPending, models.ReviewApproved, models.ReviewRejected}
+
+type ReviewController struct {
+	ReviewService *services.ReviewService
+}
+
+// NewReviewController menginisialisasi ReviewController baru
+func NewReviewController(reviewService *services.ReviewService) *ReviewController {
+	return &ReviewController{ReviewService: reviewService}
+}
+
+// GetProductReviews godoc
+// @Summary Get reviews of a product
+// @Description Get the approved reviews of a product, newest first. Admins can list pending or rejected reviews with status.
+// @Tags reviews
+// @Security BearerAuth
+// @Param id path int true "Product ID"
+// @Param status query string false "Status (admin only)" Enums(pending, approved, rejected)
+// @Produce json
+// @Success 200 {object} models.ApiResponse
+// @Failure 400 {object} models.ApiResponse
+// @Failure 404 {object} models.ApiResponse
+// @Failure 500 {object} models.ApiResponse
+// @Router /products/{id}/reviews [get]
+func (rc *ReviewController) GetProductReviews(c *gin.Context) {
+	id, ok := parseProductID(c)
+	if !ok {
+		return
+	}
+	status, ok := parseReviewStatus(c)
+	if !ok {
+		return
+	}
+	if !isEditor(c) {
+		status = ""
+	}
+
+	reviews, err := rc.ReviewService.GetProductReviews(id, status)
+	if err != nil {
+		handleReviewError(c, err, "Could not retrieve reviews")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ApiResponse{
+		Status:  "success",
+		Code:    http.StatusOK,
+		Message: "Reviews retrieved successfully",
+		Data:    reviews,
+		Count:   len(reviews),
+	})
+}
+
+// CreateReview godoc
+// @Summary Review a product
+// @Description Post a 1-5 rating and review for a published product. Each user can review a product once. The review is pending until an admin approves it, and only approved reviews count in the product rating.
+// @Tags reviews
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "Product ID"
+// @Param review body models.ReviewInput true "Review"
+// @Success 201 {object} models.ApiResponse
+// @Failure 400 {object} models.ApiResponse
+// @Failure 404 {object} models.ApiResponse
+// @Failure 409 {object} models.ApiResponse
+// @Failure 500 {object} models.ApiResponse
+// @Router /products/{id}/reviews [post]
+func (rc *ReviewController) CreateReview(c *gin.Context) {
+	id, ok := parseProductID(c)
+	if !ok {
+		return
+	}
+
+	var input models.ReviewInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Validation failed",
+			Data:    nil,
+			Errors:  validations.FieldErrors(err),
+		})
+		return
+	}
+
+	userID := 0
+	if user := currentUser(c); user != nil {
+		userID = user.ID
+	}
+
+	review, err := rc.ReviewService.CreateReview(id, &input, userID)
+	if err != nil {
+		handleReviewError(c, err, "Could not create review")
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.ApiResponse{
+		Status:  "success",
+		Code:    http.StatusCreated,
+		Message: "Review submitted for moderation",
+		Data:    review,
+	})
+}
+
+// UpdateReview godoc
+// @Summary Update your review
+// @Description Change the rating and text of your own review. The review goes back to pending and leaves the product rating until it is approved again.
+// @Tags reviews
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "Product ID"
+// @Param reviewId path int true "Review ID"
+// @Param review body models.ReviewInput true "Review"
+// @Success 200 {object} models.ApiResponse
+// @Failure 400 {object} models.ApiResponse
+// @Failure 404 {object} models.ApiResponse
+// @Failure 500 {object} models.ApiResponse
+// @Router /products/{id}/reviews/{reviewId} [put]
+func (rc *ReviewController) UpdateReview(c *gin.Context) {
+	id, ok := parseProductID(c)
+	if !ok {
+		return
+	}
+	reviewID, ok := parseReviewID(c, "reviewId")
+	if !ok {
+		return
+	}
+
+	var input models.ReviewInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Validation failed",
+			Data:    nil,
+			Errors:  validations.FieldErrors(err),
+		})
+		return
+	}
+
+	userID := 0
+	if user := currentUser(c); user != nil {
+		userID = user.ID
+	}
+
+	review, err := rc.ReviewService.UpdateReview(id, reviewID, &input, userID)
+	if err != nil {
+		handleReviewError(c, err, "Could not update review")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ApiResponse{
+		Status:  "success",
+		Code:    http.StatusOK,
+		Message: "Review submitted for moderation",
+		Data:    review,
+	})
+}
+
+// DeleteReview godoc
+// @Summary Delete a review
+// @Description Delete your own review. Admins can delete any review.
+// @Tags reviews
+// @Security BearerAuth
+// @Param id path int true "Product ID"
+// @Param reviewId path int true "Review ID"
+// @Produce json
+// @Success 200 {object} models.ApiResponse
+// @Failure 400 {object} models.ApiResponse
+// @Failure 404 {object} models.ApiResponse
+// @Failure 500 {object} models.ApiResponse
+// @Router /products/{id}/reviews/{reviewId} [delete]
+func (rc *ReviewController) DeleteReview(c *gin.Context) {
+	id, ok := parseProductID(c)
+	if !ok {
+		return
+	}
+	reviewID, ok := parseReviewID(c, "reviewId")
+	if !ok {
+		return
+	}
+
+	// Admin boleh menghapus review siapa pun
+	userID := 0
+	if user := currentUser(c); user != nil && !isEditor(c) {
+		userID = user.ID
+	}
+
+	if err := rc.ReviewService.DeleteReview(id, reviewID, userID); err != nil {
+		handleReviewError(c, err, "Could not delete review")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ApiResponse{
+		Status:  "success",
+		Code:    http.StatusOK,
+		Message: "Review deleted successfully",
+		Data:    nil,
+	})
+}
+
+// GetReviews godoc
+// @Summary Get reviews for moderation
+// @Description Get reviews of all products, oldest first, optionally filtered by status (admin only)
+// @Tags reviews
+// @Security BearerAuth
+// @Param status query string false "Status" Enums(pending, approved, rejected)
+// @Produce json
+// @Success 200 {object} models.ApiResponse
+// @Failure 400 {object} models.ApiResponse
+// @Failure 403 {object} models.ApiResponse
+// @Failure 500 {object} models.ApiResponse
+// @Router /reviews [get]
+func (rc *ReviewController) GetReviews(c *gin.Context) {
+	status, ok := parseReviewStatus(c)
+	if !ok {
+		return
+	}
+
+	reviews, err := rc.ReviewService.GetReviews(status)
+	if err != nil {
+		handleReviewError(c, err, "Could not retrieve reviews")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ApiResponse{
+		Status:  "success",
+		Code:    http.StatusOK,
+		Message: "Reviews retrieved successfully",
+		Data:    reviews,
+		Count:   len(reviews),
+	})
+}
+
+// ModerateReview godoc
+// @Summary Approve or reject a review
+// @Description Approve or reject a review (admin only). The product's RatingAverage and RatingCount are recalculated from its approved reviews.
+// @Tags reviews
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "Review ID"
+// @Param moderation body models.ReviewModerationInput true "Moderation"
+// @Success 200 {object} models.ApiResponse
+// @Failure 400 {object} models.ApiResponse
+// @Failure 403 {object} models.ApiResponse
+// @Failure 404 {object} models.ApiResponse
+// @Failure 500 {object} models.ApiResponse
+// @Router /reviews/{id}/moderation [put]
+func (rc *ReviewController) ModerateReview(c *gin.Context) {
+	id, ok := parseReviewID(c, "id")
+	if !ok {
+		return
+	}
+
+	var input models.ReviewModerationInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Validation failed",
+			Data:    nil,
+			Errors:  validations.FieldErrors(err),
+		})
+		return
+	}
+
+	userID := 0
+	if user := currentUser(c); user != nil {
+		userID = user.ID
+	}
+
+	review, err := rc.ReviewService.ModerateReview(id, &input, userID)
+	if err != nil {
+		handleReviewError(c, err, "Could not moderate review")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ApiResponse{
+		Status:  "success",
+		Code:    http.StatusOK,
+		Message: "Review moderated successfully",
+		Data:    review,
+	})
+}
+
+func parseReviewID(c *gin.Context, param string) (int, bool) {
+	id, err := strconv.Atoi(c.Param(param))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Invalid review ID",
+			Data:    nil,
+		})
+		return 0, false
+	}
+	return id, true
+}
+
+func parseReviewStatus(c *gin.Context) (string, bool) {
+	status := c.Query("status")
+	if status != "" && !slices.Contains(reviewStatuses, status) {
+		c.JSON(http.StatusBadRequest, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Validation failed",
+			Data:    nil,
+			Errors: []models.FieldError{{
+				Field:   "status",
+				Code:    models.ValidationInvalidValue,
+				Message: "status must be one of pending, approved, rejected",
+			}},
+		})
+		return "", false
+	}
+	return status, true
+}
+
+func handleReviewError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, services.ErrReviewNotFound):
+		c.JSON(http.StatusNotFound, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusNotFound,
+			Message: "Review not found",
+			Data:    nil,
+		})
+	case errors.Is(err, services.ErrProductNotFound):
+		c.JSON(http.StatusNotFound, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusNotFound,
+			Message: "Product not found",
+			Data:    nil,
+		})
+	case errors.Is(err, services.ErrDuplicateReview):
+		c.JSON(http.StatusConflict, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusConflict,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	case errors.Is(err, services.ErrProductNotAvailable):
+		c.JSON(http.StatusBadRequest, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Only published products can be reviewed",
+			Data:    nil,
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusInternalServerError,
+			Message: fallback,
+			Data:    nil,
+		})
+	}
+}
@@ -0,0 +1,317 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"products-api-with-jwt/models"
+	"products-api-with-jwt/services"
+	"products-api-with-jwt/validations"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AdjustStock godoc
+// @Summary Record a stock movement
+// @Description Append a receipt, sale, adjustment or return to the product's stock ledger and update its stock. Quantity is positive for receipt, sale and return; adjustments may be negative and require a reason. Without warehouse_id, incoming stock goes to the default warehouse and outgoing stock is taken from the fullest warehouses first, which can produce several ledger entries. For bundles only sale and return are allowed, and they are recorded on every component. With variant_id the movement changes that variant's stock instead of the product's.
+// @Tags stock
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "Product ID"
+// @Param movement body models.StockAdjustInput true "Stock movement"
+// @Success 201 {object} models.ApiResponse
+// @Failure 400 {object} models.ApiResponse
+// @Failure 404 {object} models.ApiResponse
+// @Failure 409 {object} models.ApiResponse
+// @Failure 500 {object} models.ApiResponse
+// @Router /products/{id}/stock/adjust [post]
+func (pc *ProductController) AdjustStock(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Invalid product ID",
+			Data:    nil,
+		})
+		return
+	}
+
+	var input models.StockAdjustInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Validation failed",
+			Data:    nil,
+			Errors:  validations.FieldErrors(err),
+		})
+		return
+	}
+
+	userID := 0
+	if user := currentUser(c); user != nil {
+		userID = user.ID
+	}
+
+	movements, err := pc.ProductService.AdjustStock(id, &input, userID)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidStockQuantity):
+			c.JSON(http.StatusBadRequest, models.ApiResponse{
+				Status:  "error",
+				Code:    http.StatusBadRequest,
+				Message: "Validation failed",
+				Data:    nil,
+				Errors: []models.FieldError{{
+					Field:   "quantity",
+					Code:    models.ValidationOutOfRange,
+					Message: err.Error(),
+				}},
+			})
+		case errors.Is(err, services.ErrStockReasonRequired):
+			c.JSON(http.StatusBadRequest, models.ApiResponse{
+				Status:  "error",
+				Code:    http.StatusBadRequest,
+				Message: "Validation failed",
+				Data:    nil,
+				Errors: []models.FieldError{{
+					Field:   "reason",
+					Code:    models.ValidationRequired,
+					Message: err.Error(),
+				}},
+			})
+		case errors.Is(err, services.ErrProductNotFound):
+			c.JSON(http.StatusNotFound, models.ApiResponse{
+				Status:  "error",
+				Code:    http.StatusNotFound,
+				Message: "Product not found",
+				Data:    nil,
+			})
+		case errors.Is(err, services.ErrWarehouseNotFound):
+			c.JSON(http.StatusNotFound, models.ApiResponse{
+				Status:  "error",
+				Code:    http.StatusNotFound,
+				Message: "Warehouse not found",
+				Data:    nil,
+			})
+		case errors.Is(err, services.ErrVariantNotFound):
+			c.JSON(http.StatusNotFound, models.ApiResponse{
+				Status:  "error",
+				Code:    http.StatusNotFound,
+				Message: "Variant not found",
+				Data:    nil,
+			})
+		case errors.Is(err, services.ErrInsufficientStock):
+			c.JSON(http.StatusConflict, models.ApiResponse{
+				Status:  "error",
+				Code:    http.StatusConflict,
+				Message: "Insufficient stock",
+				Data:    nil,
+			})
+		case errors.Is(err, services.ErrBundleStock):
+			c.JSON(http.StatusConflict, models.ApiResponse{
+				Status:  "error",
+				Code:    http.StatusConflict,
+				Message: err.Error(),
+				Data:    nil,
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, models.ApiResponse{
+				Status:  "error",
+				Code:    http.StatusInternalServerError,
+				Message: "Could not adjust stock",
+				Data:    nil,
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.ApiResponse{
+		Status:  "success",
+		Code:    http.StatusCreated,
+		Message: "Stock adjusted successfully",
+		Data:    movements,
+		Count:   len(movements),
+	})
+}
+
+// TransferStock godoc
+// @Summary Transfer stock between warehouses
+// @Description Move a quantity of a product from one warehouse to another in a single transaction. The product's total stock does not change; the ledger records one transfer entry per warehouse.
+// @Tags stock
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "Product ID"
+// @Param transfer body models.StockTransferInput true "Stock transfer"
+// @Success 201 {object} models.ApiResponse
+// @Failure 400 {object} models.ApiResponse
+// @Failure 404 {object} models.ApiResponse
+// @Failure 409 {object} models.ApiResponse
+// @Failure 500 {object} models.ApiResponse
+// @Router /products/{id}/stock/transfer [post]
+func (pc *ProductController) TransferStock(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Invalid product ID",
+			Data:    nil,
+		})
+		return
+	}
+
+	var input models.StockTransferInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Validation failed",
+			Data:    nil,
+			Errors:  validations.FieldErrors(err),
+		})
+		return
+	}
+
+	userID := 0
+	if user := currentUser(c); user != nil {
+		userID = user.ID
+	}
+
+	movements, err := pc.ProductService.TransferStock(id, &input, userID)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrProductNotFound):
+			c.JSON(http.StatusNotFound, models.ApiResponse{
+				Status:  "error",
+				Code:    http.StatusNotFound,
+				Message: "Product not found",
+				Data:    nil,
+			})
+		case errors.Is(err, services.ErrWarehouseNotFound):
+			c.JSON(http.StatusNotFound, models.ApiResponse{
+				Status:  "error",
+				Code:    http.StatusNotFound,
+				Message: "Warehouse not found",
+				Data:    nil,
+			})
+		case errors.Is(err, services.ErrInsufficientStock):
+			c.JSON(http.StatusConflict, models.ApiResponse{
+				Status:  "error",
+				Code:    http.StatusConflict,
+				Message: "Insufficient stock in source warehouse",
+				Data:    nil,
+			})
+		case errors.Is(err, services.ErrBundleStock):
+			c.JSON(http.StatusConflict, models.ApiResponse{
+				Status:  "error",
+				Code:    http.StatusConflict,
+				Message: err.Error(),
+				Data:    nil,
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, models.ApiResponse{
+				Status:  "error",
+				Code:    http.StatusInternalServerError,
+				Message: "Could not transfer stock",
+				Data:    nil,
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.ApiResponse{
+		Status:  "success",
+		Code:    http.StatusCreated,
+		Message: "Stock transferred successfully",
+		Data:    movements,
+		Count:   len(movements),
+	})
+}
+
+// GetStockHistory godoc
+// @Summary Get stock history
+// @Description Get the stock ledger of a product, newest entry first
+// @Tags stock
+// @Security BearerAuth
+// @Param id path int true "Product ID"
+// @Produce json
+// @Success 200 {object} models.ApiResponse
+// @Failure 400 {object} models.ApiResponse
+// @Failure 404 {object} models.ApiResponse
+// @Failure 500 {object} models.ApiResponse
+// @Router /products/{id}/stock/history [get]
+func (pc *ProductController) GetStockHistory(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Invalid product ID",
+			Data:    nil,
+		})
+		return
+	}
+
+	movements, err := pc.ProductService.GetStockHistory(id)
+	if err != nil {
+		if errors.Is(err, services.ErrProductNotFound) {
+			c.JSON(http.StatusNotFound, models.ApiResponse{
+				Status:  "error",
+				Code:    http.StatusNotFound,
+				Message: "Product not found",
+				Data:    nil,
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusInternalServerError,
+			Message: "Could not retrieve stock history",
+			Data:    nil,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ApiResponse{
+		Status:  "success",
+		Code:    http.StatusOK,
+		Message: "Stock history retrieved successfully",
+		Data:    movements,
+		Count:   len(movements),
+	})
+}
+
+// GetLowStockProducts godoc
+// @Summary List low-stock products
+// @Description Get the products whose stock is at or below their reorder point, largest shortfall first
+// @Tags stock
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} models.ApiResponse
+// @Failure 500 {object} models.ApiResponse
+// @Router /products/low-stock [get]
+func (pc *ProductController) GetLowStockProducts(c *gin.Context) {
+	products, err := pc.ProductService.GetLowStockProducts()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusInternalServerError,
+			Message: "Could not retrieve low stock products",
+			Data:    nil,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ApiResponse{
+		Status:  "success",
+		Code:    http.StatusOK,
+		Message: "Low stock products retrieved successfully",
+		Data:    products,
+		Count:   len(products),
+	})
+}
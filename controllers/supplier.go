@@ -0,0 +1,421 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"products-api-with-jwt/models"
+	"products-api-with-jwt/services"
+	"products-api-with-jwt/validations"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SupplierController struct {
+	SupplierService *services.SupplierService
+}
+
+// NewSupplierController menginisialisasi SupplierController baru
+func NewSupplierController(supplierService *services.SupplierService) *SupplierController {
+	return &SupplierController{SupplierService: supplierService}
+}
+
+// GetSuppliers godoc
+// @Summary Get all suppliers
+// @Description Get a list of all suppliers ordered by name
+// @Tags suppliers
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} models.ApiResponse
+// @Failure 500 {object} models.ApiResponse
+// @Router /suppliers [get]
+func (sc *SupplierController) GetSuppliers(c *gin.Context) {
+	suppliers, err := sc.SupplierService.GetAllSuppliers()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusInternalServerError,
+			Message: "Could not retrieve suppliers",
+			Data:    nil,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ApiResponse{
+		Status:  "success",
+		Code:    http.StatusOK,
+		Message: "Suppliers retrieved successfully",
+		Data:    suppliers,
+		Count:   len(suppliers),
+	})
+}
+
+// GetSupplierByID godoc
+// @Summary Get supplier by ID
+// @Description Get details of a supplier by its ID
+// @Tags suppliers
+// @Security BearerAuth
+// @Param id path int true "Supplier ID"
+// @Produce json
+// @Success 200 {object} models.ApiResponse
+// @Failure 400 {object} models.ApiResponse
+// @Failure 404 {object} models.ApiResponse
+// @Router /suppliers/{id} [get]
+func (sc *SupplierController) GetSupplierByID(c *gin.Context) {
+	id, ok := parseSupplierID(c)
+	if !ok {
+		return
+	}
+
+	supplier, err := sc.SupplierService.GetSupplierByID(id)
+	if err != nil {
+		sc.handleError(c, err, "Could not retrieve supplier")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ApiResponse{
+		Status:  "success",
+		Code:    http.StatusOK,
+		Message: "Supplier retrieved successfully",
+		Data:    supplier,
+	})
+}
+
+// CreateSupplier godoc
+// @Summary Create a new supplier
+// @Description Create a new supplier (admin only). The currency defaults to IDR and cannot be changed later.
+// @Tags suppliers
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param supplier body models.SupplierInput true "Supplier"
+// @Success 201 {object} models.ApiResponse
+// @Failure 400 {object} models.ApiResponse
+// @Failure 403 {object} models.ApiResponse
+// @Failure 409 {object} models.ApiResponse
+// @Failure 500 {object} models.ApiResponse
+// @Router /suppliers [post]
+func (sc *SupplierController) CreateSupplier(c *gin.Context) {
+	var input models.SupplierInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Validation failed",
+			Data:    nil,
+			Errors:  validations.FieldErrors(err),
+		})
+		return
+	}
+
+	supplier, err := sc.SupplierService.CreateSupplier(&input)
+	if err != nil {
+		sc.handleError(c, err, "Could not create supplier")
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.ApiResponse{
+		Status:  "success",
+		Code:    http.StatusCreated,
+		Message: "Supplier created successfully",
+		Data:    supplier,
+	})
+}
+
+// UpdateSupplier godoc
+// @Summary Update a supplier by ID
+// @Description Update a supplier's name and contact details (admin only)
+// @Tags suppliers
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "Supplier ID"
+// @Param supplier body models.SupplierInput true "Supplier"
+// @Success 200 {object} models.ApiResponse
+// @Failure 400 {object} models.ApiResponse
+// @Failure 403 {object} models.ApiResponse
+// @Failure 404 {object} models.ApiResponse
+// @Failure 409 {object} models.ApiResponse
+// @Failure 500 {object} models.ApiResponse
+// @Router /suppliers/{id} [put]
+func (sc *SupplierController) UpdateSupplier(c *gin.Context) {
+	id, ok := parseSupplierID(c)
+	if !ok {
+		return
+	}
+
+	var input models.SupplierInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Validation failed",
+			Data:    nil,
+			Errors:  validations.FieldErrors(err),
+		})
+		return
+	}
+
+	supplier, err := sc.SupplierService.UpdateSupplier(id, &input)
+	if err != nil {
+		sc.handleError(c, err, "Could not update supplier")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ApiResponse{
+		Status:  "success",
+		Code:    http.StatusOK,
+		Message: "Supplier updated successfully",
+		Data:    supplier,
+	})
+}
+
+// DeleteSupplier godoc
+// @Summary Delete a supplier by ID
+// @Description Delete a supplier and its product links (admin only). Suppliers with purchase orders cannot be deleted.
+// @Tags suppliers
+// @Security BearerAuth
+// @Param id path int true "Supplier ID"
+// @Produce json
+// @Success 200 {object} models.ApiResponse
+// @Failure 400 {object} models.ApiResponse
+// @Failure 403 {object} models.ApiResponse
+// @Failure 404 {object} models.ApiResponse
+// @Failure 409 {object} models.ApiResponse
+// @Failure 500 {object} models.ApiResponse
+// @Router /suppliers/{id} [delete]
+func (sc *SupplierController) DeleteSupplier(c *gin.Context) {
+	id, ok := parseSupplierID(c)
+	if !ok {
+		return
+	}
+
+	if err := sc.SupplierService.DeleteSupplier(id); err != nil {
+		sc.handleError(c, err, "Could not delete supplier")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ApiResponse{
+		Status:  "success",
+		Code:    http.StatusOK,
+		Message: "Supplier deleted successfully",
+		Data:    nil,
+	})
+}
+
+// GetSupplierProducts godoc
+// @Summary Get products of a supplier
+// @Description Get the products that can be purchased from a supplier with their cost and lead time
+// @Tags suppliers
+// @Security BearerAuth
+// @Param id path int true "Supplier ID"
+// @Produce json
+// @Success 200 {object} models.ApiResponse
+// @Failure 400 {object} models.ApiResponse
+// @Failure 404 {object} models.ApiResponse
+// @Failure 500 {object} models.ApiResponse
+// @Router /suppliers/{id}/products [get]
+func (sc *SupplierController) GetSupplierProducts(c *gin.Context) {
+	id, ok := parseSupplierID(c)
+	if !ok {
+		return
+	}
+
+	links, err := sc.SupplierService.GetSupplierProducts(id)
+	if err != nil {
+		sc.handleError(c, err, "Could not retrieve supplier products")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ApiResponse{
+		Status:  "success",
+		Code:    http.StatusOK,
+		Message: "Supplier products retrieved successfully",
+		Data:    links,
+		Count:   len(links),
+	})
+}
+
+// SetSupplierProduct godoc
+// @Summary Link a product to a supplier
+// @Description Create or replace the link between a supplier and a product (admin only). The cost is in the supplier's currency and is the default unit cost on purchase orders.
+// @Tags suppliers
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "Supplier ID"
+// @Param productId path int true "Product ID"
+// @Param link body models.SupplierProductInput true "Supplier product"
+// @Success 200 {object} models.ApiResponse
+// @Failure 400 {object} models.ApiResponse
+// @Failure 403 {object} models.ApiResponse
+// @Failure 404 {object} models.ApiResponse
+// @Failure 500 {object} models.ApiResponse
+// @Router /suppliers/{id}/products/{productId} [put]
+func (sc *SupplierController) SetSupplierProduct(c *gin.Context) {
+	id, ok := parseSupplierID(c)
+	if !ok {
+		return
+	}
+	productID, ok := parseSupplierProductID(c)
+	if !ok {
+		return
+	}
+
+	var input models.SupplierProductInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Validation failed",
+			Data:    nil,
+			Errors:  validations.FieldErrors(err),
+		})
+		return
+	}
+
+	link, err := sc.SupplierService.SetSupplierProduct(id, productID, &input)
+	if err != nil {
+		sc.handleError(c, err, "Could not link product to supplier")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ApiResponse{
+		Status:  "success",
+		Code:    http.StatusOK,
+		Message: "Supplier product saved successfully",
+		Data:    link,
+	})
+}
+
+// DeleteSupplierProduct godoc
+// @Summary Unlink a product from a supplier
+// @Description Remove the link between a supplier and a product (admin only). Existing purchase orders keep their unit costs.
+// @Tags suppliers
+// @Security BearerAuth
+// @Param id path int true "Supplier ID"
+// @Param productId path int true "Product ID"
+// @Produce json
+// @Success 200 {object} models.ApiResponse
+// @Failure 400 {object} models.ApiResponse
+// @Failure 403 {object} models.ApiResponse
+// @Failure 404 {object} models.ApiResponse
+// @Failure 500 {object} models.ApiResponse
+// @Router /suppliers/{id}/products/{productId} [delete]
+func (sc *SupplierController) DeleteSupplierProduct(c *gin.Context) {
+	id, ok := parseSupplierID(c)
+	if !ok {
+		return
+	}
+	productID, ok := parseSupplierProductID(c)
+	if !ok {
+		return
+	}
+
+	if err := sc.SupplierService.DeleteSupplierProduct(id, productID); err != nil {
+		sc.handleError(c, err, "Could not unlink product from supplier")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ApiResponse{
+		Status:  "success",
+		Code:    http.StatusOK,
+		Message: "Supplier product removed successfully",
+		Data:    nil,
+	})
+}
+
+func parseSupplierID(c *gin.Context) (int, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Invalid supplier ID",
+			Data:    nil,
+		})
+		return 0, false
+	}
+	return id, true
+}
+
+func parseSupplierProductID(c *gin.Context) (int, bool) {
+	id, err := strconv.Atoi(c.Param("productId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Invalid product ID",
+			Data:    nil,
+		})
+		return 0, false
+	}
+	return id, true
+}
+
+func (sc *SupplierController) handleError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, services.ErrSupplierNotFound):
+		c.JSON(http.StatusNotFound, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusNotFound,
+			Message: "Supplier not found",
+			Data:    nil,
+		})
+	case errors.Is(err, services.ErrProductNotFound):
+		c.JSON(http.StatusNotFound, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusNotFound,
+			Message: "Product not found",
+			Data:    nil,
+		})
+	case errors.Is(err, services.ErrSupplierProductNotFound):
+		c.JSON(http.StatusNotFound, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusNotFound,
+			Message: "Product is not linked to this supplier",
+			Data:    nil,
+		})
+	case errors.Is(err, services.ErrDuplicateSupplierName):
+		c.JSON(http.StatusConflict, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusConflict,
+			Message: "Supplier name already exists",
+			Data:    nil,
+		})
+	case errors.Is(err, services.ErrSupplierInUse):
+		c.JSON(http.StatusConflict, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusConflict,
+			Message: "Supplier still has purchase orders",
+			Data:    nil,
+		})
+	case errors.Is(err, services.ErrSupplierCurrencyChange):
+		supplierValidationError(c, "currency", err)
+	case errors.Is(err, services.ErrInvalidPurchaseOrderProduct):
+		supplierValidationError(c, "product_id", err)
+	default:
+		c.JSON(http.StatusInternalServerError, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusInternalServerError,
+			Message: fallback,
+			Data:    nil,
+		})
+	}
+}
+
+// supplierValidationError mengirim error validasi dari service supplier dan purchase order sebagai error field
+func supplierValidationError(c *gin.Context, field string, err error) {
+	c.JSON(http.StatusBadRequest, models.ApiResponse{
+		Status:  "error",
+		Code:    http.StatusBadRequest,
+		Message: "Validation failed",
+		Data:    nil,
+		Errors: []models.FieldError{{
+			Field:   field,
+			Code:    models.ValidationInvalidValue,
+			Message: err.Error(),
+		}},
+	})
+}
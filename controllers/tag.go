@@ -0,0 +1,142 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"products-api-with-jwt/models"
+	"products-api-with-jwt/services"
+	"products-api-with-jwt/validations"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TagController struct {
+	TagService *services.TagService
+}
+
+// NewTagController menginisialisasi TagController baru
+func NewTagController(tagService *services.TagService) *TagController {
+	return &TagController{TagService: tagService}
+}
+
+// GetTags godoc
+// @Summary Get all tags
+// @Description Get all tags with the number of products using each tag, most used first
+// @Tags tags
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} models.ApiResponse
+// @Failure 500 {object} models.ApiResponse
+// @Router /tags [get]
+func (tc *TagController) GetTags(c *gin.Context) {
+	tags, err := tc.TagService.GetAllTags()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusInternalServerError,
+			Message: "Could not retrieve tags",
+			Data:    nil,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ApiResponse{
+		Status:  "success",
+		Code:    http.StatusOK,
+		Message: "Tags retrieved successfully",
+		Data:    tags,
+		Count:   len(tags),
+	})
+}
+
+// AddProductTags godoc
+// @Summary Add tags to a product
+// @Description Attach tags to a product. Tags are normalized (lowercase, spaces become "-") and created when they do not exist yet. Returns the product's tags.
+// @Tags tags
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "Product ID"
+// @Param tags body models.TagsInput true "Tags"
+// @Success 200 {object} models.ApiResponse
+// @Failure 400 {object} models.ApiResponse
+// @Failure 404 {object} models.ApiResponse
+// @Failure 500 {object} models.ApiResponse
+// @Router /products/{id}/tags [post]
+func (tc *TagController) AddProductTags(c *gin.Context) {
+	tc.updateProductTags(c, tc.TagService.AddProductTags, "Tags added successfully")
+}
+
+// RemoveProductTags godoc
+// @Summary Remove tags from a product
+// @Description Detach tags from a product. Tags no longer used by any product are deleted. Returns the product's remaining tags.
+// @Tags tags
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "Product ID"
+// @Param tags body models.TagsInput true "Tags"
+// @Success 200 {object} models.ApiResponse
+// @Failure 400 {object} models.ApiResponse
+// @Failure 404 {object} models.ApiResponse
+// @Failure 500 {object} models.ApiResponse
+// @Router /products/{id}/tags [delete]
+func (tc *TagController) RemoveProductTags(c *gin.Context) {
+	tc.updateProductTags(c, tc.TagService.RemoveProductTags, "Tags removed successfully")
+}
+
+// updateProductTags berisi alur yang sama untuk menambah dan melepas tag produk
+func (tc *TagController) updateProductTags(c *gin.Context, update func(int, []string) ([]models.Tag, error), message string) {
+	productID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Invalid product ID",
+			Data:    nil,
+		})
+		return
+	}
+
+	var input models.TagsInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Validation failed",
+			Data:    nil,
+			Errors:  validations.FieldErrors(err),
+		})
+		return
+	}
+
+	tags, err := update(productID, input.Tags)
+	if err != nil {
+		if errors.Is(err, services.ErrProductNotFound) {
+			c.JSON(http.StatusNotFound, models.ApiResponse{
+				Status:  "error",
+				Code:    http.StatusNotFound,
+				Message: "Product not found",
+				Data:    nil,
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusInternalServerError,
+			Message: "Could not update product tags",
+			Data:    nil,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ApiResponse{
+		Status:  "success",
+		Code:    http.StatusOK,
+		Message: message,
+		Data:    tags,
+		Count:   len(tags),
+	})
+}
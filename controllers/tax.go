@@ -0,0 +1,379 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"products-api-with-jwt/models"
+	"products-api-with-jwt/services"
+	"products-api-with-jwt/validations"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TaxController struct {
+	TaxService *services.TaxService
+}
+
+// NewTaxController menginisialisasi TaxController baru
+func NewTaxController(taxService *services.TaxService) *TaxController {
+	return &TaxController{TaxService: taxService}
+}
+
+// GetTaxClasses godoc
+// @Summary Get all tax classes
+// @Description Get all tax classes with their rates per region, newest rate first
+// @Tags taxes
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} models.ApiResponse
+// @Failure 500 {object} models.ApiResponse
+// @Router /tax-classes [get]
+func (tc *TaxController) GetTaxClasses(c *gin.Context) {
+	classes, err := tc.TaxService.GetAllTaxClasses()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusInternalServerError,
+			Message: "Could not retrieve tax classes",
+			Data:    nil,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ApiResponse{
+		Status:  "success",
+		Code:    http.StatusOK,
+		Message: "Tax classes retrieved successfully",
+		Data:    classes,
+		Count:   len(classes),
+	})
+}
+
+// GetTaxClassByID godoc
+// @Summary Get tax class by ID
+// @Description Get a tax class with its rates per region, newest rate first
+// @Tags taxes
+// @Security BearerAuth
+// @Param id path int true "Tax class ID"
+// @Produce json
+// @Success 200 {object} models.ApiResponse
+// @Failure 400 {object} models.ApiResponse
+// @Failure 404 {object} models.ApiResponse
+// @Router /tax-classes/{id} [get]
+func (tc *TaxController) GetTaxClassByID(c *gin.Context) {
+	id, ok := parseTaxClassID(c)
+	if !ok {
+		return
+	}
+
+	class, err := tc.TaxService.GetTaxClassByID(id)
+	if err != nil {
+		handleTaxError(c, err, "Could not retrieve tax class")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ApiResponse{
+		Status:  "success",
+		Code:    http.StatusOK,
+		Message: "Tax class retrieved successfully",
+		Data:    class,
+	})
+}
+
+// CreateTaxClass godoc
+// @Summary Create a new tax class
+// @Description Create a new tax class such as standard or exempt (admin only)
+// @Tags taxes
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param class body models.TaxClassInput true "Tax class"
+// @Success 201 {object} models.ApiResponse
+// @Failure 400 {object} models.ApiResponse
+// @Failure 403 {object} models.ApiResponse
+// @Failure 409 {object} models.ApiResponse
+// @Failure 500 {object} models.ApiResponse
+// @Router /tax-classes [post]
+func (tc *TaxController) CreateTaxClass(c *gin.Context) {
+	var input models.TaxClassInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Validation failed",
+			Data:    nil,
+			Errors:  validations.FieldErrors(err),
+		})
+		return
+	}
+
+	class, err := tc.TaxService.CreateTaxClass(&input)
+	if err != nil {
+		handleTaxError(c, err, "Could not create tax class")
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.ApiResponse{
+		Status:  "success",
+		Code:    http.StatusCreated,
+		Message: "Tax class created successfully",
+		Data:    class,
+	})
+}
+
+// UpdateTaxClass godoc
+// @Summary Update a tax class by ID
+// @Description Change the code and name of a tax class (admin only)
+// @Tags taxes
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "Tax class ID"
+// @Param class body models.TaxClassInput true "Tax class"
+// @Success 200 {object} models.ApiResponse
+// @Failure 400 {object} models.ApiResponse
+// @Failure 403 {object} models.ApiResponse
+// @Failure 404 {object} models.ApiResponse
+// @Failure 409 {object} models.ApiResponse
+// @Failure 500 {object} models.ApiResponse
+// @Router /tax-classes/{id} [put]
+func (tc *TaxController) UpdateTaxClass(c *gin.Context) {
+	id, ok := parseTaxClassID(c)
+	if !ok {
+		return
+	}
+
+	var input models.TaxClassInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Validation failed",
+			Data:    nil,
+			Errors:  validations.FieldErrors(err),
+		})
+		return
+	}
+
+	class, err := tc.TaxService.UpdateTaxClass(id, &input)
+	if err != nil {
+		handleTaxError(c, err, "Could not update tax class")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ApiResponse{
+		Status:  "success",
+		Code:    http.StatusOK,
+		Message: "Tax class updated successfully",
+		Data:    class,
+	})
+}
+
+// DeleteTaxClass godoc
+// @Summary Delete a tax class by ID
+// @Description Delete a tax class and its rates (admin only). Tax classes that are assigned to products, including deleted products, cannot be deleted.
+// @Tags taxes
+// @Security BearerAuth
+// @Param id path int true "Tax class ID"
+// @Produce json
+// @Success 200 {object} models.ApiResponse
+// @Failure 400 {object} models.ApiResponse
+// @Failure 403 {object} models.ApiResponse
+// @Failure 404 {object} models.ApiResponse
+// @Failure 409 {object} models.ApiResponse
+// @Failure 500 {object} models.ApiResponse
+// @Router /tax-classes/{id} [delete]
+func (tc *TaxController) DeleteTaxClass(c *gin.Context) {
+	id, ok := parseTaxClassID(c)
+	if !ok {
+		return
+	}
+
+	if err := tc.TaxService.DeleteTaxClass(id); err != nil {
+		handleTaxError(c, err, "Could not delete tax class")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ApiResponse{
+		Status:  "success",
+		Code:    http.StatusOK,
+		Message: "Tax class deleted successfully",
+		Data:    nil,
+	})
+}
+
+// AddTaxRate godoc
+// @Summary Add a tax rate
+// @Description Add a rate in percent for a region from effective_from on (admin only). The rate replaces the previous rate for the same region from that moment. A country rate (ID) also applies to its subdivisions (ID-BA) unless they have their own rate.
+// @Tags taxes
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "Tax class ID"
+// @Param rate body models.TaxRateInput true "Tax rate"
+// @Success 201 {object} models.ApiResponse
+// @Failure 400 {object} models.ApiResponse
+// @Failure 403 {object} models.ApiResponse
+// @Failure 404 {object} models.ApiResponse
+// @Failure 409 {object} models.ApiResponse
+// @Failure 500 {object} models.ApiResponse
+// @Router /tax-classes/{id}/rates [post]
+func (tc *TaxController) AddTaxRate(c *gin.Context) {
+	id, ok := parseTaxClassID(c)
+	if !ok {
+		return
+	}
+
+	var input models.TaxRateInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Validation failed",
+			Data:    nil,
+			Errors:  validations.FieldErrors(err),
+		})
+		return
+	}
+
+	rate, err := tc.TaxService.AddTaxRate(id, &input)
+	if err != nil {
+		handleTaxError(c, err, "Could not add tax rate")
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.ApiResponse{
+		Status:  "success",
+		Code:    http.StatusCreated,
+		Message: "Tax rate added successfully",
+		Data:    rate,
+	})
+}
+
+// DeleteTaxRate godoc
+// @Summary Delete a tax rate
+// @Description Delete a rate from a tax class (admin only). Orders keep the rate they were created with.
+// @Tags taxes
+// @Security BearerAuth
+// @Param id path int true "Tax class ID"
+// @Param rateId path int true "Tax rate ID"
+// @Produce json
+// @Success 200 {object} models.ApiResponse
+// @Failure 400 {object} models.ApiResponse
+// @Failure 403 {object} models.ApiResponse
+// @Failure 404 {object} models.ApiResponse
+// @Failure 500 {object} models.ApiResponse
+// @Router /tax-classes/{id}/rates/{rateId} [delete]
+func (tc *TaxController) DeleteTaxRate(c *gin.Context) {
+	id, ok := parseTaxClassID(c)
+	if !ok {
+		return
+	}
+	rateID, err := strconv.Atoi(c.Param("rateId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Invalid tax rate ID",
+			Data:    nil,
+		})
+		return
+	}
+
+	if err := tc.TaxService.DeleteTaxRate(id, rateID); err != nil {
+		handleTaxError(c, err, "Could not delete tax rate")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ApiResponse{
+		Status:  "success",
+		Code:    http.StatusOK,
+		Message: "Tax rate deleted successfully",
+		Data:    nil,
+	})
+}
+
+func parseTaxClassID(c *gin.Context) (int, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Invalid tax class ID",
+			Data:    nil,
+		})
+		return 0, false
+	}
+	return id, true
+}
+
+func handleTaxError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, services.ErrTaxClassNotFound):
+		c.JSON(http.StatusNotFound, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusNotFound,
+			Message: "Tax class not found",
+			Data:    nil,
+		})
+	case errors.Is(err, services.ErrTaxRateNotFound):
+		c.JSON(http.StatusNotFound, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusNotFound,
+			Message: "Tax rate not found",
+			Data:    nil,
+		})
+	case errors.Is(err, services.ErrDuplicateTaxClassCode):
+		c.JSON(http.StatusConflict, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusConflict,
+			Message: "Validation failed",
+			Data:    nil,
+			Errors: []models.FieldError{{
+				Field:   "code",
+				Code:    models.ValidationNotUnique,
+				Message: err.Error(),
+			}},
+		})
+	case errors.Is(err, services.ErrDuplicateTaxRate):
+		c.JSON(http.StatusConflict, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusConflict,
+			Message: "Validation failed",
+			Data:    nil,
+			Errors: []models.FieldError{{
+				Field:   "effective_from",
+				Code:    models.ValidationNotUnique,
+				Message: err.Error(),
+			}},
+		})
+	case errors.Is(err, services.ErrTaxClassInUse):
+		c.JSON(http.StatusConflict, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusConflict,
+			Message: "Tax class is still assigned to products",
+			Data:    nil,
+		})
+	case errors.Is(err, services.ErrInvalidTaxRegion):
+		c.JSON(http.StatusBadRequest, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Validation failed",
+			Data:    nil,
+			Errors: []models.FieldError{{
+				Field:   "region",
+				Code:    models.ValidationInvalidValue,
+				Message: err.Error(),
+			}},
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusInternalServerError,
+			Message: fallback,
+			Data:    nil,
+		})
+	}
+}
@@ -0,0 +1,285 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"products-api-with-jwt/models"
+	"products-api-with-jwt/services"
+	"products-api-with-jwt/validations"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TranslationController struct {
+	TranslationService *services.TranslationService
+}
+
+// NewTranslationController menginisialisasi TranslationController baru
+func NewTranslationController(translationService *services.TranslationService) *TranslationController {
+	return &TranslationController{TranslationService: translationService}
+}
+
+// GetTranslations godoc
+// @Summary Get translations of a product
+// @Description Get the translated name and description of a product for every locale other than the default (id)
+// @Tags translations
+// @Security BearerAuth
+// @Param id path int true "Product ID"
+// @Produce json
+// @Success 200 {object} models.ApiResponse
+// @Failure 400 {object} models.ApiResponse
+// @Failure 404 {object} models.ApiResponse
+// @Failure 500 {object} models.ApiResponse
+// @Router /products/{id}/translations [get]
+func (tc *TranslationController) GetTranslations(c *gin.Context) {
+	productID, ok := parseProductID(c)
+	if !ok {
+		return
+	}
+
+	translations, err := tc.TranslationService.GetTranslations(productID)
+	if err != nil {
+		tc.handleError(c, err, "Could not retrieve translations")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ApiResponse{
+		Status:  "success",
+		Code:    http.StatusOK,
+		Message: "Translations retrieved successfully",
+		Data:    translations,
+		Count:   len(translations),
+	})
+}
+
+// SetTranslation godoc
+// @Summary Set a translation of a product
+// @Description Create or replace the name and description of a product in a locale. An empty description falls back to the default (id) description.
+// @Tags translations
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "Product ID"
+// @Param locale path string true "Locale (en)"
+// @Param translation body models.ProductTranslationInput true "Translation"
+// @Success 200 {object} models.ApiResponse
+// @Failure 400 {object} models.ApiResponse
+// @Failure 404 {object} models.ApiResponse
+// @Failure 500 {object} models.ApiResponse
+// @Router /products/{id}/translations/{locale} [put]
+func (tc *TranslationController) SetTranslation(c *gin.Context) {
+	productID, ok := parseProductID(c)
+	if !ok {
+		return
+	}
+	locale, ok := parseLocale(c, c.Param("locale"), "locale")
+	if !ok {
+		return
+	}
+
+	var input models.ProductTranslationInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Validation failed",
+			Data:    nil,
+			Errors:  validations.FieldErrors(err),
+		})
+		return
+	}
+
+	translation, err := tc.TranslationService.SetTranslation(productID, locale, &input)
+	if err != nil {
+		tc.handleError(c, err, "Could not save translation")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ApiResponse{
+		Status:  "success",
+		Code:    http.StatusOK,
+		Message: "Translation saved successfully",
+		Data:    translation,
+	})
+}
+
+// DeleteTranslation godoc
+// @Summary Delete a translation of a product
+// @Description Delete the translation of a product in a locale, so the product is shown in the default locale (id) instead
+// @Tags translations
+// @Security BearerAuth
+// @Param id path int true "Product ID"
+// @Param locale path string true "Locale (en)"
+// @Produce json
+// @Success 200 {object} models.ApiResponse
+// @Failure 400 {object} models.ApiResponse
+// @Failure 404 {object} models.ApiResponse
+// @Failure 500 {object} models.ApiResponse
+// @Router /products/{id}/translations/{locale} [delete]
+func (tc *TranslationController) DeleteTranslation(c *gin.Context) {
+	productID, ok := parseProductID(c)
+	if !ok {
+		return
+	}
+	locale, ok := parseLocale(c, c.Param("locale"), "locale")
+	if !ok {
+		return
+	}
+
+	if err := tc.TranslationService.DeleteTranslation(productID, locale); err != nil {
+		tc.handleError(c, err, "Could not delete translation")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ApiResponse{
+		Status:  "success",
+		Code:    http.StatusOK,
+		Message: "Translation deleted successfully",
+		Data:    nil,
+	})
+}
+
+// GetMissingTranslations godoc
+// @Summary Report missing translations
+// @Description List products without a translated name, or without a translated description while they have one in the default locale (id)
+// @Tags translations
+// @Security BearerAuth
+// @Param locale query string false "Only report this locale (default: all locales other than id)"
+// @Produce json
+// @Success 200 {object} models.ApiResponse
+// @Failure 400 {object} models.ApiResponse
+// @Failure 500 {object} models.ApiResponse
+// @Router /translations/missing [get]
+func (tc *TranslationController) GetMissingTranslations(c *gin.Context) {
+	var locale string
+	if value := c.Query("locale"); value != "" {
+		var ok bool
+		if locale, ok = parseLocale(c, value, "locale"); !ok {
+			return
+		}
+	}
+
+	report, err := tc.TranslationService.GetMissingTranslations(locale)
+	if err != nil {
+		tc.handleError(c, err, "Could not retrieve missing translations")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ApiResponse{
+		Status:  "success",
+		Code:    http.StatusOK,
+		Message: "Missing translations retrieved successfully",
+		Data:    report,
+		Count:   len(report),
+	})
+}
+
+func (tc *TranslationController) handleError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, services.ErrProductNotFound):
+		c.JSON(http.StatusNotFound, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusNotFound,
+			Message: "Product not found",
+			Data:    nil,
+		})
+	case errors.Is(err, services.ErrTranslationNotFound):
+		c.JSON(http.StatusNotFound, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusNotFound,
+			Message: "Translation not found",
+			Data:    nil,
+		})
+	case errors.Is(err, services.ErrDefaultLocale):
+		c.JSON(http.StatusBadRequest, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Validation failed",
+			Data:    nil,
+			Errors: []models.FieldError{{
+				Field:   "locale",
+				Code:    models.ValidationInvalidValue,
+				Message: err.Error(),
+			}},
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusInternalServerError,
+			Message: fallback,
+			Data:    nil,
+		})
+	}
+}
+
+// parseLocale mencocokkan value dengan SupportedLocales dan mengirim 400 jika tidak dikenal
+func parseLocale(c *gin.Context, value, field string) (string, bool) {
+	if locale, ok := matchLocale(value); ok {
+		return locale, true
+	}
+	c.JSON(http.StatusBadRequest, models.ApiResponse{
+		Status:  "error",
+		Code:    http.StatusBadRequest,
+		Message: "Validation failed",
+		Data:    nil,
+		Errors: []models.FieldError{{
+			Field:   field,
+			Code:    models.ValidationInvalidValue,
+			Message: "must be one of: " + strings.Join(models.SupportedLocales, " "),
+		}},
+	})
+	return "", false
+}
+
+// matchLocale mencocokkan language tag dengan SupportedLocales: tag persis dulu, lalu bahasa dasarnya (en-US -> en)
+func matchLocale(tag string) (string, bool) {
+	tag = strings.ToLower(strings.TrimSpace(strings.ReplaceAll(tag, "_", "-")))
+	if slices.Contains(models.SupportedLocales, tag) {
+		return tag, true
+	}
+	if base, _, found := strings.Cut(tag, "-"); found && slices.Contains(models.SupportedLocales, base) {
+		return base, true
+	}
+	return "", false
+}
+
+// acceptLanguageLocale memilih locale dari header Accept-Language berdasarkan q-value.
+// Jika tidak ada bahasa yang didukung, DefaultLocale dipakai.
+func acceptLanguageLocale(header string) string {
+	type languageRange struct {
+		tag     string
+		quality float64
+	}
+	var ranges []languageRange
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		quality := 1.0
+		if value, found := strings.CutPrefix(strings.TrimSpace(params), "q="); found {
+			q, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			quality = q
+		}
+		if tag == "" || tag == "*" || quality <= 0 {
+			continue
+		}
+		ranges = append(ranges, languageRange{tag: tag, quality: quality})
+	}
+	// Urutan di header dipertahankan untuk q-value yang sama
+	sort.SliceStable(ranges, func(i, j int) bool {
+		return ranges[i].quality > ranges[j].quality
+	})
+
+	for _, r := range ranges {
+		if locale, ok := matchLocale(r.tag); ok {
+			return locale
+		}
+	}
+	return models.DefaultLocale
+}
@@ -0,0 +1,414 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"products-api-with-jwt/models"
+	"products-api-with-jwt/services"
+	"products-api-with-jwt/validations"
+
+	"github.com/gin-gonic/gin"
+)
+
+type VariantController struct {
+	VariantService *services.VariantService
+}
+
+// NewVariantController menginisialisasi VariantController baru
+func NewVariantController(variantService *services.VariantService) *VariantController {
+	return &VariantController{VariantService: variantService}
+}
+
+// GetProductOptions godoc
+// @Summary Get product options
+// @Description Get the option definitions (for example size and color) that variants of a product choose from
+// @Tags variants
+// @Security BearerAuth
+// @Param id path int true "Product ID"
+// @Produce json
+// @Success 200 {object} models.ApiResponse
+// @Failure 400 {object} models.ApiResponse
+// @Failure 404 {object} models.ApiResponse
+// @Failure 500 {object} models.ApiResponse
+// @Router /products/{id}/options [get]
+func (vc *VariantController) GetProductOptions(c *gin.Context) {
+	productID, ok := parseProductID(c)
+	if !ok {
+		return
+	}
+
+	options, err := vc.VariantService.GetOptions(productID)
+	if err != nil {
+		vc.handleError(c, err, "Could not retrieve product options")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ApiResponse{
+		Status:  "success",
+		Code:    http.StatusOK,
+		Message: "Product options retrieved successfully",
+		Data:    options,
+		Count:   len(options),
+	})
+}
+
+// SetProductOptions godoc
+// @Summary Replace product options
+// @Description Replace all option definitions of a product. Option names are lowercased. Returns 409 when existing variants use an option or value that would be removed.
+// @Tags variants
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "Product ID"
+// @Param options body models.ProductOptionsInput true "Options"
+// @Success 200 {object} models.ApiResponse
+// @Failure 400 {object} models.ApiResponse
+// @Failure 404 {object} models.ApiResponse
+// @Failure 409 {object} models.ApiResponse
+// @Failure 500 {object} models.ApiResponse
+// @Router /products/{id}/options [put]
+func (vc *VariantController) SetProductOptions(c *gin.Context) {
+	productID, ok := parseProductID(c)
+	if !ok {
+		return
+	}
+
+	var input models.ProductOptionsInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Validation failed",
+			Data:    nil,
+			Errors:  validations.FieldErrors(err),
+		})
+		return
+	}
+
+	options, err := vc.VariantService.SetOptions(productID, &input)
+	if err != nil {
+		vc.handleError(c, err, "Could not update product options")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ApiResponse{
+		Status:  "success",
+		Code:    http.StatusOK,
+		Message: "Product options updated successfully",
+		Data:    options,
+		Count:   len(options),
+	})
+}
+
+// GetVariants godoc
+// @Summary Get product variants
+// @Description Get all variants of a product
+// @Tags variants
+// @Security BearerAuth
+// @Param id path int true "Product ID"
+// @Produce json
+// @Success 200 {object} models.ApiResponse
+// @Failure 400 {object} models.ApiResponse
+// @Failure 404 {object} models.ApiResponse
+// @Failure 500 {object} models.ApiResponse
+// @Router /products/{id}/variants [get]
+func (vc *VariantController) GetVariants(c *gin.Context) {
+	productID, ok := parseProductID(c)
+	if !ok {
+		return
+	}
+
+	variants, err := vc.VariantService.GetVariants(productID)
+	if err != nil {
+		vc.handleError(c, err, "Could not retrieve variants")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ApiResponse{
+		Status:  "success",
+		Code:    http.StatusOK,
+		Message: "Variants retrieved successfully",
+		Data:    variants,
+		Count:   len(variants),
+	})
+}
+
+// GetVariantByID godoc
+// @Summary Get product variant by ID
+// @Description Get a single variant of a product
+// @Tags variants
+// @Security BearerAuth
+// @Param id path int true "Product ID"
+// @Param variantId path int true "Variant ID"
+// @Produce json
+// @Success 200 {object} models.ApiResponse
+// @Failure 400 {object} models.ApiResponse
+// @Failure 404 {object} models.ApiResponse
+// @Failure 500 {object} models.ApiResponse
+// @Router /products/{id}/variants/{variantId} [get]
+func (vc *VariantController) GetVariantByID(c *gin.Context) {
+	productID, variantID, ok := parseVariantIDs(c)
+	if !ok {
+		return
+	}
+
+	variant, err := vc.VariantService.GetVariant(productID, variantID)
+	if err != nil {
+		vc.handleError(c, err, "Could not retrieve variant")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ApiResponse{
+		Status:  "success",
+		Code:    http.StatusOK,
+		Message: "Variant retrieved successfully",
+		Data:    variant,
+	})
+}
+
+// CreateVariant godoc
+// @Summary Create a product variant
+// @Description Add a variant with its own SKU, stock, barcode and optional price override. Options must give one allowed value for every product option. The initial stok is recorded as a receipt in the default warehouse; change it later with the stock adjust endpoint and variant_id.
+// @Tags variants
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "Product ID"
+// @Param variant body models.CreateVariantInput true "Variant"
+// @Success 201 {object} models.ApiResponse
+// @Failure 400 {object} models.ApiResponse
+// @Failure 404 {object} models.ApiResponse
+// @Failure 409 {object} models.ApiResponse
+// @Failure 500 {object} models.ApiResponse
+// @Router /products/{id}/variants [post]
+func (vc *VariantController) CreateVariant(c *gin.Context) {
+	productID, ok := parseProductID(c)
+	if !ok {
+		return
+	}
+
+	var input models.CreateVariantInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Validation failed",
+			Data:    nil,
+			Errors:  validations.FieldErrors(err),
+		})
+		return
+	}
+
+	userID := 0
+	if user := currentUser(c); user != nil {
+		userID = user.ID
+	}
+
+	variant, err := vc.VariantService.CreateVariant(productID, &input, userID)
+	if err != nil {
+		vc.handleError(c, err, "Could not create variant")
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.ApiResponse{
+		Status:  "success",
+		Code:    http.StatusCreated,
+		Message: "Variant created successfully",
+		Data:    variant,
+	})
+}
+
+// UpdateVariant godoc
+// @Summary Update a product variant
+// @Description Update fields of a variant. Omitted fields are left unchanged; price_override 0 removes the override.
+// @Tags variants
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "Product ID"
+// @Param variantId path int true "Variant ID"
+// @Param variant body models.UpdateVariantInput true "Variant"
+// @Success 200 {object} models.ApiResponse
+// @Failure 400 {object} models.ApiResponse
+// @Failure 404 {object} models.ApiResponse
+// @Failure 409 {object} models.ApiResponse
+// @Failure 500 {object} models.ApiResponse
+// @Router /products/{id}/variants/{variantId} [put]
+func (vc *VariantController) UpdateVariant(c *gin.Context) {
+	productID, variantID, ok := parseVariantIDs(c)
+	if !ok {
+		return
+	}
+
+	var input models.UpdateVariantInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Validation failed",
+			Data:    nil,
+			Errors:  validations.FieldErrors(err),
+		})
+		return
+	}
+
+	variant, err := vc.VariantService.UpdateVariant(productID, variantID, &input)
+	if err != nil {
+		vc.handleError(c, err, "Could not update variant")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ApiResponse{
+		Status:  "success",
+		Code:    http.StatusOK,
+		Message: "Variant updated successfully",
+		Data:    variant,
+	})
+}
+
+// DeleteVariant godoc
+// @Summary Delete a product variant
+// @Description Delete a variant of a product
+// @Tags variants
+// @Security BearerAuth
+// @Param id path int true "Product ID"
+// @Param variantId path int true "Variant ID"
+// @Produce json
+// @Success 200 {object} models.ApiResponse
+// @Failure 400 {object} models.ApiResponse
+// @Failure 404 {object} models.ApiResponse
+// @Failure 500 {object} models.ApiResponse
+// @Router /products/{id}/variants/{variantId} [delete]
+func (vc *VariantController) DeleteVariant(c *gin.Context) {
+	productID, variantID, ok := parseVariantIDs(c)
+	if !ok {
+		return
+	}
+
+	if err := vc.VariantService.DeleteVariant(productID, variantID); err != nil {
+		vc.handleError(c, err, "Could not delete variant")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ApiResponse{
+		Status:  "success",
+		Code:    http.StatusOK,
+		Message: "Variant deleted successfully",
+		Data:    nil,
+	})
+}
+
+func (vc *VariantController) handleError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, services.ErrProductNotFound):
+		c.JSON(http.StatusNotFound, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusNotFound,
+			Message: "Product not found",
+			Data:    nil,
+		})
+	case errors.Is(err, services.ErrVariantNotFound):
+		c.JSON(http.StatusNotFound, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusNotFound,
+			Message: "Variant not found",
+			Data:    nil,
+		})
+	case duplicateCodeField(err) != "":
+		c.JSON(http.StatusConflict, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusConflict,
+			Message: "Validation failed",
+			Data:    nil,
+			Errors: []models.FieldError{{
+				Field:   duplicateCodeField(err),
+				Code:    models.ValidationNotUnique,
+				Message: err.Error(),
+			}},
+		})
+	case errors.Is(err, services.ErrDuplicateOptionName):
+		c.JSON(http.StatusBadRequest, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Validation failed",
+			Data:    nil,
+			Errors: []models.FieldError{{
+				Field:   "options",
+				Code:    models.ValidationNotUnique,
+				Message: err.Error(),
+			}},
+		})
+	case errors.Is(err, services.ErrInvalidVariantOptions):
+		c.JSON(http.StatusBadRequest, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Validation failed",
+			Data:    nil,
+			Errors: []models.FieldError{{
+				Field:   "options",
+				Code:    models.ValidationInvalidValue,
+				Message: err.Error(),
+			}},
+		})
+	case errors.Is(err, services.ErrDuplicateVariantOptions):
+		c.JSON(http.StatusConflict, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusConflict,
+			Message: "Validation failed",
+			Data:    nil,
+			Errors: []models.FieldError{{
+				Field:   "options",
+				Code:    models.ValidationNotUnique,
+				Message: err.Error(),
+			}},
+		})
+	case errors.Is(err, services.ErrOptionsInUse), errors.Is(err, services.ErrBundleStock):
+		c.JSON(http.StatusConflict, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusConflict,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusInternalServerError,
+			Message: fallback,
+			Data:    nil,
+		})
+	}
+}
+
+func parseVariantIDs(c *gin.Context) (int, int, bool) {
+	productID, ok := parseProductID(c)
+	if !ok {
+		return 0, 0, false
+	}
+	variantID, err := strconv.Atoi(c.Param("variantId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Invalid variant ID",
+			Data:    nil,
+		})
+		return 0, 0, false
+	}
+	return productID, variantID, true
+}
+
+func parseProductID(c *gin.Context) (int, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Invalid product ID",
+			Data:    nil,
+		})
+		return 0, false
+	}
+	return id, true
+}
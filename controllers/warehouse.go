@@ -0,0 +1,290 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"products-api-with-jwt/models"
+	"products-api-with-jwt/services"
+	"products-api-with-jwt/validations"
+
+	"github.com/gin-gonic/gin"
+)
+
+type WarehouseController struct {
+	WarehouseService *services.WarehouseService
+}
+
+// NewWarehouseController menginisialisasi WarehouseController baru
+func NewWarehouseController(warehouseService *services.WarehouseService) *WarehouseController {
+	return &WarehouseController{WarehouseService: warehouseService}
+}
+
+// GetWarehouses godoc
+// @Summary Get all warehouses
+// @Description Get a list of all warehouses
+// @Tags warehouses
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} models.ApiResponse
+// @Failure 500 {object} models.ApiResponse
+// @Router /warehouses [get]
+func (wc *WarehouseController) GetWarehouses(c *gin.Context) {
+	warehouses, err := wc.WarehouseService.GetAllWarehouses()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusInternalServerError,
+			Message: "Could not retrieve warehouses",
+			Data:    nil,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ApiResponse{
+		Status:  "success",
+		Code:    http.StatusOK,
+		Message: "Warehouses retrieved successfully",
+		Data:    warehouses,
+		Count:   len(warehouses),
+	})
+}
+
+// GetWarehouseByID godoc
+// @Summary Get warehouse by ID
+// @Description Get details of a warehouse by its ID
+// @Tags warehouses
+// @Security BearerAuth
+// @Param id path int true "Warehouse ID"
+// @Produce json
+// @Success 200 {object} models.ApiResponse
+// @Failure 400 {object} models.ApiResponse
+// @Failure 404 {object} models.ApiResponse
+// @Router /warehouses/{id} [get]
+func (wc *WarehouseController) GetWarehouseByID(c *gin.Context) {
+	id, ok := parseWarehouseID(c)
+	if !ok {
+		return
+	}
+
+	warehouse, err := wc.WarehouseService.GetWarehouseByID(id)
+	if err != nil {
+		wc.handleError(c, err, "Could not retrieve warehouse")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ApiResponse{
+		Status:  "success",
+		Code:    http.StatusOK,
+		Message: "Warehouse retrieved successfully",
+		Data:    warehouse,
+	})
+}
+
+// GetWarehouseStock godoc
+// @Summary Get stock in a warehouse
+// @Description Get the stock level of every product held in a warehouse
+// @Tags warehouses
+// @Security BearerAuth
+// @Param id path int true "Warehouse ID"
+// @Produce json
+// @Success 200 {object} models.ApiResponse
+// @Failure 400 {object} models.ApiResponse
+// @Failure 404 {object} models.ApiResponse
+// @Failure 500 {object} models.ApiResponse
+// @Router /warehouses/{id}/stock [get]
+func (wc *WarehouseController) GetWarehouseStock(c *gin.Context) {
+	id, ok := parseWarehouseID(c)
+	if !ok {
+		return
+	}
+
+	locations, err := wc.WarehouseService.GetWarehouseStock(id)
+	if err != nil {
+		wc.handleError(c, err, "Could not retrieve warehouse stock")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ApiResponse{
+		Status:  "success",
+		Code:    http.StatusOK,
+		Message: "Warehouse stock retrieved successfully",
+		Data:    locations,
+		Count:   len(locations),
+	})
+}
+
+// CreateWarehouse godoc
+// @Summary Create a new warehouse
+// @Description Create a new warehouse (admin only). Marking it as default moves the default flag from the previous warehouse.
+// @Tags warehouses
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param warehouse body models.WarehouseInput true "Warehouse"
+// @Success 201 {object} models.ApiResponse
+// @Failure 400 {object} models.ApiResponse
+// @Failure 403 {object} models.ApiResponse
+// @Failure 409 {object} models.ApiResponse
+// @Failure 500 {object} models.ApiResponse
+// @Router /warehouses [post]
+func (wc *WarehouseController) CreateWarehouse(c *gin.Context) {
+	var input models.WarehouseInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Validation failed",
+			Data:    nil,
+			Errors:  validations.FieldErrors(err),
+		})
+		return
+	}
+
+	warehouse, err := wc.WarehouseService.CreateWarehouse(&input)
+	if err != nil {
+		wc.handleError(c, err, "Could not create warehouse")
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.ApiResponse{
+		Status:  "success",
+		Code:    http.StatusCreated,
+		Message: "Warehouse created successfully",
+		Data:    warehouse,
+	})
+}
+
+// UpdateWarehouse godoc
+// @Summary Update a warehouse by ID
+// @Description Update a warehouse's information by its ID (admin only)
+// @Tags warehouses
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "Warehouse ID"
+// @Param warehouse body models.WarehouseInput true "Warehouse"
+// @Success 200 {object} models.ApiResponse
+// @Failure 400 {object} models.ApiResponse
+// @Failure 403 {object} models.ApiResponse
+// @Failure 404 {object} models.ApiResponse
+// @Failure 409 {object} models.ApiResponse
+// @Failure 500 {object} models.ApiResponse
+// @Router /warehouses/{id} [put]
+func (wc *WarehouseController) UpdateWarehouse(c *gin.Context) {
+	id, ok := parseWarehouseID(c)
+	if !ok {
+		return
+	}
+
+	var input models.WarehouseInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Validation failed",
+			Data:    nil,
+			Errors:  validations.FieldErrors(err),
+		})
+		return
+	}
+
+	warehouse, err := wc.WarehouseService.UpdateWarehouse(id, &input)
+	if err != nil {
+		wc.handleError(c, err, "Could not update warehouse")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ApiResponse{
+		Status:  "success",
+		Code:    http.StatusOK,
+		Message: "Warehouse updated successfully",
+		Data:    warehouse,
+	})
+}
+
+// DeleteWarehouse godoc
+// @Summary Delete a warehouse by ID
+// @Description Delete an empty warehouse (admin only). The default warehouse and warehouses that still hold stock cannot be deleted.
+// @Tags warehouses
+// @Security BearerAuth
+// @Param id path int true "Warehouse ID"
+// @Produce json
+// @Success 200 {object} models.ApiResponse
+// @Failure 400 {object} models.ApiResponse
+// @Failure 403 {object} models.ApiResponse
+// @Failure 404 {object} models.ApiResponse
+// @Failure 409 {object} models.ApiResponse
+// @Failure 500 {object} models.ApiResponse
+// @Router /warehouses/{id} [delete]
+func (wc *WarehouseController) DeleteWarehouse(c *gin.Context) {
+	id, ok := parseWarehouseID(c)
+	if !ok {
+		return
+	}
+
+	if err := wc.WarehouseService.DeleteWarehouse(id); err != nil {
+		wc.handleError(c, err, "Could not delete warehouse")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ApiResponse{
+		Status:  "success",
+		Code:    http.StatusOK,
+		Message: "Warehouse deleted successfully",
+		Data:    nil,
+	})
+}
+
+func parseWarehouseID(c *gin.Context) (int, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Invalid warehouse ID",
+			Data:    nil,
+		})
+		return 0, false
+	}
+	return id, true
+}
+
+func (wc *WarehouseController) handleError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, services.ErrWarehouseNotFound):
+		c.JSON(http.StatusNotFound, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusNotFound,
+			Message: "Warehouse not found",
+			Data:    nil,
+		})
+	case errors.Is(err, services.ErrDuplicateWarehouseCode):
+		c.JSON(http.StatusConflict, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusConflict,
+			Message: "Validation failed",
+			Data:    nil,
+			Errors: []models.FieldError{{
+				Field:   "code",
+				Code:    models.ValidationNotUnique,
+				Message: err.Error(),
+			}},
+		})
+	case errors.Is(err, services.ErrWarehouseInUse):
+		c.JSON(http.StatusConflict, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusConflict,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusInternalServerError,
+			Message: fallback,
+			Data:    nil,
+		})
+	}
+}
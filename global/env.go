@@ -3,3 +3,20 @@ package global
 const ENVSecretKey string = "SECRET_KEY"
 const ENVRateLimitDur string = "RATE_LIMIT_DURATION"
 const ENVRateLimitTime string = "RATE_LIMIT_TIME"
+const ENVProductRetentionDays string = "PRODUCT_RETENTION_DAYS"
+const ENVProductPurgeInterval string = "PRODUCT_PURGE_INTERVAL"
+const ENVReservationTTL string = "RESERVATION_TTL"
+const ENVReservationExpireInterval string = "RESERVATION_EXPIRE_INTERVAL"
+const ENVLowStockWebhookURL string = "LOW_STOCK_WEBHOOK_URL"
+const ENVLowStockEmailTo string = "LOW_STOCK_EMAIL_TO"
+const ENVSMTPAddr string = "SMTP_ADDR"
+const ENVSMTPFrom string = "SMTP_FROM"
+const ENVSMTPUsername string = "SMTP_USERNAME"
+const ENVSMTPPassword string = "SMTP_PASSWORD"
+const ENVImageDir string = "IMAGE_DIR"
+const ENVImageMaxSize string = "IMAGE_MAX_SIZE"
+const ENVImageURLTTL string = "IMAGE_URL_TTL"
+const ENVPriceScheduleInterval string = "PRICE_SCHEDULE_INTERVAL"
+const ENVProductStatusInterval string = "PRODUCT_STATUS_INTERVAL"
+const ENVCartTTL string = "CART_TTL"
+const ENVCartExpireInterval string = "CART_EXPIRE_INTERVAL"
@@ -2,11 +2,17 @@ package main
 
 import (
 	"log"
+	"os"
 	"products-api-with-jwt/config"
 	"products-api-with-jwt/controllers"
 	_ "products-api-with-jwt/docs" // Import docs for Swagger
+	"products-api-with-jwt/global"
 	"products-api-with-jwt/middlewares"
+	"products-api-with-jwt/notifiers"
 	"products-api-with-jwt/services"
+	"products-api-with-jwt/storage"
+	"products-api-with-jwt/validations"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	swaggerFiles "github.com/swaggo/files"
@@ -14,6 +20,11 @@ import (
 )
 
 func main() {
+	// Register custom validation rules
+	if err := validations.Setup(); err != nil {
+		log.Fatalf("Failed to setup validations: %v", err)
+	}
+
 	// Setup database (SQLite)
 	db, err := config.SetupDatabase()
 	if err != nil {
@@ -23,11 +34,87 @@ func main() {
 
 	// Initialize DB for services
 	authService := services.NewAuthService(db)
-	productService := services.NewProductService(db)
+	// Low-stock alerts always go to the log, webhook and email are enabled by environment variables
+	lowStockNotifiers := []notifiers.LowStockNotifier{notifiers.NewLogNotifier()}
+	if url := os.Getenv(global.ENVLowStockWebhookURL); url != "" {
+		lowStockNotifiers = append(lowStockNotifiers, notifiers.NewWebhookNotifier(url))
+	}
+	if to := os.Getenv(global.ENVLowStockEmailTo); to != "" {
+		lowStockNotifiers = append(lowStockNotifiers, notifiers.NewEmailNotifier(
+			os.Getenv(global.ENVSMTPAddr),
+			os.Getenv(global.ENVSMTPFrom),
+			to,
+			os.Getenv(global.ENVSMTPUsername),
+			os.Getenv(global.ENVSMTPPassword),
+		))
+	}
+
+	// Product images are stored on local disk
+	imageDir := os.Getenv(global.ENVImageDir)
+	if imageDir == "" {
+		imageDir = "uploads/images"
+	}
+	imageStorage, err := storage.NewLocalStorage(imageDir)
+	if err != nil {
+		log.Fatalf("Failed to setup image storage: %v", err)
+	}
+
+	productService := services.NewProductService(db, lowStockNotifiers...)
+	productService.ImageStorage = imageStorage
+	warehouseService := services.NewWarehouseService(db)
+	categoryService := services.NewCategoryService(db)
+	tagService := services.NewTagService(db)
+	variantService := services.NewVariantService(db, productService)
+	priceService := services.NewPriceService(db)
+	currencyService := services.NewCurrencyService(db)
+	promotionService := services.NewPromotionService(db)
+	translationService := services.NewTranslationService(db)
+	attributeService := services.NewAttributeService(db)
+	imageService := services.NewImageService(db, imageStorage, os.Getenv(global.ENVSecretKey),
+		config.GetEnvDuration(global.ENVImageURLTTL, time.Hour), int64(config.GetEnvInt(global.ENVImageMaxSize, 5<<20)))
+	reservationService := services.NewReservationService(db, productService, config.GetEnvDuration(global.ENVReservationTTL, 15*time.Minute))
+	supplierService := services.NewSupplierService(db)
+	purchaseOrderService := services.NewPurchaseOrderService(db, productService)
+	orderService := services.NewOrderService(db, productService)
+	taxService := services.NewTaxService(db)
+	priceListService := services.NewPriceListService(db)
+	reviewService := services.NewReviewService(db)
+	cartService := services.NewCartService(db, orderService, config.GetEnvDuration(global.ENVCartTTL, 7*24*time.Hour))
+
+	// Make sure product stock matches the stock ledger
+	if err := productService.ReconcileStock(); err != nil {
+		log.Fatalf("Failed to reconcile stock: %v", err)
+	}
+
+	// Start background jobs
+	retention := time.Duration(config.GetEnvInt(global.ENVProductRetentionDays, 30)) * time.Hour * 24
+	productService.StartPurgeScheduler(config.GetEnvDuration(global.ENVProductPurgeInterval, time.Hour), retention)
+	reservationService.StartReservationExpirer(config.GetEnvDuration(global.ENVReservationExpireInterval, time.Minute))
+	priceService.StartPriceScheduler(config.GetEnvDuration(global.ENVPriceScheduleInterval, time.Minute))
+	productService.StartStatusScheduler(config.GetEnvDuration(global.ENVProductStatusInterval, time.Minute))
+	cartService.StartCartExpirer(config.GetEnvDuration(global.ENVCartExpireInterval, time.Hour))
 
 	// Initialize controllers
 	authController := controllers.NewAuthController(authService)
 	productController := controllers.NewProductController(productService)
+	reservationController := controllers.NewReservationController(reservationService)
+	warehouseController := controllers.NewWarehouseController(warehouseService)
+	categoryController := controllers.NewCategoryController(categoryService)
+	tagController := controllers.NewTagController(tagService)
+	variantController := controllers.NewVariantController(variantService)
+	imageController := controllers.NewImageController(imageService)
+	priceController := controllers.NewPriceController(priceService)
+	currencyController := controllers.NewCurrencyController(currencyService)
+	promotionController := controllers.NewPromotionController(promotionService)
+	translationController := controllers.NewTranslationController(translationService)
+	attributeController := controllers.NewAttributeController(attributeService)
+	supplierController := controllers.NewSupplierController(supplierService)
+	purchaseOrderController := controllers.NewPurchaseOrderController(purchaseOrderService)
+	orderController := controllers.NewOrderController(orderService)
+	cartController := controllers.NewCartController(cartService)
+	taxController := controllers.NewTaxController(taxService)
+	priceListController := controllers.NewPriceListController(priceListService)
+	reviewController := controllers.NewReviewController(reviewService)
 
 	// Initialize router
 	r := gin.Default()
@@ -39,17 +126,200 @@ func main() {
 	auth.POST("/login", authController.Login)
 	auth.POST("/logout", authController.Logout)
 
+	// Image files are served through signed URLs instead of JWT, so they work in <img> tags
+	r.GET("/images/:imageId/:version", imageController.ServeImage)
+
 	// Other endpoints require JWT authentication
 	protected := r.Group("/")
 	protected.Use(middlewares.JWTAuthMiddleware(authService))
 
 	// Product endpoints
 	product := protected.Group("/products")
-	product.GET("/", productController.GetProducts)         // Get all products
-	product.GET("/:id", productController.GetProductByID)   // Get product by ID
-	product.POST("/", productController.CreateProduct)      // Add new product
-	product.PUT("/:id", productController.UpdateProduct)    // Update product
-	product.DELETE("/:id", productController.DeleteProduct) // Delete product
+	product.GET("/", productController.GetProducts)                  // Get all products
+	product.GET("/:id", productController.GetProductByID)            // Get product by ID
+	product.GET("/low-stock", productController.GetLowStockProducts) // Products at or below reorder point
+	product.GET("/lookup", productController.LookupProduct)          // Find product by barcode or SKU
+	product.POST("/", productController.CreateProduct)               // Add new product
+	product.PUT("/:id", productController.UpdateProduct)             // Update product
+	product.DELETE("/:id", productController.DeleteProduct)          // Delete product (soft delete)
+
+	// Stock ledger endpoints
+	product.POST("/:id/stock/adjust", productController.AdjustStock)     // Record stock movement
+	product.GET("/:id/stock/history", productController.GetStockHistory) // Get stock ledger
+	product.POST("/:id/stock/transfer", productController.TransferStock) // Move stock between warehouses
+
+	// Stock reservation endpoints
+	product.POST("/:id/reservations", reservationController.CreateReservation)                         // Hold stock
+	product.POST("/:id/reservations/:reservationId/confirm", reservationController.ConfirmReservation) // Confirm hold as sale
+	product.POST("/:id/reservations/:reservationId/release", reservationController.ReleaseReservation) // Release hold
+
+	// Product tag endpoints
+	product.POST("/:id/tags", tagController.AddProductTags)      // Attach tags
+	product.DELETE("/:id/tags", tagController.RemoveProductTags) // Detach tags
+
+	// Product variant endpoints
+	product.GET("/:id/options", variantController.GetProductOptions)            // Get option definitions
+	product.PUT("/:id/options", variantController.SetProductOptions)            // Replace option definitions
+	product.GET("/:id/variants", variantController.GetVariants)                 // Get all variants
+	product.GET("/:id/variants/:variantId", variantController.GetVariantByID)   // Get variant by ID
+	product.POST("/:id/variants", variantController.CreateVariant)              // Add new variant
+	product.PUT("/:id/variants/:variantId", variantController.UpdateVariant)    // Update variant
+	product.DELETE("/:id/variants/:variantId", variantController.DeleteVariant) // Delete variant
+
+	// Product price endpoints
+	product.GET("/:id/prices", priceController.GetPriceHistory)                               // Get price history
+	product.GET("/:id/prices/scheduled", priceController.GetScheduledPrices)                  // Get scheduled prices
+	product.POST("/:id/prices/scheduled", priceController.SchedulePrice)                      // Schedule price change
+	product.DELETE("/:id/prices/scheduled/:scheduleId", priceController.CancelScheduledPrice) // Cancel scheduled price
+
+	// Product final price with promotions
+	product.GET("/:id/price", promotionController.GetProductPrice) // Evaluate promotions for ?qty=
+
+	// Product per-currency price endpoints
+	product.GET("/:id/currency-prices", currencyController.GetCurrencyPrices)                // Get per-currency prices
+	product.PUT("/:id/currency-prices/:currency", currencyController.SetCurrencyPrice)       // Set price in a currency
+	product.DELETE("/:id/currency-prices/:currency", currencyController.DeleteCurrencyPrice) // Remove price in a currency
+
+	// Product translation endpoints
+	product.GET("/:id/translations", translationController.GetTranslations)              // Get translations
+	product.PUT("/:id/translations/:locale", translationController.SetTranslation)       // Set translation in a locale
+	product.DELETE("/:id/translations/:locale", translationController.DeleteTranslation) // Remove translation in a locale
+
+	// Product bundle endpoints
+	product.PUT("/:id/bundle", productController.SetBundle)       // Make bundle or replace components
+	product.DELETE("/:id/bundle", productController.RemoveBundle) // Turn bundle back into regular product
+
+	// Related product endpoints
+	product.POST("/:id/related", productController.AddRelatedProduct)      // Link related product
+	product.DELETE("/:id/related", productController.RemoveRelatedProduct) // Unlink related product
+
+	// Product review endpoints
+	product.GET("/:id/reviews", reviewController.GetProductReviews)         // Get approved reviews
+	product.POST("/:id/reviews", reviewController.CreateReview)             // Post review for moderation
+	product.PUT("/:id/reviews/:reviewId", reviewController.UpdateReview)    // Change own review
+	product.DELETE("/:id/reviews/:reviewId", reviewController.DeleteReview) // Delete own review, admins any
+
+	// Product image endpoints
+	product.GET("/:id/images", imageController.GetProductImages)               // Get images with signed URLs
+	product.POST("/:id/images", imageController.UploadProductImage)            // Upload image (multipart)
+	product.PUT("/:id/images/:imageId", imageController.UpdateProductImage)    // Reorder image or make it primary
+	product.DELETE("/:id/images/:imageId", imageController.DeleteProductImage) // Delete image
+
+	// Admin-only product endpoints
+	admin := middlewares.RequireRole("admin")
+	product.GET("/trash", admin, productController.GetDeletedProducts)                  // List deleted products
+	product.POST("/:id/restore", admin, productController.RestoreProduct)               // Restore deleted product
+	product.PUT("/:id/status", admin, productController.ChangeProductStatus)            // Publish or archive product
+	product.PUT("/:id/status/schedule", admin, productController.ScheduleProductStatus) // Schedule publish and archive
+
+	// Warehouse endpoints
+	warehouse := protected.Group("/warehouses")
+	warehouse.GET("/", warehouseController.GetWarehouses)                // Get all warehouses
+	warehouse.GET("/:id", warehouseController.GetWarehouseByID)          // Get warehouse by ID
+	warehouse.GET("/:id/stock", warehouseController.GetWarehouseStock)   // Get stock held in warehouse
+	warehouse.POST("/", admin, warehouseController.CreateWarehouse)      // Add new warehouse
+	warehouse.PUT("/:id", admin, warehouseController.UpdateWarehouse)    // Update warehouse
+	warehouse.DELETE("/:id", admin, warehouseController.DeleteWarehouse) // Delete empty warehouse
+
+	// Category endpoints
+	category := protected.Group("/categories")
+	category.GET("/", categoryController.GetCategories)               // Get all categories (flat or tree)
+	category.GET("/:id", categoryController.GetCategoryByID)          // Get category with sub-categories
+	category.POST("/", admin, categoryController.CreateCategory)      // Add new category
+	category.PUT("/:id", admin, categoryController.UpdateCategory)    // Rename or move category
+	category.DELETE("/:id", admin, categoryController.DeleteCategory) // Delete category
+
+	// Category attribute schema endpoints
+	category.GET("/:id/attributes", attributeController.GetCategoryAttributes)                   // Get schemas incl. inherited
+	category.PUT("/:id/attributes/:code", admin, attributeController.SetCategoryAttribute)       // Create or replace schema
+	category.DELETE("/:id/attributes/:code", admin, attributeController.DeleteCategoryAttribute) // Delete schema
+
+	// Tag endpoints
+	protected.GET("/tags", tagController.GetTags) // Get all tags with usage counts
+
+	// Exchange rate endpoints
+	exchangeRate := protected.Group("/exchange-rates")
+	exchangeRate.GET("/", currencyController.GetExchangeRates)                      // Get all exchange rates
+	exchangeRate.PUT("/:from/:to", admin, currencyController.SetExchangeRate)       // Create or replace rate
+	exchangeRate.DELETE("/:from/:to", admin, currencyController.DeleteExchangeRate) // Delete rate
+
+	// Promotion endpoints (admin only)
+	promotion := protected.Group("/promotions", admin)
+	promotion.GET("/", promotionController.GetPromotions)            // Get all promotions
+	promotion.GET("/:id", promotionController.GetPromotionByID)      // Get promotion by ID
+	promotion.POST("/", promotionController.CreatePromotion)         // Add new promotion
+	promotion.PUT("/:id", promotionController.UpdatePromotion)       // Replace promotion
+	promotion.DELETE("/:id", promotionController.DeletePromotion)    // Delete promotion
+	promotion.POST("/dry-run", promotionController.DryRunPromotions) // Evaluate price without saving
+
+	// Translation report
+	protected.GET("/translations/missing", translationController.GetMissingTranslations) // Products missing translations
+
+	// Review moderation endpoints (admin only)
+	review := protected.Group("/reviews", admin)
+	review.GET("/", reviewController.GetReviews)                   // Get reviews, ?status=pending for the queue
+	review.PUT("/:id/moderation", reviewController.ModerateReview) // Approve or reject review
+
+	// Price list endpoints (admin only)
+	priceList := protected.Group("/price-lists", admin)
+	priceList.GET("/", priceListController.GetPriceLists)                           // Get all price lists with rules
+	priceList.GET("/:id", priceListController.GetPriceListByID)                     // Get price list with rules
+	priceList.POST("/", priceListController.CreatePriceList)                        // Add new price list
+	priceList.PUT("/:id", priceListController.UpdatePriceList)                      // Update price list and assignments
+	priceList.DELETE("/:id", priceListController.DeletePriceList)                   // Delete price list
+	priceList.POST("/:id/rules", priceListController.AddPriceListRule)              // Add product or category rule
+	priceList.PUT("/:id/rules/:ruleId", priceListController.UpdatePriceListRule)    // Update rule
+	priceList.DELETE("/:id/rules/:ruleId", priceListController.DeletePriceListRule) // Delete rule
+
+	// User customer group endpoint (admin only), selects the user's price list
+	protected.PUT("/users/:id/customer-group", admin, priceListController.SetCustomerGroup)
+
+	// Tax class endpoints
+	taxClass := protected.Group("/tax-classes")
+	taxClass.GET("/", taxController.GetTaxClasses)                            // Get all tax classes with rates
+	taxClass.GET("/:id", taxController.GetTaxClassByID)                       // Get tax class with rates
+	taxClass.POST("/", admin, taxController.CreateTaxClass)                   // Add new tax class
+	taxClass.PUT("/:id", admin, taxController.UpdateTaxClass)                 // Update tax class
+	taxClass.DELETE("/:id", admin, taxController.DeleteTaxClass)              // Delete unused tax class
+	taxClass.POST("/:id/rates", admin, taxController.AddTaxRate)              // Add rate for region from a date
+	taxClass.DELETE("/:id/rates/:rateId", admin, taxController.DeleteTaxRate) // Delete rate
+
+	// Supplier endpoints
+	supplier := protected.Group("/suppliers")
+	supplier.GET("/", supplierController.GetSuppliers)                                           // Get all suppliers
+	supplier.GET("/:id", supplierController.GetSupplierByID)                                     // Get supplier by ID
+	supplier.POST("/", admin, supplierController.CreateSupplier)                                 // Add new supplier
+	supplier.PUT("/:id", admin, supplierController.UpdateSupplier)                               // Update supplier
+	supplier.DELETE("/:id", admin, supplierController.DeleteSupplier)                            // Delete supplier without purchase orders
+	supplier.GET("/:id/products", supplierController.GetSupplierProducts)                        // Get products with cost and lead time
+	supplier.PUT("/:id/products/:productId", admin, supplierController.SetSupplierProduct)       // Link product with cost
+	supplier.DELETE("/:id/products/:productId", admin, supplierController.DeleteSupplierProduct) // Unlink product
+
+	// Purchase order endpoints (admin only)
+	purchaseOrder := protected.Group("/purchase-orders", admin)
+	purchaseOrder.GET("/", purchaseOrderController.GetPurchaseOrders)                // Get all purchase orders
+	purchaseOrder.GET("/:id", purchaseOrderController.GetPurchaseOrderByID)          // Get purchase order with lines
+	purchaseOrder.POST("/", purchaseOrderController.CreatePurchaseOrder)             // Add new draft purchase order
+	purchaseOrder.PUT("/:id", purchaseOrderController.UpdatePurchaseOrder)           // Replace draft purchase order
+	purchaseOrder.POST("/:id/send", purchaseOrderController.SendPurchaseOrder)       // Mark draft as sent
+	purchaseOrder.POST("/:id/receive", purchaseOrderController.ReceivePurchaseOrder) // Receive goods into stock
+	purchaseOrder.POST("/:id/cancel", purchaseOrderController.CancelPurchaseOrder)   // Cancel purchase order
+
+	// Order endpoints, users only see their own orders
+	order := protected.Group("/orders")
+	order.GET("/", orderController.GetOrders)                   // Get orders
+	order.GET("/:id", orderController.GetOrderByID)             // Get order with lines
+	order.POST("/", orderController.CreateOrder)                // Place order and deduct stock
+	order.PUT("/:id/status", orderController.ChangeOrderStatus) // Pay, ship, cancel or refund order
+
+	// Cart endpoints, every user has one cart
+	cart := protected.Group("/cart")
+	cart.GET("", cartController.GetCart)                            // Get cart with current prices and stock
+	cart.DELETE("", cartController.ClearCart)                       // Empty cart
+	cart.POST("/items", cartController.AddCartItem)                 // Add product or increase quantity
+	cart.PUT("/items/:productId", cartController.UpdateCartItem)    // Change quantity
+	cart.DELETE("/items/:productId", cartController.RemoveCartItem) // Remove product
+	cart.POST("/checkout", cartController.Checkout)                 // Turn cart into order
 
 	// Swagger endpoint
 	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
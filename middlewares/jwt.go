@@ -114,6 +114,7 @@ func JWTAuthMiddleware(authService *services.AuthService) gin.HandlerFunc {
 
 		// Menyimpan username atau informasi lain dari token ke context
 		c.Set("user_id", claims.Subject)
+		c.Set("user", user)
 		c.Next()
 	}
 }
@@ -0,0 +1,38 @@
+package middlewares
+
+import (
+	"net/http"
+	"products-api-with-jwt/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequireRole membatasi akses endpoint hanya untuk user dengan salah satu role yang diberikan.
+// Harus dipasang setelah JWTAuthMiddleware karena membaca user dari context.
+func RequireRole(roles ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		value, exists := c.Get("user")
+		user, ok := value.(*models.User)
+		if !exists || !ok {
+			c.JSON(http.StatusUnauthorized, models.ApiResponse{
+				Status:  "error",
+				Code:    http.StatusUnauthorized,
+				Message: "You are not logged in"})
+			c.Abort()
+			return
+		}
+
+		for _, role := range roles {
+			if user.Role == role {
+				c.Next()
+				return
+			}
+		}
+
+		c.JSON(http.StatusForbidden, models.ApiResponse{
+			Status:  "error",
+			Code:    http.StatusForbidden,
+			Message: "You do not have permission to access this resource"})
+		c.Abort()
+	}
+}
@@ -0,0 +1,47 @@
+package models
+
+import (
+	"regexp"
+	"time"
+)
+
+// Tipe nilai attribute produk
+const (
+	AttributeString  = "string"
+	AttributeNumber  = "number"
+	AttributeInteger = "integer"
+	AttributeBoolean = "boolean"
+	AttributeEnum    = "enum"
+)
+
+// attributeCodePattern membatasi kode attribute agar aman dipakai sebagai key JSON dan di ?attr.<code>=
+var attributeCodePattern = regexp.MustCompile(`^[a-z][a-z0-9_]{0,49}$`)
+
+// ValidAttributeCode memeriksa format kode attribute: huruf kecil, angka dan underscore, diawali huruf
+func ValidAttributeCode(code string) bool {
+	return attributeCodePattern.MatchString(code)
+}
+
+// AttributeDefinition adalah schema satu attribute produk di sebuah kategori.
+// Produk di kategori itu atau di sub-kategorinya divalidasi terhadap schema ini.
+type AttributeDefinition struct {
+	ID         int      `gorm:"primaryKey"`
+	CategoryID int      `gorm:"not null;uniqueIndex:idx_category_attribute"`
+	Code       string   `gorm:"size:50;not null;uniqueIndex:idx_category_attribute"`
+	Name       string   `gorm:"not null"`
+	Type       string   `gorm:"size:20;not null"`
+	Required   bool     `gorm:"not null;default:false"`
+	EnumValues []string `gorm:"type:text;serializer:json" json:"EnumValues,omitempty"` // Hanya untuk tipe enum
+	Unit       string   // Satuan untuk ditampilkan, misalnya kg atau V
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+// AttributeDefinitionInput adalah payload untuk membuat atau mengganti schema attribute
+type AttributeDefinitionInput struct {
+	Name       string   `json:"name" binding:"required,notblank,max=100"`
+	Type       string   `json:"type" binding:"required,oneof=string number integer boolean enum"`
+	Required   bool     `json:"required"`
+	EnumValues []string `json:"enum_values" binding:"required_if=Type enum,max=100,dive,notblank,max=100"`
+	Unit       string   `json:"unit" binding:"max=20"`
+}
@@ -0,0 +1,35 @@
+package models
+
+// Jenis produk. Bundle tidak punya stok sendiri, stok dan penjualannya diambil dari komponennya.
+const (
+	ProductSimple = "simple"
+	ProductBundle = "bundle"
+)
+
+// Cara menghitung harga bundle
+const (
+	BundlePricingFixed      = "fixed"      // Memakai harga bundle itu sendiri
+	BundlePricingComponents = "components" // Jumlah harga berlaku komponen dikurangi BundleDiscountPercent
+)
+
+// BundleComponent adalah satu produk penyusun bundle beserta jumlahnya per unit bundle
+type BundleComponent struct {
+	ID          int      `gorm:"primaryKey"`
+	BundleID    int      `gorm:"not null;uniqueIndex:idx_bundle_component"`
+	ComponentID int      `gorm:"not null;uniqueIndex:idx_bundle_component;index"`
+	Quantity    int      `gorm:"not null"`
+	Component   *Product `gorm:"foreignKey:ComponentID" json:"Component,omitempty"`
+}
+
+// BundleInput adalah payload untuk menjadikan produk sebuah bundle atau mengganti komponennya
+type BundleInput struct {
+	Components      []BundleComponentInput `json:"components" binding:"required,min=1,max=50,dive"`
+	Pricing         string                 `json:"pricing" binding:"required,oneof=fixed components"`
+	DiscountPercent float64                `json:"discount_percent" binding:"gte=0,lte=100"` // Hanya untuk pricing components
+}
+
+// BundleComponentInput adalah satu komponen di BundleInput
+type BundleComponentInput struct {
+	ProductID int `json:"product_id" binding:"required,gte=1"`
+	Quantity  int `json:"quantity" binding:"required,gte=1,lte=1000"`
+}
@@ -0,0 +1,69 @@
+package models
+
+import "time"
+
+// Masalah item cart yang ditemukan saat cart divalidasi ulang
+const (
+	CartItemUnavailable       = "unavailable"        // Produk dihapus atau tidak published
+	CartItemInsufficientStock = "insufficient_stock" // Quantity melebihi stok yang tersedia
+	CartItemCurrencyMismatch  = "currency_mismatch"  // Mata uang berbeda dengan item lain di cart
+)
+
+// Cart adalah keranjang belanja milik satu user. Harga, stok dan total tidak disimpan,
+// semuanya dihitung ulang setiap kali cart dibaca.
+type Cart struct {
+	ID            int        `gorm:"primaryKey"`
+	UserID        int        `gorm:"not null;uniqueIndex"`
+	Items         []CartItem `json:"Items"`
+	ExpiresAt     time.Time  `gorm:"not null;index"` // Diperpanjang setiap kali isi cart berubah
+	Currency      string     `gorm:"-"`
+	SubtotalMinor int64      `gorm:"-"`
+	DiscountMinor int64      `gorm:"-"`
+	TaxMinor      int64      `gorm:"-"` // Pajak di DefaultTaxRegion, dihitung ulang dengan region checkout
+	TotalMinor    int64      `gorm:"-"` // Jumlah item tanpa masalah, termasuk pajak
+	Total         float64    `gorm:"-"`
+	Valid         bool       `gorm:"-"` // Tidak ada item yang bermasalah, cart siap di-checkout
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+// CartItem adalah satu produk di cart. AddedPriceMinor adalah harga per unit (termasuk price list user)
+// saat item terakhir ditambahkan atau diubah quantity-nya, untuk memberi tahu user jika harganya berubah sejak itu.
+type CartItem struct {
+	ID              int     `gorm:"primaryKey"`
+	CartID          int     `gorm:"not null;uniqueIndex:idx_cart_product"`
+	ProductID       int     `gorm:"not null;uniqueIndex:idx_cart_product;index"`
+	Quantity        int     `gorm:"not null"`
+	AddedPriceMinor int64   `gorm:"not null;default:0"`
+	NamaProduk      string  `gorm:"-"`
+	Currency        string  `gorm:"-"`
+	UnitPriceMinor  int64   `gorm:"-"` // Harga berlaku per unit saat ini, termasuk price list user
+	ListPriceMinor  int64   `gorm:"-"` // Harga katalog per unit saat ini
+	DiscountMinor   int64   `gorm:"-"`
+	TaxMinor        int64   `gorm:"-"`
+	TotalMinor      int64   `gorm:"-"` // Termasuk pajak
+	UnitPrice       float64 `gorm:"-"`
+	Total           float64 `gorm:"-"`
+	Available       int     `gorm:"-"`
+	PriceChanged    bool    `gorm:"-"`
+	Problem         string  `gorm:"-" json:"Problem,omitempty"` // unavailable, insufficient_stock atau currency_mismatch
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+}
+
+// CartItemInput adalah payload untuk menambahkan produk ke cart
+type CartItemInput struct {
+	ProductID int `json:"product_id" binding:"required,gte=1"`
+	Quantity  int `json:"quantity" binding:"required,gte=1,lte=10000"`
+}
+
+// CartQuantityInput adalah payload untuk mengubah quantity item di cart
+type CartQuantityInput struct {
+	Quantity int `json:"quantity" binding:"required,gte=1,lte=10000"`
+}
+
+// CheckoutInput adalah payload untuk mengubah cart menjadi order
+type CheckoutInput struct {
+	Region string `json:"region" binding:"max=10"` // Region pajak order, default DefaultTaxRegion
+	Notes  string `json:"notes" binding:"max=1000"`
+}
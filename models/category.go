@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// Category adalah node di taksonomi produk. ParentID kosong berarti kategori root.
+type Category struct {
+	ID        int        `gorm:"primaryKey"`
+	Name      string     `gorm:"not null"`
+	ParentID  *int       `gorm:"index"`
+	Children  []Category `gorm:"foreignKey:ParentID" json:"Children,omitempty"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// CategoryInput adalah payload untuk membuat atau memperbarui kategori
+type CategoryInput struct {
+	Name     string `json:"name" binding:"required,notblank,max=100"`
+	ParentID *int   `json:"parent_id" binding:"omitempty,gte=1"`
+}
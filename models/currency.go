@@ -0,0 +1,47 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ExchangeRate menyatakan 1 unit FromCurrency bernilai Rate unit ToCurrency.
+// Kebalikannya dipakai otomatis jika rate arah sebaliknya tidak ada.
+type ExchangeRate struct {
+	ID           int     `gorm:"primaryKey"`
+	FromCurrency string  `gorm:"size:3;not null;uniqueIndex:idx_exchange_rate_pair"`
+	ToCurrency   string  `gorm:"size:3;not null;uniqueIndex:idx_exchange_rate_pair"`
+	Rate         float64 `gorm:"not null"`
+	UserID       int     // Admin yang terakhir mengubah rate
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+// ExchangeRateInput adalah payload untuk menyimpan exchange rate
+type ExchangeRateInput struct {
+	Rate float64 `json:"rate" binding:"gt=0,lte=1000000000"`
+}
+
+// ProductCurrencyPrice adalah harga tetap produk di mata uang lain. Jika ada, harga ini
+// dipakai alih-alih hasil konversi exchange rate.
+type ProductCurrencyPrice struct {
+	ID          int     `gorm:"primaryKey"`
+	ProductID   int     `gorm:"not null;uniqueIndex:idx_product_currency"`
+	Currency    string  `gorm:"size:3;not null;uniqueIndex:idx_product_currency"`
+	AmountMinor int64   `gorm:"not null"`
+	Amount      float64 `gorm:"-"` // AmountMinor dalam satuan mata uang, dihitung saat dibaca
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// AfterFind menghitung Amount dari AmountMinor
+func (p *ProductCurrencyPrice) AfterFind(tx *gorm.DB) error {
+	p.Amount = FromMinor(p.AmountMinor, p.Currency)
+	return nil
+}
+
+// CurrencyPriceInput adalah payload untuk menyimpan harga produk di mata uang lain
+type CurrencyPriceInput struct {
+	Amount float64 `json:"amount" binding:"gt=0,lte=1000000000"`
+}
@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+// ProductImage adalah gambar produk yang filenya disimpan di storage.
+// URL dan ThumbnailURL adalah signed URL yang dibuat saat dibaca dan hanya berlaku sementara.
+type ProductImage struct {
+	ID           int    `gorm:"primaryKey"`
+	ProductID    int    `gorm:"not null;index"`
+	FileName     string // Nama file asli dari client
+	ContentType  string `gorm:"not null"`
+	Size         int64  `gorm:"not null"`
+	Width        int    `gorm:"not null"`
+	Height       int    `gorm:"not null"`
+	StorageKey   string `gorm:"not null" json:"-"`
+	ThumbnailKey string `gorm:"not null" json:"-"`
+	Position     int    `gorm:"not null;default:0"`
+	IsPrimary    bool   `gorm:"not null;default:false"`
+	CreatedAt    time.Time
+	URL          string `gorm:"-"`
+	ThumbnailURL string `gorm:"-"`
+}
+
+// UpdateImageInput adalah payload untuk mengubah urutan gambar atau menjadikannya gambar utama
+type UpdateImageInput struct {
+	Position  *int  `json:"position" binding:"omitempty,gte=0"`
+	IsPrimary *bool `json:"is_primary"`
+}
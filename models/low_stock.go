@@ -0,0 +1,12 @@
+package models
+
+import "time"
+
+// LowStockEvent dikirim ke notifier saat stok produk turun sampai atau di bawah reorder point
+type LowStockEvent struct {
+	ProductID    int       `json:"product_id"`
+	NamaProduk   string    `json:"nama_produk"`
+	Stok         int       `json:"stok"`
+	ReorderPoint int       `json:"reorder_point"`
+	OccurredAt   time.Time `json:"occurred_at"`
+}
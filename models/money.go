@@ -0,0 +1,83 @@
+package models
+
+import (
+	"math/big"
+	"sort"
+	"strconv"
+)
+
+// DefaultCurrency adalah mata uang produk yang tidak menyebut currency, sekaligus mata uang
+// perantara saat tidak ada exchange rate langsung antara dua mata uang
+const DefaultCurrency = "IDR"
+
+// CurrencyExponents memetakan kode ISO 4217 yang didukung ke jumlah digit minor unit-nya
+var CurrencyExponents = map[string]int{
+	"IDR": 2,
+	"USD": 2,
+	"SGD": 2,
+	"EUR": 2,
+	"JPY": 0,
+}
+
+// SupportedCurrencies mengembalikan kode mata uang yang didukung, urut abjad
+func SupportedCurrencies() []string {
+	codes := make([]string, 0, len(CurrencyExponents))
+	for code := range CurrencyExponents {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	return codes
+}
+
+// Sumber harga pada Money
+const (
+	PriceSourceBase        = "base"        // Harga produk di mata uangnya sendiri
+	PriceSourceOverride    = "override"    // Harga khusus produk untuk mata uang tersebut
+	PriceSourceConverted   = "converted"   // Hasil konversi dengan exchange rate
+	PriceSourceUnconverted = "unconverted" // Tidak ada exchange rate, harga tetap di mata uang produk
+)
+
+// Money adalah harga dalam satu mata uang. AmountMinor adalah nilai yang disimpan, Amount hanya untuk tampilan.
+type Money struct {
+	Currency    string
+	AmountMinor int64
+	Amount      float64
+	Source      string
+	Rate        float64 `json:"Rate,omitempty"` // Exchange rate yang dipakai, hanya untuk Source converted
+}
+
+// NewMoney membuat Money dari nilai minor unit
+func NewMoney(minor int64, currency, source string) Money {
+	return Money{Currency: currency, AmountMinor: minor, Amount: FromMinor(minor, currency), Source: source}
+}
+
+// ToMinor mengubah nominal desimal ke minor unit mata uang, dibulatkan half-up
+func ToMinor(amount float64, currency string) int64 {
+	// Representasi desimal terpendek dipakai agar 1.005 tidak menjadi 1.00499999...
+	value, _ := new(big.Rat).SetString(strconv.FormatFloat(amount, 'f', -1, 64))
+	return RoundHalfUp(value.Mul(value, MinorUnitScale(currency)))
+}
+
+// FromMinor mengubah minor unit ke nominal desimal untuk ditampilkan
+func FromMinor(minor int64, currency string) float64 {
+	amount, _ := new(big.Rat).Quo(new(big.Rat).SetInt64(minor), MinorUnitScale(currency)).Float64()
+	return amount
+}
+
+// RoundHalfUp membulatkan ke bilangan bulat terdekat, nilai tepat setengah dibulatkan menjauhi nol
+func RoundHalfUp(value *big.Rat) int64 {
+	half := big.NewRat(1, 2)
+	abs := new(big.Rat).Abs(value)
+	abs.Add(abs, half)
+	rounded := new(big.Int).Quo(abs.Num(), abs.Denom())
+	if value.Sign() < 0 {
+		rounded.Neg(rounded)
+	}
+	return rounded.Int64()
+}
+
+// MinorUnitScale mengembalikan 10^exponent mata uang, yaitu jumlah minor unit dalam satu unit
+func MinorUnitScale(currency string) *big.Rat {
+	scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(CurrencyExponents[currency])), nil)
+	return new(big.Rat).SetInt(scale)
+}
@@ -0,0 +1,94 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Status order. Alurnya pending -> paid -> shipped. Order pending bisa di-cancel,
+// order paid atau shipped bisa di-refund.
+const (
+	OrderPending   = "pending"
+	OrderPaid      = "paid"
+	OrderShipped   = "shipped"
+	OrderCancelled = "cancelled"
+	OrderRefunded  = "refunded"
+)
+
+// Order adalah penjualan ke user. Harga setiap baris disalin saat order dibuat
+// sehingga perubahan harga atau promo sesudahnya tidak mengubah order.
+type Order struct {
+	ID            int     `gorm:"primaryKey"`
+	UserID        int     `gorm:"not null;index"`
+	Status        string  `gorm:"size:20;not null;index"`
+	Currency      string  `gorm:"size:3;not null"`
+	Region        string  `gorm:"size:10;not null;default:ID"` // Region pajak order
+	SubtotalMinor int64   `gorm:"not null;default:0"`          // Jumlah harga baris sebelum promo
+	DiscountMinor int64   `gorm:"not null;default:0"`
+	TaxMinor      int64   `gorm:"not null;default:0"` // Pajak semua baris, termasuk pajak yang sudah ada di harga
+	TotalMinor    int64   `gorm:"not null;default:0"` // Jumlah yang dibayar, termasuk pajak
+	Total         float64 `gorm:"-"`
+	Notes         string
+	Lines         []OrderLine `json:"Lines,omitempty"`
+	PaidAt        *time.Time
+	ShippedAt     *time.Time
+	CancelledAt   *time.Time
+	RefundedAt    *time.Time
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+// AfterFind menghitung Total dari TotalMinor
+func (o *Order) AfterFind(tx *gorm.DB) error {
+	o.Total = FromMinor(o.TotalMinor, o.Currency)
+	return nil
+}
+
+// OrderLine adalah satu produk di order beserta salinan nama dan harganya
+type OrderLine struct {
+	ID               int    `gorm:"primaryKey"`
+	OrderID          int    `gorm:"not null;index"`
+	ProductID        int    `gorm:"not null;index"`
+	NamaProduk       string `gorm:"not null"`
+	SKU              string
+	Quantity         int     `gorm:"not null"`
+	UnitPriceMinor   int64   `gorm:"not null"`              // Harga berlaku per unit saat order dibuat
+	ListPriceMinor   int64   `gorm:"not null;default:0"`    // Harga katalog per unit sebelum price list
+	PriceListID      *int    `json:"PriceListID,omitempty"` // Price list customer yang dipakai
+	DiscountMinor    int64   `gorm:"not null;default:0"`
+	TaxRate          float64 `gorm:"not null;default:0"` // Persen yang berlaku saat order dibuat
+	PriceIncludesTax bool    `gorm:"not null;default:false"`
+	TaxMinor         int64   `gorm:"not null;default:0"`
+	TotalMinor       int64   `gorm:"not null"` // UnitPriceMinor * Quantity - DiscountMinor, ditambah TaxMinor jika harga belum termasuk pajak
+	Currency         string  `gorm:"size:3;not null"`
+	UnitPrice        float64 `gorm:"-"`
+	Tax              float64 `gorm:"-"`
+	Total            float64 `gorm:"-"`
+}
+
+// AfterFind menghitung UnitPrice, Tax dan Total dari nilai minor unit
+func (l *OrderLine) AfterFind(tx *gorm.DB) error {
+	l.UnitPrice = FromMinor(l.UnitPriceMinor, l.Currency)
+	l.Tax = FromMinor(l.TaxMinor, l.Currency)
+	l.Total = FromMinor(l.TotalMinor, l.Currency)
+	return nil
+}
+
+// OrderInput adalah payload untuk membuat order
+type OrderInput struct {
+	Region string           `json:"region" binding:"max=10"` // Region pajak, default DefaultTaxRegion
+	Notes  string           `json:"notes" binding:"max=1000"`
+	Lines  []OrderLineInput `json:"lines" binding:"required,min=1,max=100,dive"`
+}
+
+// OrderLineInput adalah satu produk di OrderInput
+type OrderLineInput struct {
+	ProductID int `json:"product_id" binding:"required,gte=1"`
+	Quantity  int `json:"quantity" binding:"required,gte=1,lte=10000"`
+}
+
+// OrderStatusInput adalah payload untuk memindahkan order ke status berikutnya
+type OrderStatusInput struct {
+	Status string `json:"status" binding:"required,oneof=paid shipped cancelled refunded"`
+}
@@ -0,0 +1,55 @@
+package models
+
+import "time"
+
+// Sumber perubahan harga
+const (
+	PriceChangeInitial       = "initial"
+	PriceChangeManual        = "manual"
+	PriceChangeScheduled     = "scheduled"      // Harga terjadwal mulai berlaku
+	PriceChangeScheduleEnded = "schedule_ended" // Harga terjadwal berakhir, harga sebelumnya dipulihkan
+)
+
+// Status harga terjadwal
+const (
+	ScheduledPricePending   = "pending"
+	ScheduledPriceActive    = "active"
+	ScheduledPriceCompleted = "completed"
+	ScheduledPriceCancelled = "cancelled"
+)
+
+// PriceChange adalah entri append-only riwayat harga produk. UserID 0 berarti perubahan oleh scheduler.
+type PriceChange struct {
+	ID               int    `gorm:"primaryKey"`
+	ProductID        int    `gorm:"not null;index"`
+	OldHargaMinor    int64  `gorm:"not null;default:0"`
+	NewHargaMinor    int64  `gorm:"not null;default:0"`
+	Currency         string `gorm:"size:3;not null;default:IDR"`
+	Source           string `gorm:"not null"`
+	ScheduledPriceID *int
+	UserID           int
+	CreatedAt        time.Time `gorm:"index"`
+}
+
+// ScheduledPrice adalah harga yang berlaku mulai EffectiveFrom sampai EffectiveTo, dalam mata uang produk.
+// Tanpa EffectiveTo harga berlaku seterusnya; dengan EffectiveTo harga sebelumnya dipulihkan saat berakhir.
+type ScheduledPrice struct {
+	ID                 int       `gorm:"primaryKey"`
+	ProductID          int       `gorm:"not null;index"`
+	HargaMinor         int64     `gorm:"not null;default:0"`
+	Currency           string    `gorm:"size:3;not null;default:IDR"`
+	EffectiveFrom      time.Time `gorm:"not null;index"`
+	EffectiveTo        *time.Time
+	Status             string `gorm:"not null;index"`
+	PreviousHargaMinor *int64 // Harga sebelum jadwal ini diterapkan, dipakai saat EffectiveTo tercapai
+	UserID             int
+	CreatedAt          time.Time
+	UpdatedAt          time.Time
+}
+
+// ScheduledPriceInput adalah payload untuk menjadwalkan harga baru. Harga dalam mata uang produk.
+type ScheduledPriceInput struct {
+	Harga         float64    `json:"harga" binding:"gte=0,lte=1000000000"`
+	EffectiveFrom time.Time  `json:"effective_from" binding:"required"`
+	EffectiveTo   *time.Time `json:"effective_to" binding:"omitempty,gtfield=EffectiveFrom"`
+}
@@ -0,0 +1,88 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Jenis aturan price list
+const (
+	PriceListFixed      = "fixed"      // Harga tetap per unit dalam mata uang produk, hanya untuk aturan produk
+	PriceListPercentage = "percentage" // Potongan persen dari harga katalog
+)
+
+// Jenis assignment price list
+const (
+	PriceListCustomerGroup = "customer_group"
+	PriceListDepartment    = "department"
+)
+
+// PriceList adalah daftar harga khusus untuk customer group atau department tertentu, misalnya harga grosir.
+// Setiap customer group dan department hanya bisa di-assign ke satu price list.
+type PriceList struct {
+	ID          int    `gorm:"primaryKey"`
+	Name        string `gorm:"size:100;not null;uniqueIndex"`
+	Description string
+	Active      bool                  `gorm:"not null;default:false"`
+	Assignments []PriceListAssignment `json:"Assignments,omitempty"`
+	Rules       []PriceListRule       `json:"Rules,omitempty"`
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// PriceListAssignment menghubungkan price list dengan satu customer group atau department user
+type PriceListAssignment struct {
+	ID          int    `gorm:"primaryKey"`
+	PriceListID int    `gorm:"not null;index"`
+	Type        string `gorm:"size:20;not null;uniqueIndex:idx_price_list_assignment"` // customer_group atau department
+	Value       string `gorm:"size:50;not null;uniqueIndex:idx_price_list_assignment"`
+}
+
+// PriceListRule adalah harga khusus untuk satu produk atau semua produk di kategori (termasuk sub-kategori)
+// mulai MinQuantity unit. Aturan produk didahulukan dari aturan kategori, lalu tier MinQuantity tertinggi yang terpenuhi.
+type PriceListRule struct {
+	ID          int     `gorm:"primaryKey"`
+	PriceListID int     `gorm:"not null;index"`
+	ProductID   *int    `gorm:"index" json:"ProductID,omitempty"`
+	CategoryID  *int    `gorm:"index" json:"CategoryID,omitempty"`
+	MinQuantity int     `gorm:"not null;default:1"`
+	Type        string  `gorm:"size:20;not null"`
+	PriceMinor  int64   `gorm:"not null;default:0" json:"PriceMinor,omitempty"` // Harga per unit untuk jenis fixed, dalam Currency
+	Currency    string  `gorm:"size:3" json:"Currency,omitempty"`               // Mata uang produk saat aturan dibuat
+	Price       float64 `gorm:"-" json:"Price,omitempty"`
+	Percentage  float64 `gorm:"not null;default:0" json:"Percentage,omitempty"`
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// AfterFind menghitung Price dari PriceMinor
+func (r *PriceListRule) AfterFind(tx *gorm.DB) error {
+	r.Price = FromMinor(r.PriceMinor, r.Currency)
+	return nil
+}
+
+// PriceListInput adalah payload untuk membuat atau mengganti price list beserta assignment-nya
+type PriceListInput struct {
+	Name           string   `json:"name" binding:"required,notblank,max=100"`
+	Description    string   `json:"description" binding:"max=500"`
+	Active         *bool    `json:"active"` // Default true
+	CustomerGroups []string `json:"customer_groups" binding:"max=50,dive,notblank,max=50"`
+	Departments    []string `json:"departments" binding:"max=50,dive,notblank,max=50"`
+}
+
+// PriceListRuleInput adalah payload untuk menambah atau mengganti aturan price list.
+// Tepat satu dari ProductID dan CategoryID harus diisi; harga fixed hanya untuk aturan produk.
+type PriceListRuleInput struct {
+	ProductID   *int    `json:"product_id" binding:"omitempty,gte=1"`
+	CategoryID  *int    `json:"category_id" binding:"omitempty,gte=1"`
+	MinQuantity int     `json:"min_quantity" binding:"gte=0,lte=1000000"` // Default 1
+	Type        string  `json:"type" binding:"required,oneof=fixed percentage"`
+	Price       float64 `json:"price" binding:"required_if=Type fixed,gte=0,lte=1000000000"` // Dalam mata uang produk
+	Percentage  float64 `json:"percentage" binding:"required_if=Type percentage,gte=0,lte=100"`
+}
+
+// CustomerGroupInput adalah payload untuk mengganti customer group user. Kosong berarti tanpa customer group.
+type CustomerGroupInput struct {
+	CustomerGroup string `json:"customer_group" binding:"max=50"`
+}
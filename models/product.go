@@ -1,9 +1,77 @@
 package models
 
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
 type Product struct {
-	ID         int    `gorm:"primaryKey"`
-	NamaProduk string `gorm:"not null"`
-	Deskripsi  string
-	Harga      float64
-	Stok       int
+	ID                    int     `gorm:"primaryKey"`
+	NamaProduk            string  `gorm:"not null"`
+	SKU                   *string `gorm:"uniqueIndex"`
+	Barcode               *string `gorm:"uniqueIndex"` // EAN-13, EAN-8 atau UPC-A
+	Deskripsi             string
+	Locale                string              `gorm:"-" json:"Locale,omitempty"`   // Bahasa NamaProduk di response; field yang belum diterjemahkan memakai DefaultLocale
+	HargaMinor            int64               `gorm:"not null;default:0"`          // Harga dalam minor unit Currency (sen untuk IDR)
+	Currency              string              `gorm:"size:3;not null;default:IDR"` // Kode ISO 4217, tidak bisa diubah setelah produk dibuat
+	Harga                 float64             `gorm:"-"`                           // HargaMinor dalam satuan mata uang, dihitung saat dibaca
+	EffectivePriceMinor   int64               `gorm:"-"`                           // Harga yang berlaku saat ini, termasuk harga terjadwal yang sedang aktif
+	EffectivePrice        float64             `gorm:"-"`
+	ListPriceMinor        int64               `gorm:"-"` // Harga katalog sebelum price list customer group, untuk perbandingan
+	ListPrice             float64             `gorm:"-"`
+	PriceListID           *int                `gorm:"-" json:"PriceListID,omitempty"`    // Price list yang menentukan EffectivePrice
+	Price                 *Money              `gorm:"-" json:"Price,omitempty"`          // Harga berlaku dalam mata uang ?currency=
+	TaxClassID            *int                `gorm:"index" json:"TaxClassID,omitempty"` // Tanpa tax class produk tidak dikenai pajak
+	PriceIncludesTax      bool                `gorm:"not null;default:false"`            // Harga sudah termasuk pajak
+	Stok                  int                 // Total stok di semua warehouse. Untuk bundle dihitung dari komponennya.
+	Reserved              int                 `gorm:"not null;default:0"`                       // Stok yang sedang ditahan oleh reservation
+	Available             int                 `gorm:"-"`                                        // Stok - Reserved, dihitung saat dibaca
+	Status                string              `gorm:"size:20;not null;default:published;index"` // draft, published atau archived
+	Type                  string              `gorm:"size:20;not null;default:simple"`          // simple atau bundle
+	BundlePricing         string              `gorm:"size:20" json:"BundlePricing,omitempty"`   // fixed atau components, hanya untuk bundle
+	BundleDiscountPercent float64             `gorm:"not null;default:0" json:"BundleDiscountPercent,omitempty"`
+	PublishAt             *time.Time          // Jadwal publish draft
+	UnpublishAt           *time.Time          // Jadwal archive produk yang sudah published
+	PublishedAt           *time.Time          // Waktu terakhir produk di-publish
+	Department            string              `gorm:"index"`
+	RatingAverage         float64             `gorm:"not null;default:0"`                                       // Rata-rata rating review approved, 2 desimal
+	RatingCount           int                 `gorm:"not null;default:0"`                                       // Jumlah review approved
+	ReorderPoint          int                 `gorm:"not null;default:0"`                                       // Alert low-stock saat Stok <= ReorderPoint, 0 berarti nonaktif
+	LowStockAlerted       bool                `gorm:"not null;default:false"`                                   // Alert sudah dikirim, direset saat stok naik lagi
+	HasHadImages          bool                `gorm:"not null;default:false" json:"-"`                          // Pernah punya gambar, sejak itu publish memerlukan minimal satu gambar
+	Categories            []Category          `gorm:"many2many:product_categories" json:"Categories,omitempty"` // Hanya dengan ?include=categories
+	Attributes            map[string]any      `gorm:"type:text;serializer:json" json:"Attributes,omitempty"`    // Divalidasi terhadap schema attribute kategorinya
+	Tags                  []Tag               `gorm:"many2many:product_tags" json:"Tags,omitempty"`             // Hanya dengan ?include=tags
+	Options               []ProductOption     `json:"Options,omitempty"`                                        // Hanya dengan ?include=variants
+	Variants              []ProductVariant    `json:"Variants,omitempty"`                                       // Hanya dengan ?include=variants
+	Components            []BundleComponent   `gorm:"foreignKey:BundleID" json:"Components,omitempty"`          // Hanya dengan ?include=components
+	Related               []ProductRelation   `gorm:"foreignKey:ProductID" json:"Related,omitempty"`            // Hanya dengan ?include=related
+	Suggestions           []ProductSuggestion `gorm:"-" json:"Suggestions,omitempty"`                           // Hanya dengan ?include=suggestions di detail produk
+	Locations             []WarehouseStock    `json:"Locations,omitempty"`                                      // Rincian stok per warehouse, hanya dengan ?include=locations
+	DeletedAt             gorm.DeletedAt      `gorm:"index" swaggertype:"string" format:"date-time"`            // Soft delete, dihapus permanen oleh purge scheduler
+}
+
+// AfterFind menghitung stok yang masih tersedia untuk dijual dan harga dalam satuan mata uang.
+// EffectivePrice default ke Harga, ProductService menimpanya jika ada harga terjadwal yang sedang berlaku.
+func (p *Product) AfterFind(tx *gorm.DB) error {
+	p.Available = p.Stok - p.Reserved
+	p.Harga = FromMinor(p.HargaMinor, p.Currency)
+	p.SetEffectivePrice(p.HargaMinor)
+	return nil
+}
+
+// SetEffectivePrice mengisi EffectivePriceMinor dan ListPriceMinor beserta nilai tampilannya
+func (p *Product) SetEffectivePrice(minor int64) {
+	p.EffectivePriceMinor = minor
+	p.EffectivePrice = FromMinor(minor, p.Currency)
+	p.ListPriceMinor = minor
+	p.ListPrice = p.EffectivePrice
+}
+
+// SetCustomerPrice mengganti EffectivePrice dengan harga dari price list, ListPrice tetap harga katalog
+func (p *Product) SetCustomerPrice(minor int64, priceListID int) {
+	p.EffectivePriceMinor = minor
+	p.EffectivePrice = FromMinor(minor, p.Currency)
+	p.PriceListID = &priceListID
 }
@@ -0,0 +1,34 @@
+package models
+
+// CreateProductInput adalah payload untuk membuat produk baru
+type CreateProductInput struct {
+	NamaProduk       string         `json:"nama_produk" binding:"required,notblank,nocontrol,max=100"`
+	SKU              string         `json:"sku" binding:"max=64"`
+	Barcode          string         `json:"barcode" binding:"omitempty,barcode"`
+	Deskripsi        string         `json:"deskripsi" binding:"max=1000"`
+	Harga            float64        `json:"harga" binding:"gte=0,lte=1000000000"`  // Dalam satuan Currency, disimpan sebagai minor unit
+	Currency         string         `json:"currency" binding:"omitempty,currency"` // Kode ISO 4217, default IDR
+	Stok             int            `json:"stok" binding:"gte=0,lte=1000000"`      // Dicatat sebagai receipt awal di ledger
+	Department       string         `json:"department" binding:"max=50"`           // Default ke department user yang membuat
+	ReorderPoint     int            `json:"reorder_point" binding:"gte=0,lte=1000000"`
+	TaxClassID       *int           `json:"tax_class_id" binding:"omitempty,gte=1"`
+	PriceIncludesTax bool           `json:"price_includes_tax"`
+	CategoryIDs      []int          `json:"category_ids" binding:"omitempty,max=20,dive,gte=1"`
+	Attributes       map[string]any `json:"attributes" binding:"max=50"` // Kode attribute -> nilai, sesuai schema kategori produk
+}
+
+// UpdateProductInput adalah payload untuk memperbarui produk, hanya field yang dikirim yang diubah.
+// Stok tidak bisa diubah di sini, gunakan endpoint stock adjustment agar tercatat di ledger.
+type UpdateProductInput struct {
+	NamaProduk       *string         `json:"nama_produk" binding:"omitempty,notblank,nocontrol,max=100"`
+	SKU              *string         `json:"sku" binding:"omitempty,max=64"`      // String kosong menghapus SKU
+	Barcode          *string         `json:"barcode" binding:"omitempty,barcode"` // String kosong menghapus barcode
+	Deskripsi        *string         `json:"deskripsi" binding:"omitempty,max=1000"`
+	Harga            *float64        `json:"harga" binding:"omitempty,gte=0,lte=1000000000"`
+	Department       *string         `json:"department" binding:"omitempty,max=50"`
+	ReorderPoint     *int            `json:"reorder_point" binding:"omitempty,gte=0,lte=1000000"`
+	TaxClassID       *int            `json:"tax_class_id" binding:"omitempty,gte=0"` // 0 menghapus tax class
+	PriceIncludesTax *bool           `json:"price_includes_tax"`
+	CategoryIDs      *[]int          `json:"category_ids" binding:"omitempty,max=20,dive,gte=1"` // Mengganti semua kategori produk
+	Attributes       *map[string]any `json:"attributes" binding:"omitempty,max=50"`              // Mengganti semua attribute produk
+}
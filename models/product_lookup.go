@@ -0,0 +1,10 @@
+package models
+
+// ProductLookup adalah hasil pencarian produk berdasarkan SKU atau barcode.
+// Variant terisi jika kode yang dicari milik salah satu variant produk.
+type ProductLookup struct {
+	ProductID int             `json:"-"`
+	VariantID *int            `json:"-"`
+	Product   Product         `gorm:"foreignKey:ProductID"`
+	Variant   *ProductVariant `gorm:"foreignKey:VariantID" json:"Variant,omitempty"`
+}
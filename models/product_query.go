@@ -0,0 +1,21 @@
+package models
+
+// ProductQuery berisi opsi query untuk endpoint baca produk
+type ProductQuery struct {
+	Include            map[string]bool   // Data terkait yang ikut dimuat, dari ?include=a,b
+	CategoryID         int               // Filter kategori, dari ?category=
+	IncludeDescendants bool              // Ikutkan produk di sub-kategori, dari ?include_descendants=true
+	Tags               []string          // Filter tag ternormalisasi, dari ?tags=a,b
+	MatchAllTags       bool              // true jika ?match=all (produk harus punya semua tag), default any
+	Currency           string            // Mata uang harga yang ditampilkan di Price, dari ?currency=
+	Statuses           []string          // Filter status, dari ?status=a,b
+	IncludeDrafts      bool              // Draft hanya terlihat oleh editor (admin)
+	Locale             string            // Bahasa konten produk, dari ?lang= atau Accept-Language
+	UserID             int               // User yang meminta, harga mengikuti price list customer group atau department-nya
+	Attributes         map[string]string // Filter ?attr.<code>=, ?attr.<code>.min= dan ?attr.<code>.max=, key tanpa prefix attr.
+}
+
+// Includes memeriksa apakah data terkait dengan nama tersebut diminta
+func (q *ProductQuery) Includes(name string) bool {
+	return q != nil && q.Include[name]
+}
@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+// Status lifecycle produk. Transisi yang diizinkan hanya draft -> published -> archived.
+const (
+	ProductDraft     = "draft"
+	ProductPublished = "published"
+	ProductArchived  = "archived"
+)
+
+// ProductStatusInput adalah payload untuk memindahkan produk ke status berikutnya
+type ProductStatusInput struct {
+	Status string `json:"status" binding:"required,oneof=published archived"`
+}
+
+// ProductStatusScheduleInput mengatur jadwal publish dan archive. Nilai null menghapus jadwal.
+type ProductStatusScheduleInput struct {
+	PublishAt   *time.Time `json:"publish_at"`   // Hanya untuk produk draft
+	UnpublishAt *time.Time `json:"unpublish_at"` // Produk di-archive saat waktu ini tercapai
+}
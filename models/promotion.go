@@ -0,0 +1,104 @@
+package models
+
+import "time"
+
+// Jenis promo
+const (
+	PromotionPercentage = "percentage"  // Potongan persen dari harga
+	PromotionFixed      = "fixed"       // Potongan nominal tetap per unit
+	PromotionBuyXGetY   = "buy_x_get_y" // Setiap BuyQuantity unit dibeli, GetQuantity unit berikutnya gratis
+)
+
+// Promotion adalah aturan diskon yang berlaku dalam jendela waktu untuk produk, kategori atau tag tertentu.
+// Promo diterapkan berurutan dari Priority tertinggi; promo Exclusive tidak bisa digabung dengan promo lain.
+type Promotion struct {
+	ID          int    `gorm:"primaryKey"`
+	Name        string `gorm:"not null"`
+	Type        string `gorm:"not null"`
+	Percentage  float64
+	AmountMinor int64  // Potongan per unit untuk jenis fixed, dalam Currency
+	Currency    string `gorm:"size:3"` // Hanya untuk jenis fixed, promo hanya berlaku untuk produk dengan mata uang yang sama
+	BuyQuantity int
+	GetQuantity int
+	MinQuantity int        `gorm:"not null;default:1"`
+	ProductIDs  []int      `gorm:"type:text;serializer:json"`
+	CategoryIDs []int      `gorm:"type:text;serializer:json"` // Termasuk produk di sub-kategori
+	Tags        []string   `gorm:"type:text;serializer:json"`
+	StartsAt    *time.Time `gorm:"index"`
+	EndsAt      *time.Time `gorm:"index"`
+	Active      bool       `gorm:"not null;default:false"`
+	Priority    int        `gorm:"not null;default:0"`
+	Exclusive   bool       `gorm:"not null;default:false"`
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// PromotionInput adalah payload untuk membuat atau mengganti promo. Minimal satu target harus diisi.
+type PromotionInput struct {
+	Name        string     `json:"name" binding:"required,notblank,max=100"`
+	Type        string     `json:"type" binding:"required,oneof=percentage fixed buy_x_get_y"`
+	Percentage  float64    `json:"percentage" binding:"required_if=Type percentage,gte=0,lte=100"`
+	Amount      float64    `json:"amount" binding:"required_if=Type fixed,gte=0,lte=1000000000"` // Potongan per unit untuk jenis fixed
+	Currency    string     `json:"currency" binding:"omitempty,currency"`                        // Mata uang amount, default IDR
+	BuyQuantity int        `json:"buy_quantity" binding:"required_if=Type buy_x_get_y,gte=0,lte=1000"`
+	GetQuantity int        `json:"get_quantity" binding:"required_if=Type buy_x_get_y,gte=0,lte=1000"`
+	MinQuantity int        `json:"min_quantity" binding:"gte=0,lte=1000000"` // Default 1
+	ProductIDs  []int      `json:"product_ids" binding:"max=100,dive,gte=1"`
+	CategoryIDs []int      `json:"category_ids" binding:"max=100,dive,gte=1"`
+	Tags        []string   `json:"tags" binding:"max=100,dive,notblank,max=50"`
+	StartsAt    *time.Time `json:"starts_at"`
+	EndsAt      *time.Time `json:"ends_at"`
+	Active      *bool      `json:"active"` // Default true
+	Priority    int        `json:"priority" binding:"gte=-1000,lte=1000"`
+	Exclusive   bool       `json:"exclusive"`
+}
+
+// PromotionDryRunInput adalah payload untuk mensimulasikan harga produk pada waktu tertentu.
+// Promotion opsional dievaluasi seolah-olah sudah disimpan, tanpa benar-benar disimpan.
+type PromotionDryRunInput struct {
+	ProductID int             `json:"product_id" binding:"required,gte=1"`
+	Quantity  int             `json:"quantity" binding:"required,gte=1,lte=1000000"`
+	At        *time.Time      `json:"at"`                      // Default sekarang
+	Region    string          `json:"region"`                  // Region pajak, default DefaultTaxRegion
+	UserID    int             `json:"user_id" binding:"gte=0"` // Pakai price list user ini, default harga katalog
+	Promotion *PromotionInput `json:"promotion"`
+}
+
+// PriceLine adalah satu baris rincian harga. AmountMinor negatif untuk diskon.
+type PriceLine struct {
+	Description string
+	PromotionID *int `json:"PromotionID,omitempty"`
+	AmountMinor int64
+	Amount      float64
+}
+
+// SkippedPromotion adalah promo yang targetnya cocok tetapi tidak diterapkan, beserta alasannya
+type SkippedPromotion struct {
+	PromotionID int
+	Name        string
+	Reason      string
+}
+
+// PriceQuote adalah harga akhir produk untuk sejumlah unit beserta rinciannya
+type PriceQuote struct {
+	ProductID        int
+	Quantity         int
+	Currency         string
+	UnitPriceMinor   int64 // Harga berlaku per unit sebelum promo, termasuk harga price list
+	ListPriceMinor   int64 // Harga katalog per unit sebelum price list
+	PriceListID      *int  `json:"PriceListID,omitempty"`
+	SubtotalMinor    int64
+	DiscountMinor    int64
+	TotalMinor       int64 // Subtotal dikurangi diskon, dalam harga katalog (termasuk atau belum termasuk pajak)
+	Total            float64
+	TaxRegion        string
+	TaxRate          float64 // Persen, 0 jika produk tidak punya tax class atau tarif di region ini
+	PriceIncludesTax bool
+	NetMinor         int64 // Total tanpa pajak
+	TaxMinor         int64
+	GrossMinor       int64 // Total yang dibayar, termasuk pajak
+	Gross            float64
+	Lines            []PriceLine
+	Skipped          []SkippedPromotion `json:"Skipped,omitempty"`
+	EvaluatedAt      time.Time
+}
@@ -0,0 +1,86 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Status purchase order. Alurnya draft -> sent -> partially_received -> received,
+// draft dan sent yang belum diterima sama sekali bisa di-cancel.
+const (
+	PurchaseOrderDraft             = "draft"
+	PurchaseOrderSent              = "sent"
+	PurchaseOrderPartiallyReceived = "partially_received"
+	PurchaseOrderReceived          = "received"
+	PurchaseOrderCancelled         = "cancelled"
+)
+
+// PurchaseOrder adalah pesanan pembelian ke supplier. Barang yang diterima dicatat sebagai receipt di ledger stok.
+type PurchaseOrder struct {
+	ID         int       `gorm:"primaryKey"`
+	SupplierID int       `gorm:"not null;index"`
+	Supplier   *Supplier `json:"Supplier,omitempty"`
+	Status     string    `gorm:"size:20;not null;index"`
+	Currency   string    `gorm:"size:3;not null"` // Mengikuti mata uang supplier
+	Notes      string
+	TotalMinor int64               `gorm:"not null;default:0"` // Jumlah Quantity * UnitCostMinor semua baris
+	Total      float64             `gorm:"-"`
+	Lines      []PurchaseOrderLine `json:"Lines,omitempty"`
+	UserID     int                 // User yang membuat purchase order
+	SentAt     *time.Time
+	ExpectedAt *time.Time // SentAt ditambah lead time terlama produk di purchase order ini
+	ReceivedAt *time.Time // Waktu semua baris selesai diterima
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+// AfterFind menghitung Total dari TotalMinor
+func (o *PurchaseOrder) AfterFind(tx *gorm.DB) error {
+	o.Total = FromMinor(o.TotalMinor, o.Currency)
+	return nil
+}
+
+// PurchaseOrderLine adalah satu produk di purchase order
+type PurchaseOrderLine struct {
+	ID               int     `gorm:"primaryKey"`
+	PurchaseOrderID  int     `gorm:"not null;index"`
+	ProductID        int     `gorm:"not null;index"`
+	Quantity         int     `gorm:"not null"`
+	ReceivedQuantity int     `gorm:"not null;default:0"`
+	UnitCostMinor    int64   `gorm:"not null"` // Dalam minor unit mata uang purchase order
+	UnitCost         float64 `gorm:"-"`
+	Currency         string  `gorm:"size:3;not null"`
+}
+
+// AfterFind menghitung UnitCost dari UnitCostMinor
+func (l *PurchaseOrderLine) AfterFind(tx *gorm.DB) error {
+	l.UnitCost = FromMinor(l.UnitCostMinor, l.Currency)
+	return nil
+}
+
+// PurchaseOrderInput adalah payload untuk membuat purchase order atau mengganti isinya selama masih draft
+type PurchaseOrderInput struct {
+	SupplierID int                      `json:"supplier_id" binding:"required,gte=1"`
+	Notes      string                   `json:"notes" binding:"max=1000"`
+	Lines      []PurchaseOrderLineInput `json:"lines" binding:"required,min=1,max=200,dive"`
+}
+
+// PurchaseOrderLineInput adalah satu baris di PurchaseOrderInput
+type PurchaseOrderLineInput struct {
+	ProductID int      `json:"product_id" binding:"required,gte=1"`
+	Quantity  int      `json:"quantity" binding:"required,gte=1,lte=1000000"`
+	UnitCost  *float64 `json:"unit_cost" binding:"omitempty,gte=0,lte=1000000000"` // Default harga beli produk di supplier
+}
+
+// ReceivePurchaseOrderInput adalah payload untuk mencatat barang yang diterima
+type ReceivePurchaseOrderInput struct {
+	WarehouseID int                        `json:"warehouse_id"` // Default warehouse default
+	Lines       []ReceivePurchaseLineInput `json:"lines" binding:"required,min=1,max=200,dive"`
+}
+
+// ReceivePurchaseLineInput adalah jumlah yang diterima untuk satu baris purchase order
+type ReceivePurchaseLineInput struct {
+	LineID   int `json:"line_id" binding:"required,gte=1"`
+	Quantity int `json:"quantity" binding:"required,gte=1,lte=1000000"`
+}
@@ -0,0 +1,42 @@
+package models
+
+import "time"
+
+// Jenis hubungan antar produk
+const (
+	RelationAccessory   = "accessory"
+	RelationReplacement = "replacement"
+	RelationUpsell      = "upsell"
+	RelationSimilar     = "similar"
+)
+
+// ProductRelation adalah link satu arah dari produk ke produk lain dengan jenis tertentu
+type ProductRelation struct {
+	ID        int      `gorm:"primaryKey"`
+	ProductID int      `gorm:"not null;uniqueIndex:idx_product_relation"`
+	RelatedID int      `gorm:"not null;uniqueIndex:idx_product_relation;index"`
+	Type      string   `gorm:"size:20;not null;uniqueIndex:idx_product_relation"`
+	Related   *Product `gorm:"foreignKey:RelatedID" json:"Related,omitempty"`
+	CreatedAt time.Time
+}
+
+// RelatedProductInput adalah payload untuk menambah link ke produk lain
+type RelatedProductInput struct {
+	ProductID int    `json:"product_id" binding:"required,gte=1"`
+	Type      string `json:"type" binding:"required,oneof=accessory replacement upsell similar"`
+}
+
+// RelatedProductRemoveInput adalah payload untuk menghapus link. Tanpa type, semua jenis link ke produk itu dihapus.
+type RelatedProductRemoveInput struct {
+	ProductID int    `json:"product_id" binding:"required,gte=1"`
+	Type      string `json:"type" binding:"omitempty,oneof=accessory replacement upsell similar"`
+}
+
+// ProductSuggestion adalah produk mirip yang dihitung otomatis dari kategori, tag dan harga yang sama
+type ProductSuggestion struct {
+	Product          *Product
+	Score            int // 2 per kategori yang sama, 1 per tag yang sama, 1 jika harganya dalam rentang
+	SharedCategories int
+	SharedTags       int
+	InPriceRange     bool
+}
@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+// Status reservation stok
+const (
+	ReservationHeld      = "held"
+	ReservationConfirmed = "confirmed"
+	ReservationReleased  = "released"
+	ReservationExpired   = "expired"
+)
+
+// Reservation menahan sejumlah stok produk sampai dikonfirmasi, dilepas, atau kedaluwarsa
+type Reservation struct {
+	ID        int    `gorm:"primaryKey"`
+	ProductID int    `gorm:"not null;index"`
+	Quantity  int    `gorm:"not null"`
+	Status    string `gorm:"not null;index"`
+	UserID    int
+	Reference string
+	ExpiresAt time.Time `gorm:"not null;index"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// ReservationInput adalah payload untuk membuat reservation baru
+type ReservationInput struct {
+	Quantity   int    `json:"quantity" binding:"required,gte=1,lte=1000000"`
+	TTLSeconds int    `json:"ttl_seconds" binding:"omitempty,gte=1,lte=86400"` // Default dari RESERVATION_TTL
+	Reference  string `json:"reference" binding:"max=100"`
+}
@@ -1,9 +1,10 @@
 package models
 
 type ApiResponse struct {
-	Status  string      `json:"status"`
-	Code    int         `json:"code"`
-	Message string      `json:"message"`
-	Data    interface{} `json:"data"`
-	Count   int         `json:"count,omitempty"` // Optional for lists
+	Status  string       `json:"status"`
+	Code    int          `json:"code"`
+	Message string       `json:"message"`
+	Data    interface{}  `json:"data"`
+	Count   int          `json:"count,omitempty"`  // Optional for lists
+	Errors  []FieldError `json:"errors,omitempty"` // Field errors when validation fails
 }
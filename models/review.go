@@ -0,0 +1,40 @@
+package models
+
+import "time"
+
+// Status moderasi review. Hanya review approved yang tampil dan dihitung di rating produk.
+const (
+	ReviewPending  = "pending"
+	ReviewApproved = "approved"
+	ReviewRejected = "rejected"
+)
+
+// Review adalah rating 1-5 dan ulasan user untuk produk, satu review per user per produk
+type Review struct {
+	ID             int    `gorm:"primaryKey"`
+	ProductID      int    `gorm:"not null;uniqueIndex:idx_product_reviewer"`
+	UserID         int    `gorm:"not null;uniqueIndex:idx_product_reviewer;index"`
+	Username       string // Disalin saat review dibuat
+	Rating         int    `gorm:"not null"`
+	Title          string
+	Body           string
+	Status         string `gorm:"size:20;not null;index"`
+	ModerationNote string `json:"ModerationNote,omitempty"` // Alasan penolakan dari admin
+	ModeratedBy    *int   `json:"ModeratedBy,omitempty"`
+	ModeratedAt    *time.Time
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+// ReviewInput adalah payload untuk menulis atau mengubah review
+type ReviewInput struct {
+	Rating int    `json:"rating" binding:"required,gte=1,lte=5"`
+	Title  string `json:"title" binding:"max=100"`
+	Body   string `json:"body" binding:"max=2000"`
+}
+
+// ReviewModerationInput adalah payload admin untuk menyetujui atau menolak review
+type ReviewModerationInput struct {
+	Status string `json:"status" binding:"required,oneof=approved rejected"`
+	Note   string `json:"note" binding:"max=255"`
+}
@@ -0,0 +1,43 @@
+package models
+
+import "time"
+
+// Jenis pergerakan stok
+const (
+	StockMovementReceipt    = "receipt"
+	StockMovementSale       = "sale"
+	StockMovementAdjustment = "adjustment"
+	StockMovementReturn     = "return"
+	StockMovementTransfer   = "transfer"
+)
+
+// StockMovement adalah entri append-only di ledger stok. Quantity bertanda:
+// positif menambah stok, negatif mengurangi stok di warehouse terkait.
+// BalanceAfter adalah total stok produk di semua warehouse setelah pergerakan, atau total stok
+// variant jika VariantID diisi.
+type StockMovement struct {
+	ID           int    `gorm:"primaryKey"`
+	ProductID    int    `gorm:"not null;index"`
+	VariantID    *int   `gorm:"index" json:"VariantID,omitempty"` // Kosong berarti stok produk itu sendiri
+	WarehouseID  int    `gorm:"not null;default:0;index"`
+	Type         string `gorm:"not null"`
+	Quantity     int    `gorm:"not null"`
+	BalanceAfter int    `gorm:"not null"`
+	UserID       int
+	Reason       string
+	Reference    string
+	CreatedAt    time.Time
+}
+
+// StockAdjustInput adalah payload untuk mencatat pergerakan stok.
+// Untuk receipt, sale dan return quantity selalu positif; untuk adjustment quantity boleh negatif.
+// Tanpa warehouse_id, stok masuk dicatat ke warehouse default dan stok keluar diambil dari
+// warehouse dengan stok terbanyak lebih dulu.
+type StockAdjustInput struct {
+	WarehouseID int    `json:"warehouse_id"`
+	VariantID   int    `json:"variant_id" binding:"gte=0"` // Kosong berarti stok produk itu sendiri
+	Type        string `json:"type" binding:"required,oneof=receipt sale adjustment return"`
+	Quantity    int    `json:"quantity" binding:"required,gte=-1000000,lte=1000000"`
+	Reason      string `json:"reason" binding:"required_if=Type adjustment,max=255"`
+	Reference   string `json:"reference" binding:"max=100"`
+}
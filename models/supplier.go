@@ -0,0 +1,56 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Supplier adalah pemasok tempat produk dibeli lewat purchase order
+type Supplier struct {
+	ID        int    `gorm:"primaryKey"`
+	Name      string `gorm:"not null"`
+	Email     string
+	Phone     string
+	Address   string
+	Currency  string `gorm:"size:3;not null;default:IDR"` // Mata uang harga beli dan purchase order, tidak bisa diubah
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// SupplierInput adalah payload untuk membuat atau memperbarui supplier
+type SupplierInput struct {
+	Name     string `json:"name" binding:"required,notblank,max=100"`
+	Email    string `json:"email" binding:"omitempty,email,max=100"`
+	Phone    string `json:"phone" binding:"max=30"`
+	Address  string `json:"address" binding:"max=255"`
+	Currency string `json:"currency" binding:"omitempty,currency"` // Default IDR, hanya bisa diisi saat supplier dibuat
+}
+
+// SupplierProduct adalah produk yang bisa dibeli dari supplier, dengan harga beli dan lead time-nya
+type SupplierProduct struct {
+	ID           int      `gorm:"primaryKey"`
+	SupplierID   int      `gorm:"not null;uniqueIndex:idx_supplier_product"`
+	ProductID    int      `gorm:"not null;uniqueIndex:idx_supplier_product;index"`
+	SupplierSKU  string   // Kode produk di katalog supplier
+	CostMinor    int64    `gorm:"not null"` // Harga beli per unit dalam minor unit Currency
+	Currency     string   `gorm:"size:3;not null"`
+	Cost         float64  `gorm:"-"`
+	LeadTimeDays int      `gorm:"not null;default:0"` // Perkiraan hari dari purchase order dikirim sampai barang diterima
+	Product      *Product `json:"Product,omitempty"`
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+// AfterFind menghitung Cost dari CostMinor
+func (p *SupplierProduct) AfterFind(tx *gorm.DB) error {
+	p.Cost = FromMinor(p.CostMinor, p.Currency)
+	return nil
+}
+
+// SupplierProductInput adalah payload untuk menghubungkan produk ke supplier
+type SupplierProductInput struct {
+	SupplierSKU  string  `json:"supplier_sku" binding:"max=64"`
+	Cost         float64 `json:"cost" binding:"gte=0,lte=1000000000"` // Dalam mata uang supplier
+	LeadTimeDays int     `json:"lead_time_days" binding:"gte=0,lte=365"`
+}
@@ -0,0 +1,19 @@
+package models
+
+// Tag adalah label bebas untuk produk. Name disimpan dalam bentuk ternormalisasi (huruf kecil, spasi menjadi "-").
+type Tag struct {
+	ID   int    `gorm:"primaryKey"`
+	Name string `gorm:"unique;not null"`
+}
+
+// TagUsage adalah tag beserta jumlah produk yang memakainya
+type TagUsage struct {
+	ID           int
+	Name         string
+	ProductCount int
+}
+
+// TagsInput adalah payload untuk menambah atau melepas tag dari produk
+type TagsInput struct {
+	Tags []string `json:"tags" binding:"required,min=1,max=20,dive,notblank,max=50"`
+}
@@ -0,0 +1,46 @@
+package models
+
+import (
+	"regexp"
+	"time"
+)
+
+// DefaultTaxRegion dipakai jika request tidak menyebut region pajak
+const DefaultTaxRegion = "ID"
+
+// ValidTaxRegion mencocokkan kode negara ISO 3166-1 alpha-2, opsional dengan subdivisi ISO 3166-2 (misalnya ID-BA)
+var ValidTaxRegion = regexp.MustCompile(`^[A-Z]{2}(-[A-Z0-9]{1,3})?$`)
+
+// TaxClass mengelompokkan produk yang dikenai tarif pajak yang sama, misalnya standard atau exempt
+type TaxClass struct {
+	ID        int       `gorm:"primaryKey"`
+	Code      string    `gorm:"size:50;not null;uniqueIndex"`
+	Name      string    `gorm:"not null"`
+	Rates     []TaxRate `json:"Rates,omitempty"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// TaxClassInput adalah payload untuk membuat atau memperbarui tax class
+type TaxClassInput struct {
+	Code string `json:"code" binding:"required,notblank,max=50"`
+	Name string `json:"name" binding:"required,notblank,max=100"`
+}
+
+// TaxRate adalah tarif pajak tax class di satu region yang berlaku mulai EffectiveFrom
+// sampai ada tarif lain untuk class dan region yang sama dengan EffectiveFrom lebih baru
+type TaxRate struct {
+	ID            int       `gorm:"primaryKey"`
+	TaxClassID    int       `gorm:"not null;uniqueIndex:idx_tax_rate"`
+	Region        string    `gorm:"size:10;not null;uniqueIndex:idx_tax_rate"` // Region tanpa subdivisi berlaku juga untuk semua subdivisinya
+	Rate          float64   `gorm:"not null"`                                  // Persen, maksimal 2 desimal
+	EffectiveFrom time.Time `gorm:"not null;uniqueIndex:idx_tax_rate"`
+	CreatedAt     time.Time
+}
+
+// TaxRateInput adalah payload untuk menambah tarif pajak
+type TaxRateInput struct {
+	Region        string     `json:"region" binding:"required,max=10"`
+	Rate          *float64   `json:"rate" binding:"required,gte=0,lte=100"`
+	EffectiveFrom *time.Time `json:"effective_from" binding:"required"`
+}
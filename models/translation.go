@@ -0,0 +1,35 @@
+package models
+
+import "time"
+
+// DefaultLocale adalah bahasa NamaProduk dan Deskripsi yang disimpan langsung di tabel products
+const DefaultLocale = "id"
+
+// SupportedLocales adalah bahasa yang bisa diminta lewat Accept-Language atau ?lang=
+var SupportedLocales = []string{"id", "en"}
+
+// ProductTranslation adalah terjemahan nama dan deskripsi produk untuk satu locale selain DefaultLocale.
+// Field yang kosong jatuh kembali ke isi produk dalam DefaultLocale.
+type ProductTranslation struct {
+	ID         int    `gorm:"primaryKey"`
+	ProductID  int    `gorm:"not null;uniqueIndex:idx_product_locale"`
+	Locale     string `gorm:"size:10;not null;uniqueIndex:idx_product_locale"`
+	NamaProduk string
+	Deskripsi  string
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+// ProductTranslationInput adalah payload untuk menyimpan terjemahan produk
+type ProductTranslationInput struct {
+	NamaProduk string `json:"nama_produk" binding:"required,notblank,nocontrol,max=100"`
+	Deskripsi  string `json:"deskripsi" binding:"max=1000"`
+}
+
+// MissingTranslation adalah satu baris laporan terjemahan yang belum lengkap
+type MissingTranslation struct {
+	ProductID     int
+	NamaProduk    string
+	Locale        string
+	MissingFields []string // nama_produk dan/atau deskripsi
+}
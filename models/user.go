@@ -1,10 +1,11 @@
 package models
 
 type User struct {
-	ID         int    `gorm:"primaryKey"`
-	Username   string `gorm:"unique;not null"`
-	Password   string `gorm:"not null"`
-	Role       string `gorm:"not null"`
-	Department string `gorm:"not null"`
-	Active     bool
+	ID            int    `gorm:"primaryKey"`
+	Username      string `gorm:"unique;not null"`
+	Password      string `gorm:"not null" json:"-"`
+	Role          string `gorm:"not null"`
+	Department    string `gorm:"not null"`
+	CustomerGroup string `gorm:"size:50;index"` // Menentukan price list user, misalnya wholesale
+	Active        bool
 }
@@ -0,0 +1,20 @@
+package models
+
+// Kode error validasi yang bisa dibaca mesin
+const (
+	ValidationRequired     = "required"
+	ValidationTooShort     = "too_short"
+	ValidationTooLong      = "too_long"
+	ValidationOutOfRange   = "out_of_range"
+	ValidationInvalidType  = "invalid_type"
+	ValidationInvalidValue = "invalid_value"
+	ValidationNotUnique    = "not_unique"
+	ValidationMalformed    = "malformed_body"
+)
+
+// FieldError menjelaskan satu field yang gagal validasi
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
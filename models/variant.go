@@ -0,0 +1,56 @@
+package models
+
+import "time"
+
+// ProductOption adalah dimensi variasi produk (misalnya size atau color) beserta nilai yang diizinkan
+type ProductOption struct {
+	ID        int      `gorm:"primaryKey"`
+	ProductID int      `gorm:"not null;index"`
+	Name      string   `gorm:"not null"`
+	Values    []string `gorm:"type:text;serializer:json;not null"`
+	Position  int      `gorm:"not null;default:0"`
+}
+
+// ProductVariant adalah satu kombinasi option produk dengan SKU, harga dan stok sendiri.
+// PriceOverrideMinor dalam mata uang produk, kosong berarti variant memakai harga produk.
+type ProductVariant struct {
+	ID                 int    `gorm:"primaryKey"`
+	ProductID          int    `gorm:"not null;index"`
+	SKU                string `gorm:"uniqueIndex;not null"`
+	PriceOverrideMinor *int64
+	Stok               int               `gorm:"not null;default:0"` // Total stok variant di semua warehouse, hanya berubah lewat ledger
+	Barcode            *string           `gorm:"uniqueIndex"`
+	Options            map[string]string `gorm:"type:text;serializer:json;not null"`
+	CreatedAt          time.Time
+	UpdatedAt          time.Time
+}
+
+// ProductOptionInput adalah satu definisi option pada ProductOptionsInput
+type ProductOptionInput struct {
+	Name   string   `json:"name" binding:"required,notblank,max=50"`
+	Values []string `json:"values" binding:"required,min=1,dive,notblank,max=50"`
+}
+
+// ProductOptionsInput menggantikan seluruh definisi option sebuah produk
+type ProductOptionsInput struct {
+	Options []ProductOptionInput `json:"options" binding:"max=10,dive"`
+}
+
+// CreateVariantInput adalah payload untuk membuat variant. Options memetakan nama option ke nilainya.
+type CreateVariantInput struct {
+	SKU           string            `json:"sku" binding:"required,notblank,max=64"`
+	PriceOverride *float64          `json:"price_override" binding:"omitempty,gt=0"`
+	Stok          int               `json:"stok" binding:"gte=0,lte=1000000"` // Dicatat sebagai receipt awal di ledger
+	Barcode       string            `json:"barcode" binding:"omitempty,barcode"`
+	Options       map[string]string `json:"options"`
+}
+
+// UpdateVariantInput adalah payload untuk memperbarui variant. Field yang kosong tidak diubah,
+// dan price_override 0 menghapus harga khusus variant. Stok tidak bisa diubah di sini, gunakan
+// endpoint stock adjustment dengan variant_id agar tercatat di ledger.
+type UpdateVariantInput struct {
+	SKU           *string            `json:"sku" binding:"omitempty,notblank,max=64"`
+	PriceOverride *float64           `json:"price_override" binding:"omitempty,gte=0"`
+	Barcode       *string            `json:"barcode" binding:"omitempty,barcode"` // String kosong menghapus barcode
+	Options       *map[string]string `json:"options"`
+}
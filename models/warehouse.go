@@ -0,0 +1,41 @@
+package models
+
+import "time"
+
+// Warehouse adalah lokasi fisik tempat stok produk disimpan
+type Warehouse struct {
+	ID        int    `gorm:"primaryKey"`
+	Code      string `gorm:"unique;not null"`
+	Name      string `gorm:"not null"`
+	Address   string
+	IsDefault bool // Stok masuk tanpa warehouse_id dicatat ke warehouse default
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// WarehouseStock adalah jumlah stok sebuah produk, atau satu variant-nya, di satu warehouse
+type WarehouseStock struct {
+	ID          int        `gorm:"primaryKey"`
+	WarehouseID int        `gorm:"not null;uniqueIndex:idx_warehouse_product_stock,where:variant_id IS NULL;uniqueIndex:idx_warehouse_variant_stock"`
+	ProductID   int        `gorm:"not null;uniqueIndex:idx_warehouse_product_stock,where:variant_id IS NULL;index"`
+	VariantID   *int       `gorm:"uniqueIndex:idx_warehouse_variant_stock" json:"VariantID,omitempty"` // Kosong berarti stok produk itu sendiri
+	Quantity    int        `gorm:"not null;default:0"`
+	Warehouse   *Warehouse `json:"Warehouse,omitempty"`
+}
+
+// WarehouseInput adalah payload untuk membuat atau memperbarui warehouse
+type WarehouseInput struct {
+	Code      string `json:"code" binding:"required,notblank,max=20"`
+	Name      string `json:"name" binding:"required,notblank,max=100"`
+	Address   string `json:"address" binding:"max=255"`
+	IsDefault bool   `json:"is_default"`
+}
+
+// StockTransferInput adalah payload untuk memindahkan stok antar warehouse
+type StockTransferInput struct {
+	FromWarehouseID int    `json:"from_warehouse_id" binding:"required"`
+	ToWarehouseID   int    `json:"to_warehouse_id" binding:"required,nefield=FromWarehouseID"`
+	Quantity        int    `json:"quantity" binding:"required,gte=1,lte=1000000"`
+	Reason          string `json:"reason" binding:"max=255"`
+	Reference       string `json:"reference" binding:"max=100"`
+}
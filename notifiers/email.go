@@ -0,0 +1,65 @@
+package notifiers
+
+import (
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"products-api-with-jwt/models"
+	"strings"
+	"unicode"
+)
+
+// EmailNotifier mengirim event low-stock lewat SMTP
+type EmailNotifier struct {
+	Addr     string // host:port server SMTP
+	From     string
+	To       []string
+	Username string
+	Password string
+}
+
+// NewEmailNotifier menginisialisasi EmailNotifier baru. to berisi daftar alamat dipisah koma.
+func NewEmailNotifier(addr, from, to, username, password string) *EmailNotifier {
+	var recipients []string
+	for _, address := range strings.Split(to, ",") {
+		if address = strings.TrimSpace(address); address != "" {
+			recipients = append(recipients, address)
+		}
+	}
+	return &EmailNotifier{Addr: addr, From: from, To: recipients, Username: username, Password: password}
+}
+
+func (n *EmailNotifier) Name() string {
+	return "email"
+}
+
+func (n *EmailNotifier) NotifyLowStock(event models.LowStockEvent) error {
+	// Nama produk berasal dari user, jadi karakter kontrol dibuang agar tidak bisa menambah header
+	name := stripControl(event.NamaProduk)
+	subject := mime.QEncoding.Encode("utf-8", "Low stock: "+name)
+	body := fmt.Sprintf("Product %d (%s) has %d units left, at or below its reorder point of %d.\r\n",
+		event.ProductID, name, event.Stok, event.ReorderPoint)
+	message := fmt.Sprintf("From: %s\r\nTo: %s\r\nSubject: %s\r\n\r\n%s",
+		n.From, strings.Join(n.To, ", "), subject, body)
+
+	var auth smtp.Auth
+	if n.Username != "" {
+		host, _, err := net.SplitHostPort(n.Addr)
+		if err != nil {
+			return err
+		}
+		auth = smtp.PlainAuth("", n.Username, n.Password, host)
+	}
+	return smtp.SendMail(n.Addr, auth, n.From, n.To, []byte(message))
+}
+
+// stripControl mengganti karakter kontrol (termasuk CR dan LF) dengan spasi
+func stripControl(value string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return ' '
+		}
+		return r
+	}, value)
+}
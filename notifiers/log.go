@@ -0,0 +1,23 @@
+package notifiers
+
+import (
+	"log"
+	"products-api-with-jwt/models"
+)
+
+// LogNotifier menulis event low-stock ke log aplikasi
+type LogNotifier struct{}
+
+// NewLogNotifier menginisialisasi LogNotifier baru
+func NewLogNotifier() *LogNotifier {
+	return &LogNotifier{}
+}
+
+func (n *LogNotifier) Name() string {
+	return "log"
+}
+
+func (n *LogNotifier) NotifyLowStock(event models.LowStockEvent) error {
+	log.Printf("Low stock: product %d (%s) has %d left, reorder point %d", event.ProductID, event.NamaProduk, event.Stok, event.ReorderPoint)
+	return nil
+}
@@ -0,0 +1,9 @@
+package notifiers
+
+import "products-api-with-jwt/models"
+
+// LowStockNotifier menerima event low-stock. Implementasi tidak boleh mengubah event.
+type LowStockNotifier interface {
+	Name() string
+	NotifyLowStock(event models.LowStockEvent) error
+}
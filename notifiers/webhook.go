@@ -0,0 +1,46 @@
+package notifiers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"products-api-with-jwt/models"
+	"time"
+)
+
+// WebhookNotifier mengirim event low-stock sebagai JSON POST ke URL yang dikonfigurasi
+type WebhookNotifier struct {
+	URL    string
+	Client *http.Client
+}
+
+// NewWebhookNotifier menginisialisasi WebhookNotifier baru
+func NewWebhookNotifier(url string) *WebhookNotifier {
+	return &WebhookNotifier{URL: url, Client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+func (n *WebhookNotifier) Name() string {
+	return "webhook"
+}
+
+func (n *WebhookNotifier) NotifyLowStock(event models.LowStockEvent) error {
+	body, err := json.Marshal(map[string]interface{}{
+		"event": "product.low_stock",
+		"data":  event,
+	})
+	if err != nil {
+		return err
+	}
+
+	resp, err := n.Client.Post(n.URL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,333 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"products-api-with-jwt/models"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrAttributeNotFound dikembalikan jika kategori tidak punya schema attribute dengan kode yang diminta
+	ErrAttributeNotFound = errors.New("attribute not found")
+	// ErrAttributeConflict dikembalikan jika kode attribute sudah dipakai dengan tipe lain di induk atau turunan kategori
+	ErrAttributeConflict = errors.New("attribute code is already defined with another type in a parent or sub-category")
+	// ErrInvalidAttributes dikembalikan jika attribute produk atau filter attribute tidak sesuai schema
+	ErrInvalidAttributes = errors.New("attributes do not match the category attribute schemas")
+)
+
+// AttributeValidationError berisi attribute yang tidak sesuai schema, satu FieldError per attribute
+type AttributeValidationError struct {
+	Fields []models.FieldError
+}
+
+func (e *AttributeValidationError) Error() string {
+	names := make([]string, len(e.Fields))
+	for i, field := range e.Fields {
+		names[i] = field.Field
+	}
+	return ErrInvalidAttributes.Error() + ": " + strings.Join(names, ", ")
+}
+
+func (e *AttributeValidationError) Unwrap() error {
+	return ErrInvalidAttributes
+}
+
+type AttributeService struct {
+	DB *gorm.DB
+}
+
+// NewAttributeService menginisialisasi AttributeService baru
+func NewAttributeService(db *gorm.DB) *AttributeService {
+	return &AttributeService{DB: db}
+}
+
+// GetCategoryAttributes mengambil schema attribute yang berlaku di kategori, termasuk yang diwarisi dari induknya
+func (s *AttributeService) GetCategoryAttributes(categoryID int) ([]models.AttributeDefinition, error) {
+	if _, err := findCategories(s.DB, []int{categoryID}); err != nil {
+		return nil, err
+	}
+	return productAttributeDefinitions(s.DB, []int{categoryID})
+}
+
+// SetCategoryAttribute membuat atau mengganti schema attribute di kategori.
+// Nilai yang sudah tersimpan di produk divalidasi ulang saat produk itu diperbarui.
+func (s *AttributeService) SetCategoryAttribute(categoryID int, code string, input *models.AttributeDefinitionInput) (*models.AttributeDefinition, error) {
+	var definition models.AttributeDefinition
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		if _, err := findCategories(tx, []int{categoryID}); err != nil {
+			return err
+		}
+
+		// Kode yang sama di induk atau turunan harus bertipe sama, agar produk di sub-kategori bisa memenuhi keduanya
+		related, err := categoryAncestorIDs(tx, []int{categoryID})
+		if err != nil {
+			return err
+		}
+		descendants, err := categoryDescendantIDs(tx, categoryID)
+		if err != nil {
+			return err
+		}
+		related = append(related, descendants...)
+		var conflicts int64
+		err = tx.Model(&models.AttributeDefinition{}).
+			Where("category_id IN ? AND category_id <> ? AND code = ? AND type <> ?", related, categoryID, code, input.Type).
+			Count(&conflicts).Error
+		if err != nil {
+			return err
+		}
+		if conflicts > 0 {
+			return ErrAttributeConflict
+		}
+
+		err = tx.Where("category_id = ? AND code = ?", categoryID, code).First(&definition).Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		definition.CategoryID = categoryID
+		definition.Code = code
+		definition.Name = strings.TrimSpace(input.Name)
+		definition.Type = input.Type
+		definition.Required = input.Required
+		definition.EnumValues = nil
+		if input.Type == models.AttributeEnum {
+			definition.EnumValues = uniqueStrings(input.EnumValues)
+		}
+		definition.Unit = strings.TrimSpace(input.Unit)
+		return tx.Save(&definition).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &definition, nil
+}
+
+// DeleteCategoryAttribute menghapus schema attribute dari kategori
+func (s *AttributeService) DeleteCategoryAttribute(categoryID int, code string) error {
+	if _, err := findCategories(s.DB, []int{categoryID}); err != nil {
+		return err
+	}
+	result := s.DB.Where("category_id = ? AND code = ?", categoryID, code).Delete(&models.AttributeDefinition{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrAttributeNotFound
+	}
+	return nil
+}
+
+// productAttributeDefinitions mengambil schema attribute dari kategori-kategori beserta semua induknya
+func productAttributeDefinitions(db *gorm.DB, categoryIDs []int) ([]models.AttributeDefinition, error) {
+	definitions := []models.AttributeDefinition{}
+	if len(categoryIDs) == 0 {
+		return definitions, nil
+	}
+	ids, err := categoryAncestorIDs(db, categoryIDs)
+	if err != nil {
+		return nil, err
+	}
+	if err := db.Where("category_id IN ?", ids).Order("code, category_id").Find(&definitions).Error; err != nil {
+		return nil, err
+	}
+	return definitions, nil
+}
+
+// validateAttributes memeriksa nilai attribute produk terhadap schema yang berlaku dan mengembalikan nilai
+// yang sudah dinormalisasi. Jika satu kode didefinisikan di beberapa kategori, nilainya harus memenuhi semuanya.
+func validateAttributes(definitions []models.AttributeDefinition, values map[string]any) (map[string]any, error) {
+	byCode := make(map[string][]models.AttributeDefinition)
+	for _, definition := range definitions {
+		byCode[definition.Code] = append(byCode[definition.Code], definition)
+	}
+	codes := make([]string, 0, len(byCode)+len(values))
+	for code := range byCode {
+		codes = append(codes, code)
+	}
+	for code := range values {
+		if _, ok := byCode[code]; !ok {
+			codes = append(codes, code)
+		}
+	}
+	sort.Strings(codes)
+
+	var fields []models.FieldError
+	normalized := make(map[string]any, len(values))
+	for _, code := range codes {
+		field := "attributes." + code
+		defs, defined := byCode[code]
+		value, present := values[code]
+		switch {
+		case !defined:
+			fields = append(fields, models.FieldError{Field: field, Code: models.ValidationInvalidValue, Message: "is not defined for the product's categories"})
+		case !present || value == nil:
+			if slices.ContainsFunc(defs, func(d models.AttributeDefinition) bool { return d.Required }) {
+				fields = append(fields, models.FieldError{Field: field, Code: models.ValidationRequired, Message: "is required"})
+			}
+		default:
+			var fieldErr *models.FieldError
+			for _, definition := range defs {
+				if value, fieldErr = normalizeAttribute(definition, value); fieldErr != nil {
+					fieldErr.Field = field
+					fields = append(fields, *fieldErr)
+					break
+				}
+			}
+			if fieldErr == nil {
+				normalized[code] = value
+			}
+		}
+	}
+	if len(fields) > 0 {
+		return nil, &AttributeValidationError{Fields: fields}
+	}
+	if len(normalized) == 0 {
+		return nil, nil
+	}
+	return normalized, nil
+}
+
+// normalizeAttribute memeriksa satu nilai terhadap schema dan mengembalikannya dalam bentuk yang disimpan
+func normalizeAttribute(definition models.AttributeDefinition, value any) (any, *models.FieldError) {
+	invalidType := &models.FieldError{Code: models.ValidationInvalidType, Message: "must be of type " + definition.Type}
+	switch definition.Type {
+	case models.AttributeString:
+		text, ok := value.(string)
+		if !ok {
+			return nil, invalidType
+		}
+		text = strings.TrimSpace(text)
+		if text == "" {
+			return nil, &models.FieldError{Code: models.ValidationRequired, Message: "is required"}
+		}
+		if len(text) > 255 {
+			return nil, &models.FieldError{Code: models.ValidationTooLong, Message: "must be at most 255 characters"}
+		}
+		return text, nil
+	case models.AttributeNumber:
+		number, ok := value.(float64)
+		if !ok {
+			return nil, invalidType
+		}
+		return number, nil
+	case models.AttributeInteger:
+		number, ok := value.(float64)
+		if !ok || number != math.Trunc(number) {
+			return nil, invalidType
+		}
+		return int64(number), nil
+	case models.AttributeBoolean:
+		flag, ok := value.(bool)
+		if !ok {
+			return nil, invalidType
+		}
+		return flag, nil
+	case models.AttributeEnum:
+		text, ok := value.(string)
+		if !ok || !slices.Contains(definition.EnumValues, text) {
+			return nil, &models.FieldError{Code: models.ValidationInvalidValue, Message: "must be one of: " + strings.Join(definition.EnumValues, " ")}
+		}
+		return text, nil
+	default:
+		return nil, invalidType
+	}
+}
+
+// withAttributeFilters menambahkan filter ?attr.<code>= (salah satu dari nilai yang dipisah koma) serta
+// ?attr.<code>.min= dan ?attr.<code>.max= untuk attribute bertipe angka
+func withAttributeFilters(db *gorm.DB, schemaDB *gorm.DB, filters map[string]string) (*gorm.DB, error) {
+	keys := make([]string, 0, len(filters))
+	for key := range filters {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var fields []models.FieldError
+	for _, key := range keys {
+		field := "attr." + key
+		code, bound, _ := strings.Cut(key, ".")
+		if !models.ValidAttributeCode(code) || (bound != "" && bound != "min" && bound != "max") {
+			fields = append(fields, models.FieldError{Field: field, Code: models.ValidationInvalidValue, Message: "must be attr.<code>, attr.<code>.min or attr.<code>.max"})
+			continue
+		}
+
+		var types []string
+		if err := schemaDB.Model(&models.AttributeDefinition{}).Where("code = ?", code).Distinct().Order("type").Pluck("type", &types).Error; err != nil {
+			return nil, err
+		}
+		if len(types) == 0 {
+			fields = append(fields, models.FieldError{Field: field, Code: models.ValidationInvalidValue, Message: "is not a defined attribute"})
+			continue
+		}
+		attributeType := types[0]
+		numeric := attributeType == models.AttributeNumber || attributeType == models.AttributeInteger
+		path := "$." + code
+
+		if bound != "" {
+			limit, err := strconv.ParseFloat(filters[key], 64)
+			if !numeric || err != nil {
+				fields = append(fields, models.FieldError{Field: field, Code: models.ValidationInvalidValue, Message: "must be a number and can only be used on number or integer attributes"})
+				continue
+			}
+			operator := ">="
+			if bound == "max" {
+				operator = "<="
+			}
+			db = db.Where(fmt.Sprintf("json_extract(attributes, ?) %s ?", operator), path, limit)
+			continue
+		}
+
+		var values []any
+		for _, raw := range strings.Split(filters[key], ",") {
+			raw = strings.TrimSpace(raw)
+			switch {
+			case numeric:
+				number, err := strconv.ParseFloat(raw, 64)
+				if err != nil {
+					fields = append(fields, models.FieldError{Field: field, Code: models.ValidationInvalidType, Message: "must be a number"})
+					continue
+				}
+				values = append(values, number)
+			case attributeType == models.AttributeBoolean:
+				flag, err := strconv.ParseBool(raw)
+				if err != nil {
+					fields = append(fields, models.FieldError{Field: field, Code: models.ValidationInvalidType, Message: "must be true or false"})
+					continue
+				}
+				// json_extract mengembalikan boolean JSON sebagai 1 atau 0
+				if flag {
+					values = append(values, 1)
+				} else {
+					values = append(values, 0)
+				}
+			default:
+				values = append(values, raw)
+			}
+		}
+		if len(values) > 0 {
+			db = db.Where("json_extract(attributes, ?) IN ?", path, values)
+		}
+	}
+	if len(fields) > 0 {
+		return nil, &AttributeValidationError{Fields: fields}
+	}
+	return db, nil
+}
+
+// uniqueStrings membuang string kosong dan duplikat tanpa mengubah urutan
+func uniqueStrings(values []string) []string {
+	result := make([]string, 0, len(values))
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if value != "" && !slices.Contains(result, value) {
+			result = append(result, value)
+		}
+	}
+	return result
+}
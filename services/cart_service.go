@@ -0,0 +1,379 @@
+package services
+
+import (
+	"errors"
+	"log"
+	"products-api-with-jwt/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrCartEmpty dikembalikan jika checkout dilakukan dengan cart kosong
+	ErrCartEmpty = errors.New("cart is empty")
+	// ErrCartItemNotFound dikembalikan jika produk tidak ada di cart user
+	ErrCartItemNotFound = errors.New("product is not in the cart")
+)
+
+type CartService struct {
+	DB           *gorm.DB
+	OrderService *OrderService
+	TTL          time.Duration
+}
+
+// NewCartService menginisialisasi CartService baru. Cart yang tidak berubah selama ttl dikosongkan.
+func NewCartService(db *gorm.DB, orderService *OrderService, ttl time.Duration) *CartService {
+	return &CartService{DB: db, OrderService: orderService, TTL: ttl}
+}
+
+// GetCart mengambil cart user dengan harga dan stok terbaru. User tanpa cart mendapat cart kosong.
+func (s *CartService) GetCart(userID int) (*models.Cart, error) {
+	cart, err := findCart(s.DB, userID)
+	if err != nil {
+		return nil, err
+	}
+	if cart == nil {
+		cart = &models.Cart{UserID: userID, Items: []models.CartItem{}}
+	}
+	if err := revalidateCart(s.DB, cart); err != nil {
+		return nil, err
+	}
+	return cart, nil
+}
+
+// AddItem menambahkan produk ke cart, atau menambah quantity-nya jika produk sudah ada di cart
+func (s *CartService) AddItem(userID int, input *models.CartItemInput) (*models.Cart, error) {
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		cart, err := s.ensureCart(tx, userID)
+		if err != nil {
+			return err
+		}
+		product, err := cartProduct(tx, input.ProductID)
+		if err != nil {
+			return err
+		}
+
+		// Semua item di cart harus bisa di-checkout menjadi satu order dengan satu mata uang
+		var mismatched int64
+		err = tx.Model(&models.Product{}).
+			Where("id IN (?) AND currency <> ?", tx.Model(&models.CartItem{}).Select("product_id").Where("cart_id = ?", cart.ID), product.Currency).
+			Count(&mismatched).Error
+		if err != nil {
+			return err
+		}
+		if mismatched > 0 {
+			return ErrOrderCurrencyMismatch
+		}
+
+		var item models.CartItem
+		err = tx.Where("cart_id = ? AND product_id = ?", cart.ID, product.ID).First(&item).Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		quantity := item.Quantity + input.Quantity
+		if quantity > product.Available {
+			return ErrInsufficientStock
+		}
+
+		price, err := cartUnitPrice(tx, userID, product.ID, quantity)
+		if err != nil {
+			return err
+		}
+		if item.ID == 0 {
+			item = models.CartItem{CartID: cart.ID, ProductID: product.ID, Quantity: quantity, AddedPriceMinor: price}
+			return tx.Create(&item).Error
+		}
+		return tx.Model(&item).Updates(map[string]interface{}{"quantity": quantity, "added_price_minor": price}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetCart(userID)
+}
+
+// UpdateItem mengganti quantity produk di cart
+func (s *CartService) UpdateItem(userID, productID int, input *models.CartQuantityInput) (*models.Cart, error) {
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		item, err := findCartItem(tx, userID, productID)
+		if err != nil {
+			return err
+		}
+		product, err := cartProduct(tx, productID)
+		if err != nil {
+			return err
+		}
+		if input.Quantity > product.Available {
+			return ErrInsufficientStock
+		}
+		price, err := cartUnitPrice(tx, userID, productID, input.Quantity)
+		if err != nil {
+			return err
+		}
+		if err := tx.Model(item).Updates(map[string]interface{}{"quantity": input.Quantity, "added_price_minor": price}).Error; err != nil {
+			return err
+		}
+		return s.touchCart(tx, item.CartID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetCart(userID)
+}
+
+// RemoveItem menghapus produk dari cart
+func (s *CartService) RemoveItem(userID, productID int) (*models.Cart, error) {
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		item, err := findCartItem(tx, userID, productID)
+		if err != nil {
+			return err
+		}
+		if err := tx.Delete(item).Error; err != nil {
+			return err
+		}
+		return s.touchCart(tx, item.CartID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetCart(userID)
+}
+
+// ClearCart mengosongkan cart user
+func (s *CartService) ClearCart(userID int) error {
+	return s.DB.Transaction(func(tx *gorm.DB) error {
+		return deleteCarts(tx, tx.Model(&models.Cart{}).Select("id").Where("user_id = ?", userID))
+	})
+}
+
+// Checkout mengubah isi cart menjadi order pending lewat OrderService dan mengosongkan cart
+// di transaksi yang sama. Harga dan stok divalidasi ulang saat order dibuat.
+func (s *CartService) Checkout(userID int, input *models.CheckoutInput) (*models.Order, error) {
+	var order *models.Order
+	var movements []models.StockMovement
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		cart, err := findCart(tx, userID)
+		if err != nil {
+			return err
+		}
+		if cart == nil || len(cart.Items) == 0 {
+			return ErrCartEmpty
+		}
+
+		lines := make([]models.OrderLineInput, len(cart.Items))
+		for i, item := range cart.Items {
+			lines[i] = models.OrderLineInput{ProductID: item.ProductID, Quantity: item.Quantity}
+		}
+		if order, movements, err = s.OrderService.placeOrder(tx, userID, input.Region, input.Notes, lines); err != nil {
+			return err
+		}
+		return deleteCarts(tx, []int{cart.ID})
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.OrderService.ProductService.evaluateLowStock(movedProductIDs(movements)...)
+	return s.OrderService.GetOrderByID(order.ID, 0)
+}
+
+// ExpireCarts menghapus cart yang sudah tidak berubah lebih lama dari TTL
+func (s *CartService) ExpireCarts() (int, error) {
+	var ids []int
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.Cart{}).Where("expires_at <= ?", time.Now()).Pluck("id", &ids).Error; err != nil {
+			return err
+		}
+		if len(ids) == 0 {
+			return nil
+		}
+		return deleteCarts(tx, ids)
+	})
+	if err != nil {
+		return 0, err
+	}
+	return len(ids), nil
+}
+
+// StartCartExpirer menjalankan ExpireCarts secara berkala di background
+func (s *CartService) StartCartExpirer(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			expired, err := s.ExpireCarts()
+			if err != nil {
+				log.Printf("Could not expire carts: %v", err)
+				continue
+			}
+			if expired > 0 {
+				log.Printf("Expired %d carts", expired)
+			}
+		}
+	}()
+}
+
+// ensureCart mengambil cart user, membuatnya jika belum ada, dan memperpanjang masa berlakunya
+func (s *CartService) ensureCart(tx *gorm.DB, userID int) (*models.Cart, error) {
+	cart, err := findCart(tx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if cart == nil {
+		cart = &models.Cart{UserID: userID, ExpiresAt: time.Now().Add(s.TTL)}
+		return cart, tx.Create(cart).Error
+	}
+	return cart, s.touchCart(tx, cart.ID)
+}
+
+func (s *CartService) touchCart(tx *gorm.DB, cartID int) error {
+	return tx.Model(&models.Cart{}).Where("id = ?", cartID).Update("expires_at", time.Now().Add(s.TTL)).Error
+}
+
+// findCart mengambil cart user beserta itemnya, atau nil jika user belum punya cart.
+// Cart yang sudah kedaluwarsa dihapus di sini juga, tanpa menunggu ExpireCarts.
+func findCart(tx *gorm.DB, userID int) (*models.Cart, error) {
+	var cart models.Cart
+	err := tx.Preload("Items", func(db *gorm.DB) *gorm.DB {
+		return db.Order("id")
+	}).Where("user_id = ?", userID).First(&cart).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !cart.ExpiresAt.After(time.Now()) {
+		return nil, deleteCarts(tx, []int{cart.ID})
+	}
+	return &cart, nil
+}
+
+func findCartItem(tx *gorm.DB, userID, productID int) (*models.CartItem, error) {
+	cart, err := findCart(tx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if cart != nil {
+		for _, item := range cart.Items {
+			if item.ProductID == productID {
+				return &item, nil
+			}
+		}
+	}
+	return nil, ErrCartItemNotFound
+}
+
+// deleteCarts menghapus cart beserta itemnya. ids boleh berupa slice ID atau subquery.
+func deleteCarts(tx *gorm.DB, ids any) error {
+	if err := tx.Where("cart_id IN (?)", ids).Delete(&models.CartItem{}).Error; err != nil {
+		return err
+	}
+	return tx.Where("id IN (?)", ids).Delete(&models.Cart{}).Error
+}
+
+// cartProduct mengambil produk yang bisa dimasukkan ke cart beserta harga dan stok tersedianya
+func cartProduct(tx *gorm.DB, id int) (*models.Product, error) {
+	product, err := findProduct(tx, id)
+	if err != nil {
+		return nil, err
+	}
+	if product.Status != models.ProductPublished {
+		return nil, ErrProductNotAvailable
+	}
+	products := []models.Product{*product}
+	if err := applyEffectivePrices(tx, products); err != nil {
+		return nil, err
+	}
+	return &products[0], nil
+}
+
+// cartUnitPrice mengambil harga per unit yang dilihat user untuk quantity itu, termasuk tier price list-nya
+func cartUnitPrice(tx *gorm.DB, userID, productID, quantity int) (int64, error) {
+	priceList, err := userPriceList(tx, userID)
+	if err != nil {
+		return 0, err
+	}
+	quote, err := quotePrice(tx, productID, quantity, time.Now(), nil, models.DefaultTaxRegion, priceList)
+	if err != nil {
+		return 0, err
+	}
+	return quote.UnitPriceMinor, nil
+}
+
+// revalidateCart mengisi harga terbaru (termasuk price list user, promo dan pajak), stok tersedia dan masalah setiap item,
+// lalu menjumlahkan total item yang tidak bermasalah. Produk, price list dan promo dimuat sekali untuk seluruh cart.
+func revalidateCart(db *gorm.DB, cart *models.Cart) error {
+	ids := make([]int, len(cart.Items))
+	for i, item := range cart.Items {
+		ids[i] = item.ProductID
+	}
+	var products []models.Product
+	if len(ids) > 0 {
+		if err := db.Preload("Categories").Preload("Tags").Where("id IN ?", ids).Find(&products).Error; err != nil {
+			return err
+		}
+		if err := applyEffectivePrices(db, products); err != nil {
+			return err
+		}
+	}
+	productsByID := make(map[int]models.Product, len(products))
+	for _, product := range products {
+		productsByID[product.ID] = product
+	}
+
+	priceList, err := userPriceList(db, cart.UserID)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	promotions, err := activePromotions(db, now, nil)
+	if err != nil {
+		return err
+	}
+	cart.Valid = true
+	for i := range cart.Items {
+		item := &cart.Items[i]
+		product, ok := productsByID[item.ProductID]
+		if !ok || product.Status != models.ProductPublished {
+			item.Problem = models.CartItemUnavailable
+			cart.Valid = false
+			continue
+		}
+
+		quote, err := quoteProduct(db, product, item.Quantity, now, promotions, models.DefaultTaxRegion, priceList)
+		if err != nil {
+			return err
+		}
+		item.NamaProduk = product.NamaProduk
+		item.Currency = product.Currency
+		item.UnitPriceMinor = quote.UnitPriceMinor
+		item.ListPriceMinor = quote.ListPriceMinor
+		item.DiscountMinor = quote.DiscountMinor
+		item.TaxMinor = quote.TaxMinor
+		item.TotalMinor = quote.GrossMinor
+		item.UnitPrice = models.FromMinor(quote.UnitPriceMinor, product.Currency)
+		item.Total = quote.Gross
+		item.Available = product.Available
+		item.PriceChanged = quote.UnitPriceMinor != item.AddedPriceMinor
+
+		if cart.Currency == "" {
+			cart.Currency = product.Currency
+		}
+		switch {
+		case product.Currency != cart.Currency:
+			item.Problem = models.CartItemCurrencyMismatch
+		case item.Quantity > product.Available:
+			item.Problem = models.CartItemInsufficientStock
+		default:
+			cart.SubtotalMinor += quote.SubtotalMinor
+			cart.DiscountMinor += quote.DiscountMinor
+			cart.TaxMinor += quote.TaxMinor
+			cart.TotalMinor += quote.GrossMinor
+			continue
+		}
+		cart.Valid = false
+	}
+	cart.Total = models.FromMinor(cart.TotalMinor, cart.Currency)
+	return nil
+}
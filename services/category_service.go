@@ -0,0 +1,292 @@
+package services
+
+import (
+	"errors"
+	"products-api-with-jwt/models"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrCategoryNotFound dikembalikan jika kategori dengan ID yang diminta tidak ada
+	ErrCategoryNotFound = errors.New("category not found")
+	// ErrParentCategoryNotFound dikembalikan jika parent_id tidak merujuk ke kategori yang ada
+	ErrParentCategoryNotFound = errors.New("parent category not found")
+	// ErrDuplicateCategoryName dikembalikan jika nama kategori sudah dipakai di parent yang sama
+	ErrDuplicateCategoryName = errors.New("category name already exists under this parent")
+	// ErrCategoryCycle dikembalikan jika parent baru adalah kategori itu sendiri atau turunannya
+	ErrCategoryCycle = errors.New("category cannot be moved under itself or its descendants")
+	// ErrCategoryInUse dikembalikan jika kategori yang akan dihapus masih dipakai produk
+	ErrCategoryInUse = errors.New("category still has products, pass reassign_to to move them")
+)
+
+type CategoryService struct {
+	DB *gorm.DB
+}
+
+// NewCategoryService menginisialisasi CategoryService baru
+func NewCategoryService(db *gorm.DB) *CategoryService {
+	return &CategoryService{DB: db}
+}
+
+// GetAllCategories mengambil semua kategori. Jika tree true, hasilnya berupa pohon mulai dari kategori root.
+func (s *CategoryService) GetAllCategories(tree bool) ([]models.Category, error) {
+	var categories []models.Category
+	if err := s.DB.Order("name").Find(&categories).Error; err != nil {
+		return nil, err
+	}
+	if !tree {
+		return categories, nil
+	}
+	return buildCategoryTree(categories, nil), nil
+}
+
+// GetCategoryByID mengambil kategori beserta seluruh sub-kategorinya
+func (s *CategoryService) GetCategoryByID(id int) (*models.Category, error) {
+	var category models.Category
+	if err := s.DB.First(&category, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrCategoryNotFound
+		}
+		return nil, err
+	}
+
+	var categories []models.Category
+	if err := s.DB.Order("name").Find(&categories).Error; err != nil {
+		return nil, err
+	}
+	category.Children = buildCategoryTree(categories, &category.ID)
+	return &category, nil
+}
+
+// CreateCategory menambah kategori baru
+func (s *CategoryService) CreateCategory(input *models.CategoryInput) (*models.Category, error) {
+	category := models.Category{
+		Name:     strings.TrimSpace(input.Name),
+		ParentID: input.ParentID,
+	}
+
+	if err := s.checkParent(category.ParentID, 0); err != nil {
+		return nil, err
+	}
+	if err := s.checkUniqueName(category.Name, category.ParentID, 0); err != nil {
+		return nil, err
+	}
+	if err := s.DB.Create(&category).Error; err != nil {
+		return nil, err
+	}
+	return &category, nil
+}
+
+// UpdateCategory mengganti nama dan/atau memindahkan kategori ke parent lain
+func (s *CategoryService) UpdateCategory(id int, input *models.CategoryInput) (*models.Category, error) {
+	var category models.Category
+	if err := s.DB.First(&category, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrCategoryNotFound
+		}
+		return nil, err
+	}
+
+	category.Name = strings.TrimSpace(input.Name)
+	category.ParentID = input.ParentID
+
+	if err := s.checkParent(category.ParentID, category.ID); err != nil {
+		return nil, err
+	}
+	if err := s.checkUniqueName(category.Name, category.ParentID, category.ID); err != nil {
+		return nil, err
+	}
+	if err := s.DB.Model(&category).Select("name", "parent_id").Updates(&category).Error; err != nil {
+		return nil, err
+	}
+	return &category, nil
+}
+
+// DeleteCategory menghapus kategori beserta schema attribute-nya. Sub-kategori dipindahkan ke parent kategori yang dihapus.
+// Jika masih ada produk, penghapusan ditolak kecuali reassignTo diisi dengan kategori tujuan.
+func (s *CategoryService) DeleteCategory(id int, reassignTo int) error {
+	var category models.Category
+	if err := s.DB.First(&category, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrCategoryNotFound
+		}
+		return err
+	}
+
+	return s.DB.Transaction(func(tx *gorm.DB) error {
+		var productIDs []int
+		if err := tx.Table("product_categories").Where("category_id = ?", id).Pluck("product_id", &productIDs).Error; err != nil {
+			return err
+		}
+
+		if len(productIDs) > 0 {
+			if reassignTo == 0 {
+				return ErrCategoryInUse
+			}
+			if reassignTo == id {
+				return ErrCategoryCycle
+			}
+			if err := tx.First(&models.Category{}, reassignTo).Error; err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					return ErrCategoryNotFound
+				}
+				return err
+			}
+
+			// Pindahkan produk yang belum ada di kategori tujuan, sisanya cukup dilepas
+			err := tx.Exec(`INSERT INTO product_categories (product_id, category_id)
+				SELECT product_id, ? FROM product_categories
+				WHERE category_id = ? AND product_id NOT IN (SELECT product_id FROM product_categories WHERE category_id = ?)`,
+				reassignTo, id, reassignTo).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Exec("DELETE FROM product_categories WHERE category_id = ?", id).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&models.Category{}).Where("parent_id = ?", id).Update("parent_id", category.ParentID).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("category_id = ?", id).Delete(&models.AttributeDefinition{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("category_id = ?", id).Delete(&models.PriceListRule{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.Category{}, id).Error
+	})
+}
+
+// checkParent memastikan parent ada dan tidak membentuk siklus
+func (s *CategoryService) checkParent(parentID *int, categoryID int) error {
+	if parentID == nil {
+		return nil
+	}
+	if err := s.DB.First(&models.Category{}, *parentID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrParentCategoryNotFound
+		}
+		return err
+	}
+	if categoryID == 0 {
+		return nil
+	}
+
+	descendants, err := categoryDescendantIDs(s.DB, categoryID)
+	if err != nil {
+		return err
+	}
+	for _, id := range descendants {
+		if id == *parentID {
+			return ErrCategoryCycle
+		}
+	}
+	return nil
+}
+
+// checkUniqueName memastikan nama kategori unik (case-insensitive) di bawah parent yang sama
+func (s *CategoryService) checkUniqueName(name string, parentID *int, excludeID int) error {
+	query := s.DB.Model(&models.Category{}).Where("LOWER(name) = LOWER(?) AND id <> ?", name, excludeID)
+	if parentID == nil {
+		query = query.Where("parent_id IS NULL")
+	} else {
+		query = query.Where("parent_id = ?", *parentID)
+	}
+
+	var count int64
+	if err := query.Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrDuplicateCategoryName
+	}
+	return nil
+}
+
+// findCategories memastikan semua ID kategori ada dan mengembalikan datanya
+func findCategories(tx *gorm.DB, ids []int) ([]models.Category, error) {
+	var categories []models.Category
+	if len(ids) == 0 {
+		return categories, nil
+	}
+	if err := tx.Where("id IN ?", ids).Find(&categories).Error; err != nil {
+		return nil, err
+	}
+
+	found := make(map[int]bool, len(categories))
+	for _, category := range categories {
+		found[category.ID] = true
+	}
+	for _, id := range ids {
+		if !found[id] {
+			return nil, ErrCategoryNotFound
+		}
+	}
+	return categories, nil
+}
+
+// categoryDescendantIDs mengembalikan ID kategori beserta semua turunannya
+func categoryDescendantIDs(db *gorm.DB, id int) ([]int, error) {
+	var categories []models.Category
+	if err := db.Select("id", "parent_id").Find(&categories).Error; err != nil {
+		return nil, err
+	}
+
+	children := make(map[int][]int)
+	for _, category := range categories {
+		if category.ParentID != nil {
+			children[*category.ParentID] = append(children[*category.ParentID], category.ID)
+		}
+	}
+
+	ids := []int{id}
+	for i := 0; i < len(ids); i++ {
+		ids = append(ids, children[ids[i]]...)
+	}
+	return ids, nil
+}
+
+// categoryAncestorIDs mengembalikan ID kategori beserta semua induknya sampai root
+func categoryAncestorIDs(db *gorm.DB, ids []int) ([]int, error) {
+	if len(ids) == 0 {
+		return ids, nil
+	}
+	var categories []models.Category
+	if err := db.Select("id", "parent_id").Find(&categories).Error; err != nil {
+		return nil, err
+	}
+
+	parents := make(map[int]int)
+	for _, category := range categories {
+		if category.ParentID != nil {
+			parents[category.ID] = *category.ParentID
+		}
+	}
+
+	seen := make(map[int]bool, len(ids))
+	result := make([]int, 0, len(ids))
+	for _, id := range ids {
+		for ok := true; ok && !seen[id]; id, ok = parents[id] {
+			seen[id] = true
+			result = append(result, id)
+		}
+	}
+	return result, nil
+}
+
+// buildCategoryTree menyusun daftar kategori datar menjadi pohon di bawah parentID
+func buildCategoryTree(categories []models.Category, parentID *int) []models.Category {
+	var nodes []models.Category
+	for _, category := range categories {
+		if (parentID == nil && category.ParentID == nil) ||
+			(parentID != nil && category.ParentID != nil && *category.ParentID == *parentID) {
+			category.Children = buildCategoryTree(categories, &category.ID)
+			nodes = append(nodes, category)
+		}
+	}
+	return nodes
+}
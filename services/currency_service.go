@@ -0,0 +1,235 @@
+package services
+
+import (
+	"errors"
+	"math/big"
+	"products-api-with-jwt/models"
+	"strconv"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	// ErrExchangeRateNotFound dikembalikan jika tidak ada rate, langsung maupun lewat DefaultCurrency, antara dua mata uang
+	ErrExchangeRateNotFound = errors.New("exchange rate not found")
+	// ErrCurrencyPriceNotFound dikembalikan jika produk tidak punya harga khusus untuk mata uang yang diminta
+	ErrCurrencyPriceNotFound = errors.New("currency price not found")
+	// ErrSameCurrency dikembalikan jika rate atau harga khusus memakai mata uang yang sama dengan asalnya
+	ErrSameCurrency = errors.New("currency must differ from the base currency")
+)
+
+type CurrencyService struct {
+	DB *gorm.DB
+}
+
+// NewCurrencyService menginisialisasi CurrencyService baru
+func NewCurrencyService(db *gorm.DB) *CurrencyService {
+	return &CurrencyService{DB: db}
+}
+
+// GetExchangeRates mengambil semua exchange rate
+func (s *CurrencyService) GetExchangeRates() ([]models.ExchangeRate, error) {
+	var rates []models.ExchangeRate
+	if err := s.DB.Order("from_currency, to_currency").Find(&rates).Error; err != nil {
+		return nil, err
+	}
+	return rates, nil
+}
+
+// SetExchangeRate menyimpan rate dari satu mata uang ke mata uang lain, menimpa rate yang sudah ada
+func (s *CurrencyService) SetExchangeRate(from, to string, input *models.ExchangeRateInput, userID int) (*models.ExchangeRate, error) {
+	if from == to {
+		return nil, ErrSameCurrency
+	}
+	rate := models.ExchangeRate{FromCurrency: from, ToCurrency: to, Rate: input.Rate, UserID: userID}
+	err := s.DB.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "from_currency"}, {Name: "to_currency"}},
+		DoUpdates: clause.AssignmentColumns([]string{"rate", "user_id", "updated_at"}),
+	}).Create(&rate).Error
+	if err != nil {
+		return nil, err
+	}
+	// ID dari upsert tidak bisa diandalkan saat baris sudah ada, baca ulang
+	if err := s.DB.Where("from_currency = ? AND to_currency = ?", from, to).First(&rate).Error; err != nil {
+		return nil, err
+	}
+	return &rate, nil
+}
+
+// DeleteExchangeRate menghapus rate dari satu mata uang ke mata uang lain
+func (s *CurrencyService) DeleteExchangeRate(from, to string) error {
+	result := s.DB.Where("from_currency = ? AND to_currency = ?", from, to).Delete(&models.ExchangeRate{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrExchangeRateNotFound
+	}
+	return nil
+}
+
+// GetCurrencyPrices mengambil semua harga khusus produk per mata uang
+func (s *CurrencyService) GetCurrencyPrices(productID int) ([]models.ProductCurrencyPrice, error) {
+	if _, err := findProduct(s.DB, productID); err != nil {
+		return nil, err
+	}
+	var prices []models.ProductCurrencyPrice
+	if err := s.DB.Where("product_id = ?", productID).Order("currency").Find(&prices).Error; err != nil {
+		return nil, err
+	}
+	return prices, nil
+}
+
+// SetCurrencyPrice menyimpan harga khusus produk untuk satu mata uang, menimpa harga yang sudah ada
+func (s *CurrencyService) SetCurrencyPrice(productID int, currency string, input *models.CurrencyPriceInput) (*models.ProductCurrencyPrice, error) {
+	var price models.ProductCurrencyPrice
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		product, err := findProduct(tx, productID)
+		if err != nil {
+			return err
+		}
+		if product.Currency == currency {
+			return ErrSameCurrency
+		}
+
+		err = tx.Where("product_id = ? AND currency = ?", productID, currency).First(&price).Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		price.ProductID = productID
+		price.Currency = currency
+		price.AmountMinor = models.ToMinor(input.Amount, currency)
+		price.Amount = models.FromMinor(price.AmountMinor, currency)
+		return tx.Save(&price).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &price, nil
+}
+
+// DeleteCurrencyPrice menghapus harga khusus produk sehingga harga kembali dikonversi dengan exchange rate
+func (s *CurrencyService) DeleteCurrencyPrice(productID int, currency string) error {
+	if _, err := findProduct(s.DB, productID); err != nil {
+		return err
+	}
+	result := s.DB.Where("product_id = ? AND currency = ?", productID, currency).Delete(&models.ProductCurrencyPrice{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrCurrencyPriceNotFound
+	}
+	return nil
+}
+
+// applyDisplayCurrency mengisi Price setiap produk dengan harga berlakunya dalam mata uang yang diminta.
+// Harga khusus produk untuk mata uang itu didahulukan, selain itu EffectivePriceMinor dikonversi dengan exchange rate.
+// Produk tanpa exchange rate tetap memakai mata uangnya sendiri dengan Source unconverted.
+// Dipanggil setelah applyUserPriceList. Harga khusus adalah harga katalog, jadi produk yang harganya dari price list
+// user selalu dikonversi dari harga price list tersebut.
+func applyDisplayCurrency(db *gorm.DB, products []models.Product, currency string) error {
+	if len(products) == 0 {
+		return nil
+	}
+	ids := make([]int, len(products))
+	for i, product := range products {
+		ids[i] = product.ID
+	}
+
+	var overrides []models.ProductCurrencyPrice
+	if err := db.Where("product_id IN ? AND currency = ?", ids, currency).Find(&overrides).Error; err != nil {
+		return err
+	}
+	overridePrices := make(map[int]int64, len(overrides))
+	for _, override := range overrides {
+		overridePrices[override.ProductID] = override.AmountMinor
+	}
+
+	var rates []models.ExchangeRate
+	if err := db.Find(&rates).Error; err != nil {
+		return err
+	}
+	table := newRateTable(rates)
+
+	for i := range products {
+		product := &products[i]
+		if product.Currency == currency {
+			price := models.NewMoney(product.EffectivePriceMinor, currency, models.PriceSourceBase)
+			product.Price = &price
+			continue
+		}
+		if minor, ok := overridePrices[product.ID]; ok && product.PriceListID == nil {
+			price := models.NewMoney(minor, currency, models.PriceSourceOverride)
+			product.Price = &price
+			continue
+		}
+
+		rate, ok := table.rate(product.Currency, currency)
+		if !ok {
+			// Satu produk tanpa rate tidak menggagalkan seluruh listing
+			price := models.NewMoney(product.EffectivePriceMinor, product.Currency, models.PriceSourceUnconverted)
+			product.Price = &price
+			continue
+		}
+		price := models.NewMoney(convertMinor(product.EffectivePriceMinor, product.Currency, currency, rate), currency, models.PriceSourceConverted)
+		price.Rate, _ = rate.Float64()
+		product.Price = &price
+	}
+	return nil
+}
+
+// convertMinor mengonversi nilai minor unit antar mata uang. Perhitungan dilakukan secara eksak
+// lalu dibulatkan half-up sekali ke minor unit mata uang tujuan.
+func convertMinor(minor int64, from, to string, rate *big.Rat) int64 {
+	value := new(big.Rat).SetInt64(minor)
+	value.Mul(value, rate)
+	value.Mul(value, models.MinorUnitScale(to))
+	value.Quo(value, models.MinorUnitScale(from))
+	return models.RoundHalfUp(value)
+}
+
+// rateTable menyimpan exchange rate sebagai bilangan rasional agar konversi tidak terkena error float
+type rateTable map[[2]string]*big.Rat
+
+func newRateTable(rates []models.ExchangeRate) rateTable {
+	table := make(rateTable, len(rates))
+	for _, rate := range rates {
+		// Representasi desimal terpendek dari float dipakai sebagai nilai eksak rate
+		value, ok := new(big.Rat).SetString(strconv.FormatFloat(rate.Rate, 'f', -1, 64))
+		if ok && value.Sign() > 0 {
+			table[[2]string{rate.FromCurrency, rate.ToCurrency}] = value
+		}
+	}
+	return table
+}
+
+// rate mencari rate langsung, lalu kebalikan rate arah sebaliknya, lalu lewat DefaultCurrency
+func (t rateTable) rate(from, to string) (*big.Rat, bool) {
+	if rate, ok := t.direct(from, to); ok {
+		return rate, true
+	}
+	if from == models.DefaultCurrency || to == models.DefaultCurrency {
+		return nil, false
+	}
+	first, ok := t.direct(from, models.DefaultCurrency)
+	if !ok {
+		return nil, false
+	}
+	second, ok := t.direct(models.DefaultCurrency, to)
+	if !ok {
+		return nil, false
+	}
+	return new(big.Rat).Mul(first, second), true
+}
+
+func (t rateTable) direct(from, to string) (*big.Rat, bool) {
+	if rate, ok := t[[2]string{from, to}]; ok {
+		return rate, true
+	}
+	if rate, ok := t[[2]string{to, from}]; ok {
+		return new(big.Rat).Inv(rate), true
+	}
+	return nil, false
+}
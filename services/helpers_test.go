@@ -0,0 +1,74 @@
+package services
+
+import (
+	"path/filepath"
+	"products-api-with-jwt/models"
+	"testing"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// newTestDB membuat database SQLite sementara dengan schema lengkap, satu warehouse default dan satu user
+func newTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	dsn := filepath.Join(t.TempDir(), "test.db") + "?_busy_timeout=5000&_txlock=immediate"
+	db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = db.AutoMigrate(&models.User{}, &models.Product{}, &models.StockMovement{}, &models.Reservation{},
+		&models.Warehouse{}, &models.WarehouseStock{}, &models.Category{}, &models.Tag{},
+		&models.ProductOption{}, &models.ProductVariant{}, &models.ProductImage{},
+		&models.PriceChange{}, &models.ScheduledPrice{}, &models.ExchangeRate{}, &models.ProductCurrencyPrice{},
+		&models.Promotion{}, &models.ProductTranslation{},
+		&models.AttributeDefinition{}, &models.BundleComponent{}, &models.ProductRelation{},
+		&models.Supplier{}, &models.SupplierProduct{}, &models.PurchaseOrder{}, &models.PurchaseOrderLine{},
+		&models.Order{}, &models.OrderLine{}, &models.Cart{}, &models.CartItem{},
+		&models.TaxClass{}, &models.TaxRate{}, &models.Review{},
+		&models.PriceList{}, &models.PriceListAssignment{}, &models.PriceListRule{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Create(&models.Warehouse{Code: "MAIN", Name: "Main Warehouse", IsDefault: true}).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Create(&models.User{Username: "buyer", Password: "x", Role: "user"}).Error; err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+// createPublishedProduct membuat produk lewat ProductService agar stok awal tercatat di ledger, lalu mempublikasikannya
+func createPublishedProduct(t *testing.T, ps *ProductService, name string, harga float64, stok int) int {
+	t.Helper()
+	product, err := ps.CreateProduct(&models.CreateProductInput{NamaProduk: name, Deskripsi: name, Harga: harga, Stok: stok}, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ps.DB.Model(&models.Product{}).Where("id = ?", product.ID).Update("status", models.ProductPublished).Error; err != nil {
+		t.Fatal(err)
+	}
+	return product.ID
+}
+
+// stockOf membaca Stok produk, termasuk produk yang sudah di-soft delete
+func stockOf(t *testing.T, db *gorm.DB, id int) int {
+	t.Helper()
+	var product models.Product
+	if err := db.Unscoped().First(&product, id).Error; err != nil {
+		t.Fatal(err)
+	}
+	return product.Stok
+}
+
+// movementCount menghitung entri ledger sebuah produk
+func movementCount(t *testing.T, db *gorm.DB, productID int) int64 {
+	t.Helper()
+	var count int64
+	if err := db.Model(&models.StockMovement{}).Where("product_id = ?", productID).Count(&count).Error; err != nil {
+		t.Fatal(err)
+	}
+	return count
+}
@@ -0,0 +1,414 @@
+package services
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/gif" // Daftarkan decoder GIF untuk image.Decode
+	"image/jpeg"
+	"image/png"
+	"io"
+	"log"
+	"net/http"
+	"path"
+	"products-api-with-jwt/models"
+	"products-api-with-jwt/storage"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// ImageOriginal dan ImageThumbnail adalah versi gambar yang bisa diminta lewat signed URL
+	ImageOriginal  = "original"
+	ImageThumbnail = "thumbnail"
+
+	thumbnailSize  = 256        // Sisi terpanjang thumbnail dalam pixel
+	maxImagePixels = 40_000_000 // Batas resolusi agar decode gambar tidak menghabiskan memori
+)
+
+var (
+	// ErrImageNotFound dikembalikan jika gambar tidak ada di produk yang diminta
+	ErrImageNotFound = errors.New("image not found")
+	// ErrImageTooLarge dikembalikan jika ukuran file atau resolusi gambar melebihi batas
+	ErrImageTooLarge = errors.New("image is too large")
+	// ErrUnsupportedImageType dikembalikan jika file bukan JPEG, PNG atau GIF
+	ErrUnsupportedImageType = errors.New("image must be a JPEG, PNG or GIF")
+	// ErrInvalidImage dikembalikan jika file tidak bisa dibaca sebagai gambar
+	ErrInvalidImage = errors.New("file is not a valid image")
+	// ErrInvalidImageSignature dikembalikan jika signed URL salah atau sudah kedaluwarsa
+	ErrInvalidImageSignature = errors.New("image URL is invalid or expired")
+)
+
+// imageExtensions memetakan MIME type yang diizinkan ke ekstensi file
+var imageExtensions = map[string]string{
+	"image/jpeg": "jpg",
+	"image/png":  "png",
+	"image/gif":  "gif",
+}
+
+type ImageService struct {
+	DB      *gorm.DB
+	Storage storage.Storage
+	Secret  []byte        // Kunci HMAC untuk signed URL
+	URLTTL  time.Duration // Lama signed URL berlaku
+	MaxSize int64         // Ukuran file maksimal dalam byte
+}
+
+// NewImageService menginisialisasi ImageService baru
+func NewImageService(db *gorm.DB, store storage.Storage, secret string, urlTTL time.Duration, maxSize int64) *ImageService {
+	return &ImageService{DB: db, Storage: store, Secret: []byte(secret), URLTTL: urlTTL, MaxSize: maxSize}
+}
+
+// GetImages mengambil gambar produk sesuai urutan, lengkap dengan signed URL
+func (s *ImageService) GetImages(productID int) ([]models.ProductImage, error) {
+	if _, err := findProduct(s.DB, productID); err != nil {
+		return nil, err
+	}
+	images, err := productImages(s.DB, productID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range images {
+		s.sign(&images[i])
+	}
+	return images, nil
+}
+
+// UploadImage memvalidasi dan menyimpan gambar baru beserta thumbnailnya.
+// Gambar pertama sebuah produk otomatis menjadi gambar utama.
+func (s *ImageService) UploadImage(productID int, fileName string, r io.Reader, primary bool) (*models.ProductImage, error) {
+	data, err := io.ReadAll(io.LimitReader(r, s.MaxSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > s.MaxSize {
+		return nil, ErrImageTooLarge
+	}
+
+	contentType := http.DetectContentType(data)
+	ext, ok := imageExtensions[contentType]
+	if !ok {
+		return nil, ErrUnsupportedImageType
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrInvalidImage
+	}
+	if config.Width*config.Height > maxImagePixels {
+		return nil, ErrImageTooLarge
+	}
+	decoded, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrInvalidImage
+	}
+
+	var thumb bytes.Buffer
+	thumbExt := "png"
+	if contentType == "image/jpeg" {
+		thumbExt = "jpg"
+		err = jpeg.Encode(&thumb, thumbnail(decoded, thumbnailSize), &jpeg.Options{Quality: 85})
+	} else {
+		err = png.Encode(&thumb, thumbnail(decoded, thumbnailSize))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	name, err := randomName()
+	if err != nil {
+		return nil, err
+	}
+	img := models.ProductImage{
+		ProductID:    productID,
+		FileName:     path.Base(fileName),
+		ContentType:  contentType,
+		Size:         int64(len(data)),
+		Width:        config.Width,
+		Height:       config.Height,
+		StorageKey:   fmt.Sprintf("products/%d/%s.%s", productID, name, ext),
+		ThumbnailKey: fmt.Sprintf("products/%d/%s_thumb.%s", productID, name, thumbExt),
+	}
+
+	if _, err := findProduct(s.DB, productID); err != nil {
+		return nil, err
+	}
+	if err := s.Storage.Save(img.StorageKey, bytes.NewReader(data)); err != nil {
+		return nil, err
+	}
+	if err := s.Storage.Save(img.ThumbnailKey, &thumb); err != nil {
+		s.deleteFiles(img)
+		return nil, err
+	}
+
+	err = s.DB.Transaction(func(tx *gorm.DB) error {
+		if _, err := findProduct(tx, productID); err != nil {
+			return err
+		}
+		var existing []models.ProductImage
+		if err := tx.Where("product_id = ?", productID).Find(&existing).Error; err != nil {
+			return err
+		}
+
+		img.Position = len(existing)
+		img.IsPrimary = primary || len(existing) == 0
+		if img.IsPrimary {
+			if err := tx.Model(&models.ProductImage{}).Where("product_id = ?", productID).Update("is_primary", false).Error; err != nil {
+				return err
+			}
+		}
+		// Sekali punya gambar, produk harus tetap punya minimal satu gambar untuk di-publish
+		if err := tx.Model(&models.Product{}).Where("id = ?", productID).Update("has_had_images", true).Error; err != nil {
+			return err
+		}
+		return tx.Create(&img).Error
+	})
+	if err != nil {
+		s.deleteFiles(img)
+		return nil, err
+	}
+	s.sign(&img)
+	return &img, nil
+}
+
+// UpdateImage memindahkan gambar ke posisi lain dan/atau menjadikannya gambar utama.
+// Jika gambar utama di-set is_primary=false, gambar pertama lainnya menjadi gambar utama.
+func (s *ImageService) UpdateImage(productID, imageID int, input *models.UpdateImageInput) (*models.ProductImage, error) {
+	var updated models.ProductImage
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		if _, err := findProduct(tx, productID); err != nil {
+			return err
+		}
+		images, err := productImages(tx, productID)
+		if err != nil {
+			return err
+		}
+		index := imageIndex(images, imageID)
+		if index < 0 {
+			return ErrImageNotFound
+		}
+
+		if input.Position != nil {
+			moved := images[index]
+			images = append(images[:index], images[index+1:]...)
+			position := min(*input.Position, len(images))
+			images = append(images[:position], append([]models.ProductImage{moved}, images[position:]...)...)
+			index = position
+		}
+		if input.IsPrimary != nil && *input.IsPrimary {
+			for i := range images {
+				images[i].IsPrimary = i == index
+			}
+		} else if input.IsPrimary != nil && images[index].IsPrimary && len(images) > 1 {
+			// Serahkan status gambar utama ke gambar pertama selain gambar ini
+			images[index].IsPrimary = false
+			if index == 0 {
+				images[1].IsPrimary = true
+			} else {
+				images[0].IsPrimary = true
+			}
+		}
+		ensurePrimaryImage(images)
+
+		if err := saveImageOrder(tx, images); err != nil {
+			return err
+		}
+		updated = images[index]
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.sign(&updated)
+	return &updated, nil
+}
+
+// DeleteImage menghapus gambar beserta filenya. Urutan gambar lain dirapikan kembali.
+func (s *ImageService) DeleteImage(productID, imageID int) error {
+	var deleted models.ProductImage
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		if _, err := findProduct(tx, productID); err != nil {
+			return err
+		}
+		images, err := productImages(tx, productID)
+		if err != nil {
+			return err
+		}
+		index := imageIndex(images, imageID)
+		if index < 0 {
+			return ErrImageNotFound
+		}
+
+		deleted = images[index]
+		if err := tx.Delete(&deleted).Error; err != nil {
+			return err
+		}
+		images = append(images[:index], images[index+1:]...)
+		ensurePrimaryImage(images)
+		return saveImageOrder(tx, images)
+	})
+	if err != nil {
+		return err
+	}
+	s.deleteFiles(deleted)
+	return nil
+}
+
+// OpenImage memverifikasi signed URL lalu membuka file gambar atau thumbnailnya.
+// Mengembalikan isi file dan content type-nya.
+func (s *ImageService) OpenImage(imageID int, version string, expires int64, signature string) (io.ReadCloser, string, error) {
+	if time.Now().Unix() > expires || !hmac.Equal([]byte(signature), []byte(s.signature(imageID, version, expires))) {
+		return nil, "", ErrInvalidImageSignature
+	}
+
+	var img models.ProductImage
+	err := s.DB.Joins("JOIN products ON products.id = product_images.product_id AND products.deleted_at IS NULL").
+		First(&img, imageID).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, "", ErrImageNotFound
+		}
+		return nil, "", err
+	}
+
+	key, contentType := img.StorageKey, img.ContentType
+	if version == ImageThumbnail {
+		key, contentType = img.ThumbnailKey, "image/png"
+		if path.Ext(key) == ".jpg" {
+			contentType = "image/jpeg"
+		}
+	}
+	file, err := s.Storage.Open(key)
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil, "", ErrImageNotFound
+	}
+	return file, contentType, err
+}
+
+// sign mengisi URL dan ThumbnailURL dengan signed URL yang berlaku selama URLTTL
+func (s *ImageService) sign(img *models.ProductImage) {
+	expires := time.Now().Add(s.URLTTL).Unix()
+	img.URL = s.signedURL(img.ID, ImageOriginal, expires)
+	img.ThumbnailURL = s.signedURL(img.ID, ImageThumbnail, expires)
+}
+
+func (s *ImageService) signedURL(imageID int, version string, expires int64) string {
+	return fmt.Sprintf("/images/%d/%s?expires=%d&signature=%s", imageID, version, expires, s.signature(imageID, version, expires))
+}
+
+// signature adalah HMAC-SHA256 dari ID gambar, versi dan waktu kedaluwarsa
+func (s *ImageService) signature(imageID int, version string, expires int64) string {
+	mac := hmac.New(sha256.New, s.Secret)
+	mac.Write([]byte(strconv.Itoa(imageID) + ":" + version + ":" + strconv.FormatInt(expires, 10)))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func (s *ImageService) deleteFiles(img models.ProductImage) {
+	deleteImageFiles(s.Storage, []models.ProductImage{img})
+}
+
+// deleteImageFiles menghapus file gambar dan thumbnail dari storage. Kegagalan hanya dicatat di log
+// karena baris database-nya sudah terhapus.
+func deleteImageFiles(store storage.Storage, images []models.ProductImage) {
+	for _, img := range images {
+		for _, key := range []string{img.StorageKey, img.ThumbnailKey} {
+			if err := store.Delete(key); err != nil {
+				log.Printf("Failed to delete image file %s: %v", key, err)
+			}
+		}
+	}
+}
+
+func productImages(db *gorm.DB, productID int) ([]models.ProductImage, error) {
+	var images []models.ProductImage
+	if err := db.Where("product_id = ?", productID).Order("position, id").Find(&images).Error; err != nil {
+		return nil, err
+	}
+	return images, nil
+}
+
+func imageIndex(images []models.ProductImage, imageID int) int {
+	for i, img := range images {
+		if img.ID == imageID {
+			return i
+		}
+	}
+	return -1
+}
+
+// ensurePrimaryImage menjadikan gambar pertama sebagai gambar utama jika belum ada gambar utama
+func ensurePrimaryImage(images []models.ProductImage) {
+	for _, img := range images {
+		if img.IsPrimary {
+			return
+		}
+	}
+	if len(images) > 0 {
+		images[0].IsPrimary = true
+	}
+}
+
+// saveImageOrder menyimpan posisi (0..n-1 sesuai urutan slice) dan flag gambar utama
+func saveImageOrder(tx *gorm.DB, images []models.ProductImage) error {
+	for i := range images {
+		images[i].Position = i
+		err := tx.Model(&images[i]).Select("position", "is_primary").
+			Updates(map[string]interface{}{"position": i, "is_primary": images[i].IsPrimary}).Error
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// thumbnail mengecilkan gambar agar sisi terpanjangnya maksimal size pixel, dengan rata-rata area (box filter).
+// Gambar yang sudah lebih kecil hanya disalin.
+func thumbnail(src image.Image, size int) image.Image {
+	bounds := src.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+	tw, th := w, h
+	if w > size || h > size {
+		if w >= h {
+			tw, th = size, max(1, h*size/w)
+		} else {
+			tw, th = max(1, w*size/h), size
+		}
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, tw, th))
+	for y := 0; y < th; y++ {
+		y0 := bounds.Min.Y + y*h/th
+		y1 := max(y0+1, bounds.Min.Y+(y+1)*h/th)
+		for x := 0; x < tw; x++ {
+			x0 := bounds.Min.X + x*w/tw
+			x1 := max(x0+1, bounds.Min.X+(x+1)*w/tw)
+
+			var r, g, b, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					cr, cg, cb, ca := src.At(sx, sy).RGBA()
+					r, g, b, a = r+uint64(cr), g+uint64(cg), b+uint64(cb), a+uint64(ca)
+					n++
+				}
+			}
+			dst.SetRGBA(x, y, color.RGBA{R: uint8(r / n >> 8), G: uint8(g / n >> 8), B: uint8(b / n >> 8), A: uint8(a / n >> 8)})
+		}
+	}
+	return dst
+}
+
+func randomName() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
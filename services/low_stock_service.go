@@ -0,0 +1,71 @@
+package services
+
+import (
+	"log"
+	"products-api-with-jwt/models"
+	"time"
+)
+
+// GetLowStockProducts mengambil produk yang stoknya sudah sampai atau di bawah reorder point,
+// produk dengan kekurangan terbesar lebih dulu. Bundle tidak ikut karena stoknya berasal dari komponen.
+func (s *ProductService) GetLowStockProducts() ([]models.Product, error) {
+	var products []models.Product
+	err := s.DB.Where("type = ? AND reorder_point > 0 AND stok <= reorder_point", models.ProductSimple).
+		Order("reorder_point - stok desc, id").
+		Find(&products).Error
+	if err != nil {
+		return nil, err
+	}
+	return products, nil
+}
+
+// evaluateLowStock dipanggil setelah Stok atau reorder point berubah. Flag LowStockAlerted
+// di-set secara kondisional sehingga alert hanya dikirim sekali saat stok melewati threshold,
+// dan baru bisa dikirim lagi setelah stok kembali di atas threshold.
+func (s *ProductService) evaluateLowStock(productIDs ...int) {
+	for _, id := range productIDs {
+		// Stok sudah kembali di atas threshold, siapkan alert berikutnya
+		err := s.DB.Model(&models.Product{}).
+			Where("id = ? AND low_stock_alerted = ? AND (reorder_point = 0 OR stok > reorder_point)", id, true).
+			Update("low_stock_alerted", false).Error
+		if err != nil {
+			log.Printf("Could not reset low stock flag for product %d: %v", id, err)
+			continue
+		}
+
+		result := s.DB.Model(&models.Product{}).
+			Where("id = ? AND type = ? AND low_stock_alerted = ? AND reorder_point > 0 AND stok <= reorder_point", id, models.ProductSimple, false).
+			Update("low_stock_alerted", true)
+		if result.Error != nil {
+			log.Printf("Could not evaluate low stock for product %d: %v", id, result.Error)
+			continue
+		}
+		if result.RowsAffected == 0 {
+			continue
+		}
+
+		var product models.Product
+		if err := s.DB.First(&product, id).Error; err != nil {
+			log.Printf("Could not load product %d for low stock alert: %v", id, err)
+			continue
+		}
+		s.notifyLowStock(models.LowStockEvent{
+			ProductID:    product.ID,
+			NamaProduk:   product.NamaProduk,
+			Stok:         product.Stok,
+			ReorderPoint: product.ReorderPoint,
+			OccurredAt:   time.Now(),
+		})
+	}
+}
+
+// notifyLowStock mengirim event ke semua notifier di background agar request tidak tertahan
+func (s *ProductService) notifyLowStock(event models.LowStockEvent) {
+	for _, notifier := range s.LowStockNotifiers {
+		go func() {
+			if err := notifier.NotifyLowStock(event); err != nil {
+				log.Printf("Could not send low stock alert via %s: %v", notifier.Name(), err)
+			}
+		}()
+	}
+}
@@ -0,0 +1,286 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"products-api-with-jwt/models"
+	"slices"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrOrderNotFound dikembalikan jika order tidak ada atau bukan milik user yang meminta
+	ErrOrderNotFound = errors.New("order not found")
+	// ErrInvalidOrderTransition dikembalikan jika perpindahan status order tidak diizinkan
+	ErrInvalidOrderTransition = errors.New("order cannot move to this status from its current status")
+	// ErrOrderStatusForbidden dikembalikan jika user biasa mencoba status selain membatalkan order miliknya
+	ErrOrderStatusForbidden = errors.New("only admins can change the order status, customers can only cancel")
+	// ErrProductNotAvailable dikembalikan jika produk di order belum atau tidak lagi published
+	ErrProductNotAvailable = errors.New("product is not available for sale")
+	// ErrOrderCurrencyMismatch dikembalikan jika produk di satu order memakai mata uang yang berbeda
+	ErrOrderCurrencyMismatch = errors.New("all products in an order must use the same currency")
+	// ErrDuplicateOrderLine dikembalikan jika satu produk muncul di lebih dari satu baris
+	ErrDuplicateOrderLine = errors.New("each product can only appear once in an order")
+)
+
+// orderTransitions adalah status berikutnya yang diizinkan dari setiap status order
+var orderTransitions = map[string][]string{
+	models.OrderPending: {models.OrderPaid, models.OrderCancelled},
+	models.OrderPaid:    {models.OrderShipped, models.OrderRefunded},
+	models.OrderShipped: {models.OrderRefunded},
+}
+
+type OrderService struct {
+	DB             *gorm.DB
+	ProductService *ProductService
+}
+
+// NewOrderService menginisialisasi OrderService baru
+func NewOrderService(db *gorm.DB, productService *ProductService) *OrderService {
+	return &OrderService{DB: db, ProductService: productService}
+}
+
+// GetOrders mengambil order terbaru lebih dulu. userID 0 berarti order semua user.
+func (s *OrderService) GetOrders(userID int, status string) ([]models.Order, error) {
+	query := s.DB.Preload("Lines").Order("id desc")
+	if userID != 0 {
+		query = query.Where("user_id = ?", userID)
+	}
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	var orders []models.Order
+	if err := query.Find(&orders).Error; err != nil {
+		return nil, err
+	}
+	return orders, nil
+}
+
+// GetOrderByID mengambil order beserta barisnya. userID 0 berarti order milik siapa pun.
+func (s *OrderService) GetOrderByID(id, userID int) (*models.Order, error) {
+	return findOrder(s.DB, id, userID)
+}
+
+// CreateOrder membuat order pending. Stok dikurangi di transaksi yang sama dengan insert order,
+// sehingga order hanya tersimpan jika stok semua produknya cukup.
+func (s *OrderService) CreateOrder(input *models.OrderInput, userID int) (*models.Order, error) {
+	var order *models.Order
+	var movements []models.StockMovement
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		order, movements, err = s.placeOrder(tx, userID, input.Region, input.Notes, input.Lines)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.ProductService.evaluateLowStock(movedProductIDs(movements)...)
+	return s.GetOrderByID(order.ID, 0)
+}
+
+// ChangeOrderStatus memindahkan order ke status berikutnya. User biasa hanya boleh membatalkan order
+// miliknya sendiri. Cancel dan refund sebelum dikirim mengembalikan stok lewat ledger.
+func (s *OrderService) ChangeOrderStatus(id int, status string, userID int, admin bool) (*models.Order, error) {
+	var movements []models.StockMovement
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		ownerID := userID
+		if admin {
+			ownerID = 0
+		}
+		order, err := findOrder(tx, id, ownerID)
+		if err != nil {
+			return err
+		}
+		if !admin && status != models.OrderCancelled {
+			return ErrOrderStatusForbidden
+		}
+		if !slices.Contains(orderTransitions[order.Status], status) {
+			return ErrInvalidOrderTransition
+		}
+
+		// Barang yang sudah dikirim tidak kembali ke gudang saat refund, retur dicatat terpisah di ledger
+		if status == models.OrderCancelled || order.Status == models.OrderPaid && status == models.OrderRefunded {
+			if movements, err = s.returnOrderStock(tx, order.ID, userID, "order "+status); err != nil {
+				return err
+			}
+		}
+
+		now := time.Now()
+		updates := map[string]any{"status": status}
+		switch status {
+		case models.OrderPaid:
+			updates["paid_at"] = now
+		case models.OrderShipped:
+			updates["shipped_at"] = now
+		case models.OrderCancelled:
+			updates["cancelled_at"] = now
+		case models.OrderRefunded:
+			updates["refunded_at"] = now
+		}
+		// Update kondisional agar dua perubahan status bersamaan tidak sama-sama berhasil
+		result := tx.Model(&models.Order{}).Where("id = ? AND status = ?", order.ID, order.Status).Updates(updates)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrInvalidOrderTransition
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.ProductService.evaluateLowStock(movedProductIDs(movements)...)
+	return s.GetOrderByID(id, 0)
+}
+
+// placeOrder menyimpan order pending dengan salinan harga saat ini (termasuk price list user, promo dan pajak region)
+// lalu mengurangi stok. Harus dipanggil di dalam transaksi.
+func (s *OrderService) placeOrder(tx *gorm.DB, userID int, region, notes string, inputs []models.OrderLineInput) (*models.Order, []models.StockMovement, error) {
+	region, err := normalizeTaxRegion(region)
+	if err != nil {
+		return nil, nil, err
+	}
+	priceList, err := userPriceList(tx, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	now := time.Now()
+	order := models.Order{
+		UserID: userID,
+		Status: models.OrderPending,
+		Region: region,
+		Notes:  strings.TrimSpace(notes),
+		Lines:  make([]models.OrderLine, 0, len(inputs)),
+	}
+	for _, input := range inputs {
+		if slices.ContainsFunc(order.Lines, func(line models.OrderLine) bool { return line.ProductID == input.ProductID }) {
+			return nil, nil, ErrDuplicateOrderLine
+		}
+		product, err := findProduct(tx, input.ProductID)
+		if err != nil {
+			return nil, nil, err
+		}
+		if product.Status != models.ProductPublished {
+			return nil, nil, ErrProductNotAvailable
+		}
+		if order.Currency == "" {
+			order.Currency = product.Currency
+		} else if order.Currency != product.Currency {
+			return nil, nil, ErrOrderCurrencyMismatch
+		}
+
+		quote, err := quotePrice(tx, product.ID, input.Quantity, now, nil, region, priceList)
+		if err != nil {
+			return nil, nil, err
+		}
+		line := models.OrderLine{
+			ProductID:        product.ID,
+			NamaProduk:       product.NamaProduk,
+			Quantity:         input.Quantity,
+			UnitPriceMinor:   quote.UnitPriceMinor,
+			ListPriceMinor:   quote.ListPriceMinor,
+			PriceListID:      quote.PriceListID,
+			DiscountMinor:    quote.DiscountMinor,
+			TaxRate:          quote.TaxRate,
+			PriceIncludesTax: quote.PriceIncludesTax,
+			TaxMinor:         quote.TaxMinor,
+			TotalMinor:       quote.GrossMinor,
+			Currency:         product.Currency,
+		}
+		if product.SKU != nil {
+			line.SKU = *product.SKU
+		}
+		order.Lines = append(order.Lines, line)
+		order.SubtotalMinor += quote.SubtotalMinor
+		order.DiscountMinor += quote.DiscountMinor
+		order.TaxMinor += quote.TaxMinor
+		order.TotalMinor += quote.GrossMinor
+	}
+	if err := tx.Create(&order).Error; err != nil {
+		return nil, nil, err
+	}
+
+	var movements []models.StockMovement
+	for _, line := range order.Lines {
+		movement := models.StockMovement{
+			ProductID: line.ProductID,
+			Type:      models.StockMovementSale,
+			Quantity:  -line.Quantity,
+			UserID:    userID,
+			Reason:    "order placed",
+			Reference: orderReference(order.ID),
+		}
+		posted, err := s.ProductService.postProductMovement(tx, movement)
+		if err != nil {
+			return nil, nil, err
+		}
+		movements = append(movements, posted...)
+	}
+	return &order, movements, nil
+}
+
+// returnOrderStock membalik pergerakan sale yang dicatat saat order dibuat, ke warehouse yang sama.
+// Bundle dikembalikan ke komponen yang benar-benar terjual walaupun komposisinya sudah berubah.
+// Unscoped agar stok tetap kembali walaupun produknya sudah di-soft delete.
+func (s *OrderService) returnOrderStock(tx *gorm.DB, orderID, userID int, reason string) ([]models.StockMovement, error) {
+	unscoped := tx.Unscoped().Session(&gorm.Session{})
+	var sales []models.StockMovement
+	err := unscoped.Where("reference = ? AND type = ?", orderReference(orderID), models.StockMovementSale).
+		Order("id").Find(&sales).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var movements []models.StockMovement
+	for _, sale := range sales {
+		movement := models.StockMovement{
+			ProductID:   sale.ProductID,
+			WarehouseID: sale.WarehouseID,
+			Type:        models.StockMovementReturn,
+			Quantity:    -sale.Quantity,
+			UserID:      userID,
+			Reason:      reason,
+			Reference:   sale.Reference,
+		}
+		// Warehouse yang sudah dihapus diganti warehouse default
+		var count int64
+		if err := unscoped.Model(&models.Warehouse{}).Where("id = ?", sale.WarehouseID).Count(&count).Error; err != nil {
+			return nil, err
+		}
+		if count == 0 {
+			movement.WarehouseID = 0
+		}
+		posted, err := s.ProductService.postStockMovement(unscoped, movement)
+		if err != nil {
+			return nil, err
+		}
+		movements = append(movements, posted...)
+	}
+	return movements, nil
+}
+
+func findOrder(tx *gorm.DB, id, userID int) (*models.Order, error) {
+	query := tx.Preload("Lines", func(db *gorm.DB) *gorm.DB {
+		return db.Order("id")
+	})
+	if userID != 0 {
+		query = query.Where("user_id = ?", userID)
+	}
+	var order models.Order
+	if err := query.First(&order, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrOrderNotFound
+		}
+		return nil, err
+	}
+	return &order, nil
+}
+
+// orderReference adalah referensi pergerakan stok milik order
+func orderReference(id int) string {
+	return fmt.Sprintf("order:%d", id)
+}
@@ -0,0 +1,82 @@
+package services
+
+import (
+	"products-api-with-jwt/models"
+	"testing"
+)
+
+func TestOrderStockRoundTrip(t *testing.T) {
+	tests := []struct {
+		name   string
+		before []string // Status yang dilalui order sebelum status akhir
+		final  string
+	}{
+		{name: "cancel pending order", final: models.OrderCancelled},
+		{name: "refund paid order", before: []string{models.OrderPaid}, final: models.OrderRefunded},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := newTestDB(t)
+			ps := NewProductService(db)
+			orders := NewOrderService(db, ps)
+
+			simple := createPublishedProduct(t, ps, "Simple", 1000, 10)
+			component := createPublishedProduct(t, ps, "Component", 2000, 20)
+			replacement := createPublishedProduct(t, ps, "Replacement", 3000, 5)
+			bundle := createPublishedProduct(t, ps, "Bundle", 5000, 0)
+			_, err := ps.SetBundle(bundle, &models.BundleInput{
+				Components: []models.BundleComponentInput{{ProductID: component, Quantity: 2}},
+				Pricing:    models.BundlePricingFixed,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			order, err := orders.CreateOrder(&models.OrderInput{Lines: []models.OrderLineInput{
+				{ProductID: simple, Quantity: 3},
+				{ProductID: bundle, Quantity: 2},
+			}}, 1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := stockOf(t, db, simple); got != 7 {
+				t.Fatalf("simple stock after order = %d, want 7", got)
+			}
+			if got := stockOf(t, db, component); got != 16 {
+				t.Fatalf("component stock after order = %d, want 16", got)
+			}
+
+			// Produk yang sudah dihapus dan komposisi bundle yang berubah tidak boleh menghalangi pengembalian stok
+			if err := ps.DeleteProduct(simple); err != nil {
+				t.Fatal(err)
+			}
+			_, err = ps.SetBundle(bundle, &models.BundleInput{
+				Components: []models.BundleComponentInput{{ProductID: replacement, Quantity: 1}},
+				Pricing:    models.BundlePricingFixed,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			purged, err := ps.PurgeDeletedProducts(0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if purged != 0 {
+				t.Fatalf("purged %d products while their order is open, want 0", purged)
+			}
+
+			for _, status := range append(tt.before, tt.final) {
+				if _, err := orders.ChangeOrderStatus(order.ID, status, 1, true); err != nil {
+					t.Fatalf("change order to %s: %v", status, err)
+				}
+			}
+
+			want := map[int]int{simple: 10, component: 20, replacement: 5}
+			for id, stok := range want {
+				if got := stockOf(t, db, id); got != stok {
+					t.Errorf("product %d stock = %d, want %d", id, got, stok)
+				}
+			}
+		})
+	}
+}
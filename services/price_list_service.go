@@ -0,0 +1,448 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"products-api-with-jwt/models"
+	"slices"
+	"strconv"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrPriceListNotFound dikembalikan jika price list dengan ID yang diminta tidak ada
+	ErrPriceListNotFound = errors.New("price list not found")
+	// ErrDuplicatePriceListName dikembalikan jika nama price list sudah dipakai
+	ErrDuplicatePriceListName = errors.New("price list name already exists")
+	// ErrPriceListAssigned dikembalikan jika customer group atau department sudah di-assign ke price list lain
+	ErrPriceListAssigned = errors.New("customer group or department is already assigned to another price list")
+	// ErrPriceListRuleNotFound dikembalikan jika aturan tidak ada di price list tersebut
+	ErrPriceListRuleNotFound = errors.New("price list rule not found")
+	// ErrPriceListRuleTarget dikembalikan jika aturan tidak menyebut tepat satu dari produk dan kategori
+	ErrPriceListRuleTarget = errors.New("a rule needs either product_id or category_id, not both")
+	// ErrFixedCategoryPrice dikembalikan jika harga fixed diberikan untuk kategori
+	ErrFixedCategoryPrice = errors.New("fixed prices can only be set for a product, use a percentage for categories")
+	// ErrDuplicatePriceListRule dikembalikan jika price list sudah punya aturan untuk target dan MinQuantity yang sama
+	ErrDuplicatePriceListRule = errors.New("a rule for this product or category and min_quantity already exists")
+	// ErrUserNotFound dikembalikan jika user dengan ID yang diminta tidak ada
+	ErrUserNotFound = errors.New("user not found")
+)
+
+type PriceListService struct {
+	DB *gorm.DB
+}
+
+// NewPriceListService menginisialisasi PriceListService baru
+func NewPriceListService(db *gorm.DB) *PriceListService {
+	return &PriceListService{DB: db}
+}
+
+// GetAllPriceLists mengambil semua price list beserta assignment dan aturannya
+func (s *PriceListService) GetAllPriceLists() ([]models.PriceList, error) {
+	var priceLists []models.PriceList
+	if err := withPriceListDetails(s.DB).Order("name").Find(&priceLists).Error; err != nil {
+		return nil, err
+	}
+	return priceLists, nil
+}
+
+// GetPriceListByID mengambil price list beserta assignment dan aturannya
+func (s *PriceListService) GetPriceListByID(id int) (*models.PriceList, error) {
+	return findPriceList(withPriceListDetails(s.DB), id)
+}
+
+// CreatePriceList membuat price list baru dan meng-assign-nya ke customer group dan department
+func (s *PriceListService) CreatePriceList(input *models.PriceListInput) (*models.PriceList, error) {
+	priceList := models.PriceList{
+		Name:        strings.TrimSpace(input.Name),
+		Description: strings.TrimSpace(input.Description),
+		Active:      input.Active == nil || *input.Active,
+	}
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		if err := checkUniquePriceListName(tx, priceList.Name, 0); err != nil {
+			return err
+		}
+		if err := tx.Create(&priceList).Error; err != nil {
+			return err
+		}
+		return assignPriceList(tx, priceList.ID, input)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetPriceListByID(priceList.ID)
+}
+
+// UpdatePriceList mengganti nama, status dan assignment price list. Aturannya tidak berubah.
+func (s *PriceListService) UpdatePriceList(id int, input *models.PriceListInput) (*models.PriceList, error) {
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		priceList, err := findPriceList(tx, id)
+		if err != nil {
+			return err
+		}
+		priceList.Name = strings.TrimSpace(input.Name)
+		priceList.Description = strings.TrimSpace(input.Description)
+		priceList.Active = input.Active == nil || *input.Active
+		if err := checkUniquePriceListName(tx, priceList.Name, id); err != nil {
+			return err
+		}
+		if err := tx.Model(priceList).Select("name", "description", "active").Updates(priceList).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("price_list_id = ?", id).Delete(&models.PriceListAssignment{}).Error; err != nil {
+			return err
+		}
+		return assignPriceList(tx, id, input)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetPriceListByID(id)
+}
+
+// DeletePriceList menghapus price list beserta assignment dan aturannya
+func (s *PriceListService) DeletePriceList(id int) error {
+	return s.DB.Transaction(func(tx *gorm.DB) error {
+		if _, err := findPriceList(tx, id); err != nil {
+			return err
+		}
+		if err := tx.Where("price_list_id = ?", id).Delete(&models.PriceListAssignment{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("price_list_id = ?", id).Delete(&models.PriceListRule{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.PriceList{}, id).Error
+	})
+}
+
+// AddRule menambah harga produk atau diskon kategori ke price list
+func (s *PriceListService) AddRule(priceListID int, input *models.PriceListRuleInput) (*models.PriceListRule, error) {
+	rule := models.PriceListRule{PriceListID: priceListID}
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		if _, err := findPriceList(tx, priceListID); err != nil {
+			return err
+		}
+		if err := buildPriceListRule(tx, &rule, input); err != nil {
+			return err
+		}
+		return tx.Create(&rule).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
+
+// UpdateRule mengganti target, tier dan harga aturan price list
+func (s *PriceListService) UpdateRule(priceListID, ruleID int, input *models.PriceListRuleInput) (*models.PriceListRule, error) {
+	var rule models.PriceListRule
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("price_list_id = ?", priceListID).First(&rule, ruleID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrPriceListRuleNotFound
+			}
+			return err
+		}
+		if err := buildPriceListRule(tx, &rule, input); err != nil {
+			return err
+		}
+		return tx.Select("product_id", "category_id", "min_quantity", "type", "price_minor", "currency", "percentage").
+			Updates(&rule).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
+
+// DeleteRule menghapus aturan dari price list
+func (s *PriceListService) DeleteRule(priceListID, ruleID int) error {
+	result := s.DB.Where("price_list_id = ?", priceListID).Delete(&models.PriceListRule{}, ruleID)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrPriceListRuleNotFound
+	}
+	return nil
+}
+
+// SetCustomerGroup mengganti customer group user. Harga baru berlaku di request berikutnya user tersebut.
+func (s *PriceListService) SetCustomerGroup(userID int, input *models.CustomerGroupInput) (*models.User, error) {
+	var user models.User
+	if err := s.DB.First(&user, userID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+	user.CustomerGroup = normalizeCustomerGroup(input.CustomerGroup)
+	if err := s.DB.Model(&user).Update("customer_group", user.CustomerGroup).Error; err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// buildPriceListRule memvalidasi input dan mengisinya ke rule. Harga fixed disimpan dalam mata uang produk.
+func buildPriceListRule(tx *gorm.DB, rule *models.PriceListRule, input *models.PriceListRuleInput) error {
+	if (input.ProductID == nil) == (input.CategoryID == nil) {
+		return ErrPriceListRuleTarget
+	}
+	rule.ProductID = input.ProductID
+	rule.CategoryID = input.CategoryID
+	rule.MinQuantity = max(input.MinQuantity, 1)
+	rule.Type = input.Type
+	rule.PriceMinor, rule.Currency, rule.Percentage = 0, "", 0
+
+	target := tx.Model(&models.PriceListRule{}).Where("price_list_id = ? AND min_quantity = ? AND id <> ?", rule.PriceListID, rule.MinQuantity, rule.ID)
+	if input.ProductID != nil {
+		product, err := findProduct(tx, *input.ProductID)
+		if err != nil {
+			return err
+		}
+		if input.Type == models.PriceListFixed {
+			rule.Currency = product.Currency
+			rule.PriceMinor = models.ToMinor(input.Price, product.Currency)
+		}
+		target = target.Where("product_id = ?", product.ID)
+	} else {
+		if input.Type == models.PriceListFixed {
+			return ErrFixedCategoryPrice
+		}
+		if _, err := findCategories(tx, []int{*input.CategoryID}); err != nil {
+			return err
+		}
+		target = target.Where("category_id = ?", *input.CategoryID)
+	}
+	if input.Type == models.PriceListPercentage {
+		rule.Percentage = input.Percentage
+	}
+
+	var count int64
+	if err := target.Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrDuplicatePriceListRule
+	}
+	rule.Price = models.FromMinor(rule.PriceMinor, rule.Currency)
+	return nil
+}
+
+// assignPriceList menyimpan assignment customer group dan department price list
+func assignPriceList(tx *gorm.DB, priceListID int, input *models.PriceListInput) error {
+	var assignments []models.PriceListAssignment
+	add := func(kind, value string) {
+		for _, assignment := range assignments {
+			if assignment.Type == kind && assignment.Value == value {
+				return
+			}
+		}
+		assignments = append(assignments, models.PriceListAssignment{PriceListID: priceListID, Type: kind, Value: value})
+	}
+	for _, group := range input.CustomerGroups {
+		add(models.PriceListCustomerGroup, normalizeCustomerGroup(group))
+	}
+	for _, department := range input.Departments {
+		add(models.PriceListDepartment, strings.TrimSpace(department))
+	}
+	if len(assignments) == 0 {
+		return nil
+	}
+
+	for _, assignment := range assignments {
+		var count int64
+		err := tx.Model(&models.PriceListAssignment{}).
+			Where("type = ? AND value = ? AND price_list_id <> ?", assignment.Type, assignment.Value, priceListID).
+			Count(&count).Error
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			return fmt.Errorf("%w: %s %s", ErrPriceListAssigned, strings.ReplaceAll(assignment.Type, "_", " "), assignment.Value)
+		}
+	}
+	return tx.Create(&assignments).Error
+}
+
+// normalizeCustomerGroup mengubah customer group ke huruf kecil tanpa spasi di awal dan akhir
+func normalizeCustomerGroup(group string) string {
+	return strings.ToLower(strings.TrimSpace(group))
+}
+
+// userPriceList mencari price list aktif untuk user. Price list customer group didahulukan dari price list department.
+// Mengembalikan nil jika userID 0 atau tidak ada price list yang berlaku.
+func userPriceList(db *gorm.DB, userID int) (*models.PriceList, error) {
+	if userID == 0 {
+		return nil, nil
+	}
+	var user models.User
+	if err := db.First(&user, userID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	candidates := []models.PriceListAssignment{
+		{Type: models.PriceListCustomerGroup, Value: user.CustomerGroup},
+		{Type: models.PriceListDepartment, Value: user.Department},
+	}
+	for _, candidate := range candidates {
+		if candidate.Value == "" {
+			continue
+		}
+		var priceList models.PriceList
+		err := db.Preload("Rules").
+			Where("active = ? AND id IN (?)", true, db.Model(&models.PriceListAssignment{}).
+				Select("price_list_id").Where("type = ? AND value = ?", candidate.Type, candidate.Value)).
+			First(&priceList).Error
+		if err == nil {
+			return &priceList, nil
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+	}
+	return nil, nil
+}
+
+// applyUserPriceList mengganti EffectivePrice produk dengan harga satuan price list user.
+// Dipanggil setelah applyEffectivePrices, ListPrice tetap berisi harga katalog.
+func applyUserPriceList(db *gorm.DB, products []models.Product, userID int) error {
+	if len(products) == 0 {
+		return nil
+	}
+	priceList, err := userPriceList(db, userID)
+	if err != nil || priceList == nil {
+		return err
+	}
+	return applyPriceList(db, products, priceList, 1)
+}
+
+// applyPriceList menerapkan aturan price list untuk quantity unit pada setiap produk
+func applyPriceList(db *gorm.DB, products []models.Product, priceList *models.PriceList, quantity int) error {
+	if priceList == nil || len(priceList.Rules) == 0 {
+		return nil
+	}
+
+	// Kategori (beserta induknya) hanya perlu dimuat jika ada aturan kategori
+	categoryIDs := map[int][]int{}
+	if slices.ContainsFunc(priceList.Rules, func(rule models.PriceListRule) bool { return rule.CategoryID != nil }) {
+		ids := make([]int, len(products))
+		for i, product := range products {
+			ids[i] = product.ID
+		}
+		var links []struct {
+			ProductID  int
+			CategoryID int
+		}
+		if err := db.Table("product_categories").Where("product_id IN ?", ids).Find(&links).Error; err != nil {
+			return err
+		}
+		for _, link := range links {
+			categoryIDs[link.ProductID] = append(categoryIDs[link.ProductID], link.CategoryID)
+		}
+		for productID, direct := range categoryIDs {
+			ancestors, err := categoryAncestorIDs(db, direct)
+			if err != nil {
+				return err
+			}
+			categoryIDs[productID] = ancestors
+		}
+	}
+
+	for i := range products {
+		product := &products[i]
+		if rule := matchPriceListRule(priceList.Rules, product, categoryIDs[product.ID], quantity); rule != nil {
+			product.SetCustomerPrice(priceListPrice(rule, product.ListPriceMinor), priceList.ID)
+		}
+	}
+	return nil
+}
+
+// matchPriceListRule memilih aturan untuk produk: aturan produk didahulukan dari aturan kategori,
+// lalu MinQuantity tertinggi yang tidak melebihi quantity, lalu harga terendah.
+// Harga fixed dalam mata uang lain dari produk diabaikan.
+func matchPriceListRule(rules []models.PriceListRule, product *models.Product, categoryIDs []int, quantity int) *models.PriceListRule {
+	var best *models.PriceListRule
+	bestRank := func(rule *models.PriceListRule) bool {
+		if best == nil {
+			return true
+		}
+		if (rule.ProductID != nil) != (best.ProductID != nil) {
+			return rule.ProductID != nil
+		}
+		if rule.MinQuantity != best.MinQuantity {
+			return rule.MinQuantity > best.MinQuantity
+		}
+		return priceListPrice(rule, product.ListPriceMinor) < priceListPrice(best, product.ListPriceMinor)
+	}
+	for i := range rules {
+		rule := &rules[i]
+		if rule.MinQuantity > quantity {
+			continue
+		}
+		switch {
+		case rule.ProductID != nil:
+			if *rule.ProductID != product.ID {
+				continue
+			}
+		case rule.CategoryID != nil:
+			if !slices.Contains(categoryIDs, *rule.CategoryID) {
+				continue
+			}
+		}
+		if rule.Type == models.PriceListFixed && rule.Currency != product.Currency {
+			continue
+		}
+		if bestRank(rule) {
+			best = rule
+		}
+	}
+	return best
+}
+
+// priceListPrice menghitung harga satuan dari aturan. Potongan persen dibulatkan half-up ke minor unit.
+func priceListPrice(rule *models.PriceListRule, listPrice int64) int64 {
+	if rule.Type == models.PriceListFixed {
+		return rule.PriceMinor
+	}
+	percentage, _ := new(big.Rat).SetString(strconv.FormatFloat(rule.Percentage, 'f', -1, 64))
+	value := new(big.Rat).Mul(new(big.Rat).SetInt64(listPrice), percentage)
+	return listPrice - models.RoundHalfUp(value.Quo(value, big.NewRat(100, 1)))
+}
+
+func withPriceListDetails(db *gorm.DB) *gorm.DB {
+	return db.Preload("Assignments", func(db *gorm.DB) *gorm.DB {
+		return db.Order("type, value")
+	}).Preload("Rules", func(db *gorm.DB) *gorm.DB {
+		return db.Order("product_id IS NULL, product_id, category_id, min_quantity")
+	})
+}
+
+func findPriceList(tx *gorm.DB, id int) (*models.PriceList, error) {
+	var priceList models.PriceList
+	if err := tx.First(&priceList, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrPriceListNotFound
+		}
+		return nil, err
+	}
+	return &priceList, nil
+}
+
+func checkUniquePriceListName(tx *gorm.DB, name string, excludeID int) error {
+	var count int64
+	if err := tx.Model(&models.PriceList{}).Where("name = ? AND id <> ?", name, excludeID).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrDuplicatePriceListName
+	}
+	return nil
+}
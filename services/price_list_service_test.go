@@ -0,0 +1,70 @@
+package services
+
+import (
+	"products-api-with-jwt/models"
+	"testing"
+)
+
+func TestPriceListPrice(t *testing.T) {
+	tests := []struct {
+		name      string
+		rule      models.PriceListRule
+		listPrice int64
+		want      int64
+	}{
+		{name: "fixed ignores list price", rule: models.PriceListRule{Type: models.PriceListFixed, PriceMinor: 80000}, listPrice: 100000, want: 80000},
+		{name: "percentage", rule: models.PriceListRule{Type: models.PriceListPercentage, Percentage: 10}, listPrice: 100000, want: 90000},
+		{name: "percentage rounds discount half up", rule: models.PriceListRule{Type: models.PriceListPercentage, Percentage: 12.5}, listPrice: 999, want: 874},
+		{name: "half a minor unit rounds up", rule: models.PriceListRule{Type: models.PriceListPercentage, Percentage: 0.5}, listPrice: 100, want: 99},
+		{name: "full discount", rule: models.PriceListRule{Type: models.PriceListPercentage, Percentage: 100}, listPrice: 4321, want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := priceListPrice(&tt.rule, tt.listPrice); got != tt.want {
+				t.Errorf("priceListPrice() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatchPriceListRule(t *testing.T) {
+	productID, otherProductID, categoryID := 1, 2, 5
+	productRule := func(id int, price int64, minQuantity int) models.PriceListRule {
+		return models.PriceListRule{ID: id, ProductID: &productID, MinQuantity: minQuantity, Type: models.PriceListFixed, PriceMinor: price, Currency: "IDR"}
+	}
+	categoryRule := func(id int, percentage float64, minQuantity int) models.PriceListRule {
+		return models.PriceListRule{ID: id, CategoryID: &categoryID, MinQuantity: minQuantity, Type: models.PriceListPercentage, Percentage: percentage}
+	}
+	product := &models.Product{ID: productID, Currency: "IDR", ListPriceMinor: 100000}
+
+	tests := []struct {
+		name        string
+		rules       []models.PriceListRule
+		categoryIDs []int
+		quantity    int
+		wantID      int // 0 berarti tidak ada aturan yang cocok
+	}{
+		{name: "product rule beats cheaper category rule", rules: []models.PriceListRule{categoryRule(1, 50, 1), productRule(2, 80000, 1)}, categoryIDs: []int{categoryID}, quantity: 1, wantID: 2},
+		{name: "highest tier reached", rules: []models.PriceListRule{productRule(1, 80000, 1), productRule(2, 70000, 10)}, quantity: 10, wantID: 2},
+		{name: "tier not reached", rules: []models.PriceListRule{productRule(1, 80000, 1), productRule(2, 70000, 10)}, quantity: 9, wantID: 1},
+		{name: "category tier", rules: []models.PriceListRule{categoryRule(1, 30, 1), categoryRule(2, 40, 5)}, categoryIDs: []int{categoryID}, quantity: 5, wantID: 2},
+		{name: "cheapest rule in the same tier", rules: []models.PriceListRule{productRule(1, 80000, 1), productRule(2, 75000, 1)}, quantity: 1, wantID: 2},
+		{name: "other product", rules: []models.PriceListRule{{ID: 1, ProductID: &otherProductID, MinQuantity: 1, Type: models.PriceListFixed, PriceMinor: 10, Currency: "IDR"}}, quantity: 1},
+		{name: "product not in category", rules: []models.PriceListRule{categoryRule(1, 30, 1)}, categoryIDs: []int{6}, quantity: 1},
+		{name: "fixed price in another currency", rules: []models.PriceListRule{{ID: 1, ProductID: &productID, MinQuantity: 1, Type: models.PriceListFixed, PriceMinor: 50, Currency: "USD"}}, quantity: 1},
+		{name: "no rules", quantity: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := matchPriceListRule(tt.rules, product, tt.categoryIDs, tt.quantity)
+			switch {
+			case tt.wantID == 0 && got != nil:
+				t.Errorf("matchPriceListRule() = rule %d, want no rule", got.ID)
+			case tt.wantID != 0 && got == nil:
+				t.Errorf("matchPriceListRule() = no rule, want rule %d", tt.wantID)
+			case tt.wantID != 0 && got.ID != tt.wantID:
+				t.Errorf("matchPriceListRule() = rule %d, want rule %d", got.ID, tt.wantID)
+			}
+		})
+	}
+}
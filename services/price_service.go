@@ -0,0 +1,301 @@
+package services
+
+import (
+	"errors"
+	"log"
+	"products-api-with-jwt/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrScheduledPriceNotFound dikembalikan jika harga terjadwal tidak ada di produk yang diminta
+	ErrScheduledPriceNotFound = errors.New("scheduled price not found")
+	// ErrScheduledPriceOverlap dikembalikan jika jadwal baru bertabrakan dengan jadwal lain yang belum selesai
+	ErrScheduledPriceOverlap = errors.New("scheduled price overlaps another pending or active schedule")
+	// ErrScheduledPriceFinished dikembalikan saat membatalkan jadwal yang sudah selesai atau dibatalkan
+	ErrScheduledPriceFinished = errors.New("scheduled price is already completed or cancelled")
+)
+
+type PriceService struct {
+	DB *gorm.DB
+}
+
+// NewPriceService menginisialisasi PriceService baru
+func NewPriceService(db *gorm.DB) *PriceService {
+	return &PriceService{DB: db}
+}
+
+// GetPriceHistory mengambil riwayat perubahan harga produk, terbaru lebih dulu
+func (s *PriceService) GetPriceHistory(productID int) ([]models.PriceChange, error) {
+	if _, err := findProduct(s.DB, productID); err != nil {
+		return nil, err
+	}
+	var changes []models.PriceChange
+	if err := s.DB.Where("product_id = ?", productID).Order("id desc").Find(&changes).Error; err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
+
+// GetScheduledPrices mengambil semua harga terjadwal produk, urut dari yang paling awal berlaku
+func (s *PriceService) GetScheduledPrices(productID int) ([]models.ScheduledPrice, error) {
+	if _, err := findProduct(s.DB, productID); err != nil {
+		return nil, err
+	}
+	var schedules []models.ScheduledPrice
+	if err := s.DB.Where("product_id = ?", productID).Order("effective_from, id").Find(&schedules).Error; err != nil {
+		return nil, err
+	}
+	return schedules, nil
+}
+
+// SchedulePrice menjadwalkan harga baru. Jadwal yang sudah mulai berlaku langsung diterapkan.
+func (s *PriceService) SchedulePrice(productID int, input *models.ScheduledPriceInput, userID int) (*models.ScheduledPrice, error) {
+	schedule := models.ScheduledPrice{
+		ProductID:     productID,
+		EffectiveFrom: input.EffectiveFrom,
+		EffectiveTo:   input.EffectiveTo,
+		Status:        models.ScheduledPricePending,
+		UserID:        userID,
+	}
+
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		product, err := findProduct(tx, productID)
+		if err != nil {
+			return err
+		}
+		schedule.Currency = product.Currency
+		schedule.HargaMinor = models.ToMinor(input.Harga, product.Currency)
+
+		// Dua jadwal bertabrakan jika masing-masing mulai sebelum yang lain berakhir; EffectiveTo kosong berarti tanpa akhir
+		overlap := tx.Model(&models.ScheduledPrice{}).
+			Where("product_id = ? AND status IN ?", productID, []string{models.ScheduledPricePending, models.ScheduledPriceActive}).
+			Where("effective_to IS NULL OR effective_to > ?", schedule.EffectiveFrom)
+		if schedule.EffectiveTo != nil {
+			overlap = overlap.Where("effective_from < ?", *schedule.EffectiveTo)
+		}
+		var count int64
+		if err := overlap.Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return ErrScheduledPriceOverlap
+		}
+		return tx.Create(&schedule).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if !schedule.EffectiveFrom.After(time.Now()) {
+		if _, err := s.ApplyScheduledPrices(time.Now()); err != nil {
+			return nil, err
+		}
+		if err := s.DB.First(&schedule, schedule.ID).Error; err != nil {
+			return nil, err
+		}
+	}
+	return &schedule, nil
+}
+
+// CancelScheduledPrice membatalkan jadwal. Jadwal yang sedang aktif memulihkan harga sebelumnya,
+// kecuali harga produk sudah diubah manual sejak jadwal diterapkan.
+func (s *PriceService) CancelScheduledPrice(productID, scheduleID, userID int) (*models.ScheduledPrice, error) {
+	var schedule models.ScheduledPrice
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("product_id = ?", productID).First(&schedule, scheduleID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrScheduledPriceNotFound
+			}
+			return err
+		}
+
+		switch schedule.Status {
+		case models.ScheduledPriceActive:
+			if err := endScheduledPrice(tx, &schedule, userID); err != nil {
+				return err
+			}
+		case models.ScheduledPricePending:
+		default:
+			return ErrScheduledPriceFinished
+		}
+
+		schedule.Status = models.ScheduledPriceCancelled
+		return tx.Model(&schedule).Update("status", schedule.Status).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &schedule, nil
+}
+
+// ApplyScheduledPrices mengakhiri jadwal aktif yang sudah lewat EffectiveTo lalu menerapkan jadwal
+// pending yang sudah mulai berlaku. Mengembalikan jumlah jadwal yang benar-benar diubah; jadwal produk di trash tidak dihitung.
+func (s *PriceService) ApplyScheduledPrices(now time.Time) (int, error) {
+	processed := 0
+
+	// Akhiri dulu jadwal yang selesai agar jadwal berikutnya yang langsung menyambung
+	// mencatat harga normal sebagai harga sebelumnya
+	var ending []models.ScheduledPrice
+	err := s.DB.Where("status = ? AND effective_to IS NOT NULL AND effective_to <= ?", models.ScheduledPriceActive, now).
+		Order("effective_to").Find(&ending).Error
+	if err != nil {
+		return processed, err
+	}
+	for i := range ending {
+		var updated int64
+		err := s.DB.Transaction(func(tx *gorm.DB) error {
+			if err := endScheduledPrice(tx, &ending[i], 0); err != nil {
+				return err
+			}
+			result := tx.Model(&ending[i]).Update("status", models.ScheduledPriceCompleted)
+			updated = result.RowsAffected
+			return result.Error
+		})
+		if err != nil {
+			return processed, err
+		}
+		processed += int(updated)
+	}
+
+	var starting []models.ScheduledPrice
+	err = s.DB.Where("status = ? AND effective_from <= ?", models.ScheduledPricePending, now).
+		Order("effective_from").Find(&starting).Error
+	if err != nil {
+		return processed, err
+	}
+	for i := range starting {
+		schedule := &starting[i]
+		var updated int64 // Tetap 0 untuk jadwal yang dilewati karena produknya di trash
+		err := s.DB.Transaction(func(tx *gorm.DB) error {
+			product, err := findProduct(tx, schedule.ProductID)
+			if errors.Is(err, ErrProductNotFound) {
+				return nil // Produk di trash, jadwal diterapkan jika produk dipulihkan
+			}
+			if err != nil {
+				return err
+			}
+
+			// Jendela yang sudah lewat seluruhnya (misalnya server mati) tidak diterapkan lagi
+			if schedule.EffectiveTo != nil && !schedule.EffectiveTo.After(now) {
+				result := tx.Model(schedule).Update("status", models.ScheduledPriceCompleted)
+				updated = result.RowsAffected
+				return result.Error
+			}
+
+			previous := product.HargaMinor
+			if err := setProductPrice(tx, product, schedule.HargaMinor, models.PriceChangeScheduled, &schedule.ID, 0); err != nil {
+				return err
+			}
+			// Jadwal tanpa akhir tidak perlu dipulihkan, jadi langsung selesai
+			status := models.ScheduledPriceCompleted
+			if schedule.EffectiveTo != nil {
+				status = models.ScheduledPriceActive
+			}
+			result := tx.Model(schedule).Updates(map[string]interface{}{"status": status, "previous_harga_minor": previous})
+			updated = result.RowsAffected
+			return result.Error
+		})
+		if err != nil {
+			return processed, err
+		}
+		processed += int(updated)
+	}
+	return processed, nil
+}
+
+// StartPriceScheduler menjalankan ApplyScheduledPrices secara berkala di background
+func (s *PriceService) StartPriceScheduler(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			processed, err := s.ApplyScheduledPrices(time.Now())
+			if err != nil {
+				log.Printf("Failed to apply scheduled prices: %v", err)
+				continue
+			}
+			if processed > 0 {
+				log.Printf("Applied %d scheduled price changes", processed)
+			}
+		}
+	}()
+}
+
+// endScheduledPrice memulihkan harga sebelum jadwal aktif diterapkan. Jika harga produk sudah
+// diubah manual sejak itu, perubahan manual dipertahankan.
+func endScheduledPrice(tx *gorm.DB, schedule *models.ScheduledPrice, userID int) error {
+	product, err := findProduct(tx, schedule.ProductID)
+	if errors.Is(err, ErrProductNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if schedule.PreviousHargaMinor == nil || product.HargaMinor != schedule.HargaMinor {
+		return nil
+	}
+	return setProductPrice(tx, product, *schedule.PreviousHargaMinor, models.PriceChangeScheduleEnded, &schedule.ID, userID)
+}
+
+// setProductPrice mengubah harga produk (minor unit) dan mencatatnya di riwayat harga
+func setProductPrice(tx *gorm.DB, product *models.Product, hargaMinor int64, source string, scheduleID *int, userID int) error {
+	oldHarga := product.HargaMinor
+	if err := tx.Model(product).Update("harga_minor", hargaMinor).Error; err != nil {
+		return err
+	}
+	product.HargaMinor = hargaMinor
+	return recordPriceChange(tx, product, oldHarga, source, scheduleID, userID)
+}
+
+// recordPriceChange mencatat perubahan dari oldHarga ke harga produk saat ini. Harga yang tidak berubah tidak dicatat.
+func recordPriceChange(tx *gorm.DB, product *models.Product, oldHarga int64, source string, scheduleID *int, userID int) error {
+	if oldHarga == product.HargaMinor && source != models.PriceChangeInitial {
+		return nil
+	}
+	return tx.Create(&models.PriceChange{
+		ProductID:        product.ID,
+		OldHargaMinor:    oldHarga,
+		NewHargaMinor:    product.HargaMinor,
+		Currency:         product.Currency,
+		Source:           source,
+		ScheduledPriceID: scheduleID,
+		UserID:           userID,
+	}).Error
+}
+
+// applyEffectivePrices mengisi EffectivePrice dengan harga terjadwal yang jendelanya mencakup saat ini.
+// Ini membuat harga yang ditampilkan sudah benar meski scheduler belum sempat berjalan.
+// Harga dan stok bundle dihitung dari komponennya di sini juga, lewat applyBundles.
+func applyEffectivePrices(db *gorm.DB, products []models.Product) error {
+	if len(products) == 0 {
+		return nil
+	}
+	ids := make([]int, len(products))
+	for i, product := range products {
+		ids[i] = product.ID
+	}
+
+	now := time.Now()
+	var schedules []models.ScheduledPrice
+	err := db.Where("product_id IN ? AND status IN ?", ids, []string{models.ScheduledPricePending, models.ScheduledPriceActive}).
+		Where("effective_from <= ? AND (effective_to IS NULL OR effective_to > ?)", now, now).
+		Order("effective_from").Find(&schedules).Error
+	if err != nil {
+		return err
+	}
+
+	prices := make(map[int]int64, len(schedules))
+	for _, schedule := range schedules {
+		prices[schedule.ProductID] = schedule.HargaMinor
+	}
+	for i := range products {
+		if harga, ok := prices[products[i].ID]; ok {
+			products[i].SetEffectivePrice(harga)
+		}
+	}
+	return applyBundles(db, products)
+}
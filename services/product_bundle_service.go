@@ -0,0 +1,294 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"products-api-with-jwt/models"
+	"strconv"
+
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrBundleStock dikembalikan jika stok bundle diubah langsung, padahal stoknya berasal dari komponen
+	ErrBundleStock = errors.New("bundle stock comes from its components, only sale and return are allowed")
+	// ErrInvalidBundleComponent dikembalikan jika komponen tidak ada, berupa bundle, atau bundle itu sendiri
+	ErrInvalidBundleComponent = errors.New("components must be existing non-bundle products other than the bundle itself")
+	// ErrBundleCurrencyMismatch dikembalikan jika pricing components dipakai dengan komponen bermata uang lain
+	ErrBundleCurrencyMismatch = errors.New("components must use the bundle's currency when pricing is components")
+	// ErrBundleNotAllowed dikembalikan jika produk masih punya stok sendiri atau dipakai sebagai komponen bundle lain
+	ErrBundleNotAllowed = errors.New("only products without stock of their own that are not a component of another bundle can become bundles")
+	// ErrBundleReorderPoint dikembalikan jika reorder point diisi untuk bundle, yang tidak punya stok sendiri
+	ErrBundleReorderPoint = errors.New("bundles have no stock of their own, set reorder_point on the components instead")
+	// ErrBundleInUse dikembalikan jika komponen bundle diubah saat masih ada reservation yang ditahan
+	ErrBundleInUse = errors.New("bundle has held reservations")
+	// ErrNotBundle dikembalikan jika produk yang diminta bukan bundle
+	ErrNotBundle = errors.New("product is not a bundle")
+)
+
+// stockLine adalah produk dan jumlah stok yang benar-benar bergerak
+type stockLine struct {
+	ProductID int
+	Quantity  int
+}
+
+// SetBundle menjadikan produk sebuah bundle atau mengganti komponen dan cara penetapan harganya
+func (s *ProductService) SetBundle(id int, input *models.BundleInput) (*models.Product, error) {
+	var product *models.Product
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		if product, err = findProduct(tx, id); err != nil {
+			return err
+		}
+		if product.Type != models.ProductBundle {
+			var components int64
+			if err := tx.Model(&models.BundleComponent{}).Where("component_id = ?", id).Count(&components).Error; err != nil {
+				return err
+			}
+			if product.Stok != 0 || product.Reserved != 0 || components > 0 {
+				return ErrBundleNotAllowed
+			}
+		}
+		if err := checkBundleReservations(tx, id); err != nil {
+			return err
+		}
+
+		quantities := make(map[int]int, len(input.Components))
+		ids := make([]int, 0, len(input.Components))
+		for _, component := range input.Components {
+			if _, ok := quantities[component.ProductID]; !ok {
+				ids = append(ids, component.ProductID)
+			}
+			quantities[component.ProductID] += component.Quantity
+		}
+		var parts []models.Product
+		if err := tx.Where("id IN ? AND id <> ? AND type = ?", ids, id, models.ProductSimple).Find(&parts).Error; err != nil {
+			return err
+		}
+		if len(parts) != len(ids) {
+			return ErrInvalidBundleComponent
+		}
+		if input.Pricing == models.BundlePricingComponents {
+			for _, part := range parts {
+				if part.Currency != product.Currency {
+					return ErrBundleCurrencyMismatch
+				}
+			}
+		}
+
+		if err := tx.Where("bundle_id = ?", id).Delete(&models.BundleComponent{}).Error; err != nil {
+			return err
+		}
+		components := make([]models.BundleComponent, len(ids))
+		for i, componentID := range ids {
+			components[i] = models.BundleComponent{BundleID: id, ComponentID: componentID, Quantity: quantities[componentID]}
+		}
+		if err := tx.Create(&components).Error; err != nil {
+			return err
+		}
+
+		// Bundle tidak punya stok sendiri, low stock dipantau pada komponennya
+		product.Type = models.ProductBundle
+		product.BundlePricing = input.Pricing
+		product.BundleDiscountPercent = 0
+		if input.Pricing == models.BundlePricingComponents {
+			product.BundleDiscountPercent = input.DiscountPercent
+		}
+		product.ReorderPoint = 0
+		product.LowStockAlerted = false
+		return tx.Model(product).Select("type", "bundle_pricing", "bundle_discount_percent", "reorder_point", "low_stock_alerted").Updates(product).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.bundleWithComponents(id)
+}
+
+// RemoveBundle menjadikan bundle produk biasa lagi. Stok komponen tidak berubah.
+func (s *ProductService) RemoveBundle(id int) (*models.Product, error) {
+	var product *models.Product
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		if product, err = findProduct(tx, id); err != nil {
+			return err
+		}
+		if product.Type != models.ProductBundle {
+			return ErrNotBundle
+		}
+		if err := checkBundleReservations(tx, id); err != nil {
+			return err
+		}
+		if err := tx.Where("bundle_id = ?", id).Delete(&models.BundleComponent{}).Error; err != nil {
+			return err
+		}
+		product.Type = models.ProductSimple
+		product.BundlePricing = ""
+		product.BundleDiscountPercent = 0
+		return tx.Model(product).Select("type", "bundle_pricing", "bundle_discount_percent").Updates(product).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.productWithPrices(product)
+}
+
+// bundleWithComponents mengambil bundle beserta komponennya, dengan harga dan stok yang sudah dihitung
+func (s *ProductService) bundleWithComponents(id int) (*models.Product, error) {
+	var product models.Product
+	if err := s.DB.Preload("Components.Component").First(&product, id).Error; err != nil {
+		return nil, err
+	}
+	return s.productWithPrices(&product)
+}
+
+// checkBundleReservations menolak perubahan komponen selama masih ada reservation bundle yang ditahan,
+// karena reservation itu melepas stok komponen sesuai komposisi saat dibuat. Order pending atau paid
+// tidak perlu dicek: cancel dan refund membalik pergerakan sale yang tercatat, bukan komposisi saat ini.
+func checkBundleReservations(tx *gorm.DB, bundleID int) error {
+	var held int64
+	if err := tx.Model(&models.Reservation{}).Where("product_id = ? AND status = ?", bundleID, models.ReservationHeld).Count(&held).Error; err != nil {
+		return err
+	}
+	if held > 0 {
+		return ErrBundleInUse
+	}
+	return nil
+}
+
+// stockLines menguraikan quantity unit produk menjadi stok yang benar-benar bergerak.
+// Produk biasa menghasilkan dirinya sendiri, bundle menghasilkan komponennya dikali quantity.
+func stockLines(tx *gorm.DB, productID, quantity int) ([]stockLine, error) {
+	var product models.Product
+	if err := tx.Select("id", "type").First(&product, productID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrProductNotFound
+		}
+		return nil, err
+	}
+	if product.Type != models.ProductBundle {
+		return []stockLine{{ProductID: productID, Quantity: quantity}}, nil
+	}
+
+	var components []models.BundleComponent
+	if err := tx.Where("bundle_id = ?", productID).Order("component_id").Find(&components).Error; err != nil {
+		return nil, err
+	}
+	lines := make([]stockLine, len(components))
+	for i, component := range components {
+		lines[i] = stockLine{ProductID: component.ComponentID, Quantity: component.Quantity * quantity}
+	}
+	return lines, nil
+}
+
+// postProductMovement seperti postStockMovement, tetapi pergerakan bundle diteruskan ke setiap komponennya
+// di dalam transaksi yang sama, sehingga semua komponen berkurang atau tidak sama sekali
+func (s *ProductService) postProductMovement(tx *gorm.DB, movement models.StockMovement) ([]models.StockMovement, error) {
+	sign := 1
+	if movement.Quantity < 0 {
+		sign = -1
+	}
+	lines, err := stockLines(tx, movement.ProductID, movement.Quantity*sign)
+	if err != nil {
+		return nil, err
+	}
+	if len(lines) == 1 && lines[0].ProductID == movement.ProductID {
+		return s.postStockMovement(tx, movement)
+	}
+
+	var movements []models.StockMovement
+	for _, line := range lines {
+		part := movement
+		part.ProductID = line.ProductID
+		part.Quantity = line.Quantity * sign
+		if part.Reference == "" {
+			part.Reference = fmt.Sprintf("bundle:%d", movement.ProductID)
+		}
+		posted, err := s.postStockMovement(tx, part)
+		if err != nil {
+			return nil, err
+		}
+		movements = append(movements, posted...)
+	}
+	return movements, nil
+}
+
+// movedProductIDs mengembalikan ID produk yang stoknya bergerak, tanpa duplikat
+func movedProductIDs(movements []models.StockMovement) []int {
+	ids := make([]int, len(movements))
+	for i, movement := range movements {
+		ids[i] = movement.ProductID
+	}
+	return uniqueInts(ids)
+}
+
+// applyBundles menghitung stok bundle dari komponennya (jumlah unit bundle yang bisa disusun) dan,
+// untuk pricing components, harga berlakunya dari harga berlaku komponen dikurangi diskon bundle
+func applyBundles(db *gorm.DB, products []models.Product) error {
+	var bundleIDs []int
+	for _, product := range products {
+		if product.Type == models.ProductBundle {
+			bundleIDs = append(bundleIDs, product.ID)
+		}
+	}
+	if len(bundleIDs) == 0 {
+		return nil
+	}
+
+	var components []models.BundleComponent
+	if err := db.Where("bundle_id IN ?", bundleIDs).Find(&components).Error; err != nil {
+		return err
+	}
+	componentIDs := make([]int, 0, len(components))
+	for _, component := range components {
+		componentIDs = append(componentIDs, component.ComponentID)
+	}
+	// Komponen yang sudah dihapus tidak ikut dimuat, sehingga bundle-nya tidak tersedia
+	var parts []models.Product
+	if err := db.Where("id IN ?", componentIDs).Find(&parts).Error; err != nil {
+		return err
+	}
+	if err := applyEffectivePrices(db, parts); err != nil {
+		return err
+	}
+	partsByID := make(map[int]models.Product, len(parts))
+	for _, part := range parts {
+		partsByID[part.ID] = part
+	}
+	byBundle := make(map[int][]models.BundleComponent, len(bundleIDs))
+	for _, component := range components {
+		byBundle[component.BundleID] = append(byBundle[component.BundleID], component)
+	}
+
+	for i := range products {
+		if products[i].Type != models.ProductBundle {
+			continue
+		}
+		stok, available := 0, 0
+		var total int64
+		for j, component := range byBundle[products[i].ID] {
+			part, ok := partsByID[component.ComponentID]
+			if !ok {
+				stok, available = 0, 0
+				break
+			}
+			if j == 0 {
+				stok, available = part.Stok/component.Quantity, part.Available/component.Quantity
+			} else {
+				stok, available = min(stok, part.Stok/component.Quantity), min(available, part.Available/component.Quantity)
+			}
+			total += part.EffectivePriceMinor * int64(component.Quantity)
+		}
+		products[i].Stok = stok
+		products[i].Available = max(available, 0)
+
+		if products[i].BundlePricing == models.BundlePricingComponents {
+			discount, _ := new(big.Rat).SetString(strconv.FormatFloat(products[i].BundleDiscountPercent, 'f', -1, 64))
+			price := new(big.Rat).SetInt64(total)
+			price.Mul(price, new(big.Rat).Sub(big.NewRat(100, 1), discount))
+			price.Quo(price, big.NewRat(100, 1))
+			products[i].SetEffectivePrice(models.RoundHalfUp(price))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+package services
+
+import (
+	"errors"
+	"products-api-with-jwt/models"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrDuplicateSKU dikembalikan jika SKU sudah dipakai produk atau variant lain
+	ErrDuplicateSKU = errors.New("sku already exists")
+	// ErrDuplicateBarcode dikembalikan jika barcode sudah dipakai produk atau variant lain
+	ErrDuplicateBarcode = errors.New("barcode already exists")
+)
+
+// LookupProduct mencari produk berdasarkan SKU atau barcode, baik milik produk maupun variantnya.
+// Pencarian dilakukan dalam satu query: kode dicocokkan di kedua tabel lalu di-join ke produk dan variant.
+// Draft hanya ditemukan jika includeDrafts true. Harga mengikuti price list user dengan userID.
+func (s *ProductService) LookupProduct(column, code string, includeDrafts bool, userID int) (*models.ProductLookup, error) {
+	if column != "sku" && column != "barcode" {
+		return nil, errors.New("lookup column must be sku or barcode")
+	}
+
+	matches := s.DB.Raw("SELECT id AS product_id, NULL AS variant_id FROM products WHERE "+column+" = ? "+
+		"UNION ALL SELECT product_id, id FROM product_variants WHERE "+column+" = ?", code, code)
+
+	db := s.DB.Table("(?) AS matches", matches).InnerJoins("Product").Joins("Variant")
+	if !includeDrafts {
+		db = db.Where("Product.status <> ?", models.ProductDraft)
+	}
+
+	var lookup models.ProductLookup
+	err := db.Take(&lookup).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrProductNotFound
+		}
+		return nil, err
+	}
+	if lookup.VariantID == nil {
+		lookup.Variant = nil
+	}
+	// Hook AfterFind tidak dijalankan untuk relasi yang di-join
+	if err := lookup.Product.AfterFind(s.DB); err != nil {
+		return nil, err
+	}
+	products := []models.Product{lookup.Product}
+	if err := applyEffectivePrices(s.DB, products); err != nil {
+		return nil, err
+	}
+	if err := applyUserPriceList(s.DB, products, userID); err != nil {
+		return nil, err
+	}
+	lookup.Product = products[0]
+	return &lookup, nil
+}
+
+// checkProductCodes memastikan SKU dan barcode belum dipakai produk atau variant lain.
+// SKU dan barcode berbagi namespace antara produk dan variant agar lookup selalu menghasilkan satu produk.
+func checkProductCodes(tx *gorm.DB, sku, barcode *string, excludeProductID, excludeVariantID int) error {
+	codes := []struct {
+		column string
+		value  *string
+		err    error
+	}{
+		{"sku", sku, ErrDuplicateSKU},
+		{"barcode", barcode, ErrDuplicateBarcode},
+	}
+	for _, code := range codes {
+		if code.value == nil {
+			continue
+		}
+		var count int64
+		if err := tx.Unscoped().Model(&models.Product{}).Where(code.column+" = ? AND id <> ?", *code.value, excludeProductID).Count(&count).Error; err != nil {
+			return err
+		}
+		if count == 0 {
+			if err := tx.Model(&models.ProductVariant{}).Where(code.column+" = ? AND id <> ?", *code.value, excludeVariantID).Count(&count).Error; err != nil {
+				return err
+			}
+		}
+		if count > 0 {
+			return code.err
+		}
+	}
+	return nil
+}
+
+// optionalCode mengubah SKU atau barcode kosong menjadi nil agar tidak bentrok dengan unique index
+func optionalCode(code string) *string {
+	if code = strings.TrimSpace(code); code == "" {
+		return nil
+	}
+	return &code
+}
@@ -0,0 +1,185 @@
+package services
+
+import (
+	"errors"
+	"products-api-with-jwt/models"
+	"sort"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// similarSuggestionLimit adalah jumlah maksimum saran produk mirip
+	similarSuggestionLimit = 5
+	// similarPriceRange adalah selisih harga relatif yang masih dianggap satu rentang harga
+	similarPriceRange = 0.25
+)
+
+var (
+	// ErrInvalidRelatedProduct dikembalikan jika produk di-link ke dirinya sendiri
+	ErrInvalidRelatedProduct = errors.New("a product cannot be related to itself")
+	// ErrRelatedProductNotFound dikembalikan jika produk yang akan di-link tidak ada
+	ErrRelatedProductNotFound = errors.New("related product not found")
+	// ErrDuplicateRelation dikembalikan jika link dengan jenis yang sama sudah ada
+	ErrDuplicateRelation = errors.New("products are already related with this type")
+	// ErrRelationNotFound dikembalikan jika link yang akan dihapus tidak ada
+	ErrRelationNotFound = errors.New("relation not found")
+)
+
+// AddRelatedProduct menambah link satu arah dari produk ke produk lain
+func (s *ProductService) AddRelatedProduct(id int, input *models.RelatedProductInput) (*models.ProductRelation, error) {
+	if input.ProductID == id {
+		return nil, ErrInvalidRelatedProduct
+	}
+
+	relation := models.ProductRelation{ProductID: id, RelatedID: input.ProductID, Type: input.Type}
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		if _, err := findProduct(tx, id); err != nil {
+			return err
+		}
+		related, err := findProduct(tx, input.ProductID)
+		if errors.Is(err, ErrProductNotFound) {
+			return ErrRelatedProductNotFound
+		}
+		if err != nil {
+			return err
+		}
+
+		var count int64
+		if err := tx.Model(&models.ProductRelation{}).Where("product_id = ? AND related_id = ? AND type = ?", id, input.ProductID, input.Type).Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return ErrDuplicateRelation
+		}
+		if err := tx.Create(&relation).Error; err != nil {
+			return err
+		}
+		relation.Related = related
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &relation, nil
+}
+
+// RemoveRelatedProduct menghapus link ke produk lain. Tanpa type, semua jenis link ke produk itu dihapus.
+func (s *ProductService) RemoveRelatedProduct(id int, input *models.RelatedProductRemoveInput) error {
+	if _, err := findProduct(s.DB, id); err != nil {
+		return err
+	}
+	query := s.DB.Where("product_id = ? AND related_id = ?", id, input.ProductID)
+	if input.Type != "" {
+		query = query.Where("type = ?", input.Type)
+	}
+	result := query.Delete(&models.ProductRelation{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrRelationNotFound
+	}
+	return nil
+}
+
+// dropHiddenRelations membuang link ke produk yang sudah dihapus atau tidak terlihat oleh user (draft)
+func dropHiddenRelations(products []models.Product) {
+	for i := range products {
+		if products[i].Related == nil {
+			continue
+		}
+		visible := products[i].Related[:0]
+		for _, relation := range products[i].Related {
+			if relation.Related != nil {
+				visible = append(visible, relation)
+			}
+		}
+		products[i].Related = visible
+	}
+}
+
+// similarProducts menyarankan produk yang punya kategori atau tag yang sama, diurutkan berdasarkan skor.
+// Produk yang sudah di-link tidak disarankan lagi. Harga dibandingkan hanya jika mata uangnya sama.
+func (s *ProductService) similarProducts(product *models.Product, includeDrafts bool) ([]models.ProductSuggestion, error) {
+	type shared struct {
+		ProductID int
+		Total     int
+	}
+	var categories, tags []shared
+	err := s.DB.Table("product_categories").
+		Select("product_id, COUNT(*) AS total").
+		Where("category_id IN (SELECT category_id FROM product_categories WHERE product_id = ?) AND product_id <> ?", product.ID, product.ID).
+		Group("product_id").Scan(&categories).Error
+	if err != nil {
+		return nil, err
+	}
+	err = s.DB.Table("product_tags").
+		Select("product_id, COUNT(*) AS total").
+		Where("tag_id IN (SELECT tag_id FROM product_tags WHERE product_id = ?) AND product_id <> ?", product.ID, product.ID).
+		Group("product_id").Scan(&tags).Error
+	if err != nil {
+		return nil, err
+	}
+
+	suggestions := make(map[int]*models.ProductSuggestion)
+	suggestion := func(id int) *models.ProductSuggestion {
+		if suggestions[id] == nil {
+			suggestions[id] = &models.ProductSuggestion{}
+		}
+		return suggestions[id]
+	}
+	for _, category := range categories {
+		suggestion(category.ProductID).SharedCategories = category.Total
+	}
+	for _, tag := range tags {
+		suggestion(tag.ProductID).SharedTags = tag.Total
+	}
+	result := []models.ProductSuggestion{}
+	if len(suggestions) == 0 {
+		return result, nil
+	}
+
+	ids := make([]int, 0, len(suggestions))
+	for id := range suggestions {
+		ids = append(ids, id)
+	}
+	query := s.DB.Where("id IN ?", ids).
+		Where("id NOT IN (SELECT related_id FROM product_relations WHERE product_id = ?)", product.ID)
+	if !includeDrafts {
+		query = query.Where("status <> ?", models.ProductDraft)
+	}
+	var candidates []models.Product
+	if err := query.Find(&candidates).Error; err != nil {
+		return nil, err
+	}
+	if err := applyEffectivePrices(s.DB, candidates); err != nil {
+		return nil, err
+	}
+
+	base := float64(product.EffectivePriceMinor)
+	for i := range candidates {
+		candidate := suggestions[candidates[i].ID]
+		candidate.Product = &candidates[i]
+		if candidates[i].Currency == product.Currency {
+			diff := float64(candidates[i].EffectivePriceMinor) - base
+			candidate.InPriceRange = diff <= base*similarPriceRange && -diff <= base*similarPriceRange
+		}
+		candidate.Score = 2*candidate.SharedCategories + candidate.SharedTags
+		if candidate.InPriceRange {
+			candidate.Score++
+		}
+		result = append(result, *candidate)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Score != result[j].Score {
+			return result[i].Score > result[j].Score
+		}
+		return result[i].Product.ID < result[j].Product.ID
+	})
+	if len(result) > similarSuggestionLimit {
+		result = result[:similarSuggestionLimit]
+	}
+	return result, nil
+}
@@ -2,86 +2,573 @@ package services
 
 import (
 	"errors"
+	"log"
 	"products-api-with-jwt/models"
+	"products-api-with-jwt/notifiers"
+	"products-api-with-jwt/storage"
+	"strings"
+	"time"
 
 	"gorm.io/gorm"
 )
 
+var (
+	// ErrProductNotFound dikembalikan jika produk dengan ID yang diminta tidak ada
+	ErrProductNotFound = errors.New("product not found")
+	// ErrDuplicateProductName dikembalikan jika nama produk sudah dipakai di department yang sama
+	ErrDuplicateProductName = errors.New("product name already exists in this department")
+)
+
 type ProductService struct {
-	DB *gorm.DB
+	DB                *gorm.DB
+	LowStockNotifiers []notifiers.LowStockNotifier
+	ImageStorage      storage.Storage // Dipakai purge untuk menghapus file gambar produk
 }
 
-func NewProductService(db *gorm.DB) *ProductService {
-	return &ProductService{DB: db}
+// NewProductService menginisialisasi ProductService baru dengan notifier untuk alert low-stock
+func NewProductService(db *gorm.DB, lowStockNotifiers ...notifiers.LowStockNotifier) *ProductService {
+	return &ProductService{DB: db, LowStockNotifiers: lowStockNotifiers}
 }
 
 // GetAllProducts mengambil semua produk dari database
-func (s *ProductService) GetAllProducts() ([]models.Product, error) {
+func (s *ProductService) GetAllProducts(query *models.ProductQuery) ([]models.Product, error) {
+	db, err := s.withFilters(s.DB, query)
+	if err != nil {
+		return nil, err
+	}
+
 	var products []models.Product
-	if err := s.DB.Find(&products).Error; err != nil {
+	if err := s.withIncludes(db, query).Find(&products).Error; err != nil {
 		return nil, err
 	}
+	dropHiddenRelations(products)
+	if err := applyEffectivePrices(s.DB, products); err != nil {
+		return nil, err
+	}
+	if query != nil {
+		if err := applyUserPriceList(s.DB, products, query.UserID); err != nil {
+			return nil, err
+		}
+	}
+	if query != nil && query.Currency != "" {
+		if err := applyDisplayCurrency(s.DB, products, query.Currency); err != nil {
+			return nil, err
+		}
+	}
+	if query != nil {
+		if err := applyTranslations(s.DB, products, query.Locale); err != nil {
+			return nil, err
+		}
+	}
 	return products, nil
 }
 
+// GetProductDetail mengambil produk berdasarkan ID beserta data terkait yang diminta
+func (s *ProductService) GetProductDetail(id int, query *models.ProductQuery) (*models.Product, error) {
+	if query == nil {
+		query = &models.ProductQuery{}
+	}
+	db := s.DB
+	if !query.IncludeDrafts {
+		db = db.Where("status <> ?", models.ProductDraft)
+	}
+
+	var product models.Product
+	if err := s.withIncludes(db, query).First(&product, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrProductNotFound
+		}
+		return nil, err
+	}
+	products := []models.Product{product}
+	dropHiddenRelations(products)
+	if err := applyEffectivePrices(s.DB, products); err != nil {
+		return nil, err
+	}
+	if err := applyUserPriceList(s.DB, products, query.UserID); err != nil {
+		return nil, err
+	}
+	if query.Includes("suggestions") {
+		var err error
+		if products[0].Suggestions, err = s.similarProducts(&products[0], query.IncludeDrafts); err != nil {
+			return nil, err
+		}
+	}
+	if query.Currency != "" {
+		if err := applyDisplayCurrency(s.DB, products, query.Currency); err != nil {
+			return nil, err
+		}
+	}
+	if err := applyTranslations(s.DB, products, query.Locale); err != nil {
+		return nil, err
+	}
+	return &products[0], nil
+}
+
+// withFilters menambahkan filter listing produk dari query string
+func (s *ProductService) withFilters(db *gorm.DB, query *models.ProductQuery) (*gorm.DB, error) {
+	if query == nil {
+		return db, nil
+	}
+
+	if len(query.Statuses) > 0 {
+		db = db.Where("status IN ?", query.Statuses)
+	}
+	if !query.IncludeDrafts {
+		db = db.Where("status <> ?", models.ProductDraft)
+	}
+
+	if query.CategoryID != 0 {
+		categoryIDs := []int{query.CategoryID}
+		if query.IncludeDescendants {
+			var err error
+			if categoryIDs, err = categoryDescendantIDs(s.DB, query.CategoryID); err != nil {
+				return nil, err
+			}
+		}
+		db = db.Where("id IN (SELECT product_id FROM product_categories WHERE category_id IN ?)", categoryIDs)
+	}
+
+	if len(query.Tags) > 0 {
+		tagged := s.DB.Table("product_tags").
+			Select("product_tags.product_id").
+			Joins("JOIN tags ON tags.id = product_tags.tag_id").
+			Where("tags.name IN ?", query.Tags)
+		if query.MatchAllTags {
+			tagged = tagged.Group("product_tags.product_id").Having("COUNT(DISTINCT tags.id) = ?", len(query.Tags))
+		}
+		db = db.Where("id IN (?)", tagged)
+	}
+
+	if len(query.Attributes) > 0 {
+		return withAttributeFilters(db, s.DB, query.Attributes)
+	}
+	return db, nil
+}
+
+// withIncludes menambahkan preload untuk data terkait yang diminta lewat ?include=
+func (s *ProductService) withIncludes(db *gorm.DB, query *models.ProductQuery) *gorm.DB {
+	if query.Includes("categories") {
+		db = db.Preload("Categories")
+	}
+	if query.Includes("variants") {
+		db = db.Preload("Options", func(db *gorm.DB) *gorm.DB {
+			return db.Order("position")
+		}).Preload("Variants", func(db *gorm.DB) *gorm.DB {
+			return db.Order("id")
+		})
+	}
+	if query.Includes("components") {
+		db = db.Preload("Components.Component")
+	}
+	if query.Includes("related") {
+		db = db.Preload("Related", func(db *gorm.DB) *gorm.DB {
+			return db.Order("type, id")
+		}).Preload("Related.Related", func(db *gorm.DB) *gorm.DB {
+			if !query.IncludeDrafts {
+				db = db.Where("status <> ?", models.ProductDraft)
+			}
+			return db
+		})
+	}
+	if query.Includes("tags") {
+		db = db.Preload("Tags", func(db *gorm.DB) *gorm.DB {
+			return db.Order("name")
+		})
+	}
+	if query.Includes("locations") {
+		db = db.Preload("Locations", func(db *gorm.DB) *gorm.DB {
+			return db.Where("variant_id IS NULL").Order("warehouse_id")
+		}).Preload("Locations.Warehouse")
+	}
+	return db
+}
+
 // GetProductByID mengambil produk berdasarkan ID
 func (s *ProductService) GetProductByID(id int) (*models.Product, error) {
 	var product models.Product
 	if err := s.DB.First(&product, id).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("product not found")
+			return nil, ErrProductNotFound
 		}
 		return nil, err
 	}
 	return &product, nil
 }
 
-// CreateProduct menambah produk baru ke database
-func (s *ProductService) CreateProduct(product *models.Product) (models.Product, error) {
-	// Menyimpan produk baru ke database
-	if err := s.DB.Create(product).Error; err != nil {
+// CreateProduct menambah produk baru ke database sebagai draft. Stok awal dicatat sebagai receipt di ledger.
+func (s *ProductService) CreateProduct(input *models.CreateProductInput, userID int) (models.Product, error) {
+	currency := input.Currency
+	if currency == "" {
+		currency = models.DefaultCurrency
+	}
+	product := models.Product{
+		NamaProduk:       strings.TrimSpace(input.NamaProduk),
+		SKU:              optionalCode(input.SKU),
+		Barcode:          optionalCode(input.Barcode),
+		Deskripsi:        input.Deskripsi,
+		HargaMinor:       models.ToMinor(input.Harga, currency),
+		Currency:         currency,
+		Status:           models.ProductDraft,
+		Type:             models.ProductSimple,
+		Department:       input.Department,
+		ReorderPoint:     input.ReorderPoint,
+		TaxClassID:       input.TaxClassID,
+		PriceIncludesTax: input.PriceIncludesTax,
+	}
+
+	if err := s.checkUniqueName(s.DB, product.NamaProduk, product.Department, 0); err != nil {
+		return models.Product{}, err
+	}
+	if product.TaxClassID != nil {
+		if _, err := findTaxClass(s.DB, *product.TaxClassID); err != nil {
+			return models.Product{}, err
+		}
+	}
+
+	categories, err := findCategories(s.DB, input.CategoryIDs)
+	if err != nil {
+		return models.Product{}, err
+	}
+	product.Categories = categories
+
+	definitions, err := productAttributeDefinitions(s.DB, input.CategoryIDs)
+	if err != nil {
+		return models.Product{}, err
+	}
+	if product.Attributes, err = validateAttributes(definitions, input.Attributes); err != nil {
+		return models.Product{}, err
+	}
+
+	// Menyimpan produk baru, kategorinya, dan stok awalnya dalam satu transaksi
+	err = s.DB.Transaction(func(tx *gorm.DB) error {
+		if err := checkProductCodes(tx, product.SKU, product.Barcode, 0, 0); err != nil {
+			return err
+		}
+		if err := tx.Omit("Categories.*").Create(&product).Error; err != nil {
+			return err
+		}
+		if err := recordPriceChange(tx, &product, 0, models.PriceChangeInitial, nil, userID); err != nil {
+			return err
+		}
+		if input.Stok == 0 {
+			return nil
+		}
+
+		movement := models.StockMovement{
+			ProductID: product.ID,
+			Type:      models.StockMovementReceipt,
+			Quantity:  input.Stok,
+			UserID:    userID,
+			Reason:    "initial stock",
+		}
+		movements, err := s.postStockMovement(tx, movement)
+		if err != nil {
+			return err
+		}
+		product.Stok = movements[len(movements)-1].BalanceAfter
+		return nil
+	})
+	product.Available = product.Stok - product.Reserved
+	product.Harga = models.FromMinor(product.HargaMinor, product.Currency)
+	product.SetEffectivePrice(product.HargaMinor)
+	if err != nil {
 		return models.Product{}, err // Kembalikan error jika terjadi kesalahan
 	}
-	return *product, nil // Kembalikan produk yang baru dibuat
+	s.evaluateLowStock(product.ID)
+	return product, nil // Kembalikan produk yang baru dibuat
 }
 
-// UpdateProduct memperbarui hanya field yang disediakan dalam permintaan
-func (s *ProductService) UpdateProduct(id int, updatedProduct *models.Product) (*models.Product, error) {
+// UpdateProduct memperbarui hanya field yang disediakan dalam permintaan. Perubahan harga dicatat di riwayat harga.
+func (s *ProductService) UpdateProduct(id int, input *models.UpdateProductInput, userID int) (*models.Product, error) {
 	var product models.Product
 
 	// Cari produk berdasarkan ID
 	if err := s.DB.First(&product, id).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("product not found")
+			return nil, ErrProductNotFound
 		}
 		return nil, err
 	}
 
 	// Perbarui hanya field yang disediakan
-	if updatedProduct.NamaProduk != "" {
-		product.NamaProduk = updatedProduct.NamaProduk
+	if input.NamaProduk != nil {
+		product.NamaProduk = strings.TrimSpace(*input.NamaProduk)
+	}
+	if input.SKU != nil {
+		product.SKU = optionalCode(*input.SKU)
+	}
+	if input.Barcode != nil {
+		product.Barcode = optionalCode(*input.Barcode)
 	}
-	if updatedProduct.Deskripsi != "" {
-		product.Deskripsi = updatedProduct.Deskripsi
+	if input.Deskripsi != nil {
+		product.Deskripsi = *input.Deskripsi
 	}
-	if updatedProduct.Harga != 0 {
-		product.Harga = updatedProduct.Harga
+	oldHarga := product.HargaMinor
+	if input.Harga != nil {
+		product.HargaMinor = models.ToMinor(*input.Harga, product.Currency)
+		product.Harga = models.FromMinor(product.HargaMinor, product.Currency)
+		product.SetEffectivePrice(product.HargaMinor)
 	}
-	if updatedProduct.Stok != 0 {
-		product.Stok = updatedProduct.Stok
+	if input.Department != nil {
+		product.Department = *input.Department
+	}
+	if input.ReorderPoint != nil {
+		if *input.ReorderPoint > 0 && product.Type == models.ProductBundle {
+			return nil, ErrBundleReorderPoint
+		}
+		product.ReorderPoint = *input.ReorderPoint
+	}
+	if input.TaxClassID != nil {
+		product.TaxClassID = nil
+		if *input.TaxClassID != 0 {
+			if _, err := findTaxClass(s.DB, *input.TaxClassID); err != nil {
+				return nil, err
+			}
+			product.TaxClassID = input.TaxClassID
+		}
+	}
+	if input.PriceIncludesTax != nil {
+		product.PriceIncludesTax = *input.PriceIncludesTax
 	}
 
-	// Simpan perubahan ke database
-	if err := s.DB.Save(&product).Error; err != nil {
+	if input.NamaProduk != nil || input.Department != nil {
+		if err := s.checkUniqueName(s.DB, product.NamaProduk, product.Department, product.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	var categories []models.Category
+	if input.CategoryIDs != nil {
+		var err error
+		if categories, err = findCategories(s.DB, *input.CategoryIDs); err != nil {
+			return nil, err
+		}
+	}
+
+	// Attribute divalidasi ulang jika attribute atau kategori produk berubah
+	if input.Attributes != nil || input.CategoryIDs != nil {
+		var categoryIDs []int
+		if input.CategoryIDs != nil {
+			categoryIDs = *input.CategoryIDs
+		} else if err := s.DB.Table("product_categories").Where("product_id = ?", product.ID).Pluck("category_id", &categoryIDs).Error; err != nil {
+			return nil, err
+		}
+		values := product.Attributes
+		if input.Attributes != nil {
+			values = *input.Attributes
+		}
+		definitions, err := productAttributeDefinitions(s.DB, categoryIDs)
+		if err != nil {
+			return nil, err
+		}
+		if product.Attributes, err = validateAttributes(definitions, values); err != nil {
+			return nil, err
+		}
+	}
+
+	// Simpan perubahan ke database. Stok tidak ikut disimpan karena hanya boleh berubah lewat ledger.
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		if input.SKU != nil || input.Barcode != nil {
+			if err := checkProductCodes(tx, product.SKU, product.Barcode, product.ID, 0); err != nil {
+				return err
+			}
+		}
+		if err := tx.Model(&product).Select("nama_produk", "sku", "barcode", "deskripsi", "harga_minor", "department", "reorder_point", "attributes", "tax_class_id", "price_includes_tax").Updates(&product).Error; err != nil {
+			return err
+		}
+		if err := recordPriceChange(tx, &product, oldHarga, models.PriceChangeManual, nil, userID); err != nil {
+			return err
+		}
+		if input.CategoryIDs != nil {
+			if err := tx.Model(&product).Omit("Categories.*").Association("Categories").Replace(categories); err != nil {
+				return err
+			}
+			product.Categories = categories
+		}
+		return nil
+	})
+	if err != nil {
 		return nil, err
 	}
-	return &product, nil
+	if input.ReorderPoint != nil {
+		s.evaluateLowStock(product.ID)
+	}
+	products := []models.Product{product}
+	if err := applyEffectivePrices(s.DB, products); err != nil {
+		return nil, err
+	}
+	return &products[0], nil
 }
 
-// DeleteProduct menghapus produk berdasarkan ID
-func (s *ProductService) DeleteProduct(id int) error {
-	if err := s.DB.Delete(&models.Product{}, id).Error; err != nil {
+// checkUniqueName memastikan nama produk unik (case-insensitive) dalam satu department
+func (s *ProductService) checkUniqueName(db *gorm.DB, name, department string, excludeID int) error {
+	var count int64
+	err := db.Model(&models.Product{}).
+		Where("LOWER(nama_produk) = LOWER(?) AND department = ? AND id <> ?", name, department, excludeID).
+		Count(&count).Error
+	if err != nil {
 		return err
 	}
+	if count > 0 {
+		return ErrDuplicateProductName
+	}
 	return nil
 }
+
+// DeleteProduct melakukan soft delete produk berdasarkan ID
+func (s *ProductService) DeleteProduct(id int) error {
+	result := s.DB.Delete(&models.Product{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrProductNotFound
+	}
+	return nil
+}
+
+// GetDeletedProducts mengambil produk yang sudah di-soft delete (trash)
+func (s *ProductService) GetDeletedProducts() ([]models.Product, error) {
+	var products []models.Product
+	if err := s.DB.Unscoped().Where("deleted_at IS NOT NULL").Order("deleted_at desc").Find(&products).Error; err != nil {
+		return nil, err
+	}
+	return products, nil
+}
+
+// RestoreProduct mengembalikan produk dari trash. Restore ditolak dengan ErrDuplicateProductName jika
+// selama di trash sudah ada produk lain dengan nama yang sama di department yang sama.
+func (s *ProductService) RestoreProduct(id int) (*models.Product, error) {
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		var product models.Product
+		if err := tx.Unscoped().Where("id = ? AND deleted_at IS NOT NULL", id).First(&product).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrProductNotFound
+			}
+			return err
+		}
+		if err := s.checkUniqueName(tx, product.NamaProduk, product.Department, product.ID); err != nil {
+			return err
+		}
+		return tx.Unscoped().Model(&product).Update("deleted_at", nil).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetProductByID(id)
+}
+
+// PurgeDeletedProducts menghapus permanen produk yang sudah berada di trash lebih lama dari retention.
+// Produk yang masih ada di order pending atau paid, atau masih di-reserve, ditunda sampai stoknya
+// tidak mungkin dikembalikan lagi.
+func (s *ProductService) PurgeDeletedProducts(retention time.Duration) (int64, error) {
+	cutoff := time.Now().Add(-retention)
+
+	openStatuses := []string{models.OrderPending, models.OrderPaid}
+	openOrderIDs := s.DB.Model(&models.Order{}).Select("id").Where("status IN ?", openStatuses)
+	openOrderReferences := s.DB.Model(&models.Order{}).Select("'order:' || id").Where("status IN ?", openStatuses)
+	heldProducts := s.DB.Model(&models.Reservation{}).Select("product_id").Where("status = ?", models.ReservationHeld)
+	var ids []int
+	err := s.DB.Unscoped().Model(&models.Product{}).
+		Where("deleted_at IS NOT NULL AND deleted_at < ?", cutoff).
+		Where("id NOT IN (?)", s.DB.Model(&models.OrderLine{}).Select("product_id").Where("order_id IN (?)", openOrderIDs)).
+		Where("id NOT IN (?)", s.DB.Model(&models.StockMovement{}).Select("product_id").
+			Where("type = ? AND reference IN (?)", models.StockMovementSale, openOrderReferences)).
+		Where("id NOT IN (?)", heldProducts).
+		Where("id NOT IN (?)", s.DB.Model(&models.BundleComponent{}).Select("component_id").Where("bundle_id IN (?)", heldProducts)).
+		Pluck("id", &ids).Error
+	if err != nil {
+		return 0, err
+	}
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	var images []models.ProductImage
+	if err := s.DB.Where("product_id IN ?", ids).Find(&images).Error; err != nil {
+		return 0, err
+	}
+
+	var purged int64
+	err = s.DB.Transaction(func(tx *gorm.DB) error {
+		// Hapus relasi many-to-many yang tidak ikut terhapus oleh database
+		if err := tx.Exec("DELETE FROM product_categories WHERE product_id IN ?", ids).Error; err != nil {
+			return err
+		}
+		if err := tx.Exec("DELETE FROM product_tags WHERE product_id IN ?", ids).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("product_id IN ?", ids).Delete(&models.ProductVariant{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("product_id IN ?", ids).Delete(&models.ProductOption{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("product_id IN ?", ids).Delete(&models.ProductImage{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("product_id IN ?", ids).Delete(&models.PriceChange{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("product_id IN ?", ids).Delete(&models.ScheduledPrice{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("product_id IN ?", ids).Delete(&models.ProductCurrencyPrice{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("product_id IN ?", ids).Delete(&models.ProductTranslation{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("product_id IN ? OR related_id IN ?", ids, ids).Delete(&models.ProductRelation{}).Error; err != nil {
+			return err
+		}
+		// Baris komponen yang menunjuk produk yang di-purge dibiarkan agar bundle-nya tetap tidak tersedia
+		if err := tx.Where("bundle_id IN ?", ids).Delete(&models.BundleComponent{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("product_id IN ?", ids).Delete(&models.SupplierProduct{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("product_id IN ?", ids).Delete(&models.CartItem{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("product_id IN ?", ids).Delete(&models.Review{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("product_id IN ?", ids).Delete(&models.PriceListRule{}).Error; err != nil {
+			return err
+		}
+		result := tx.Unscoped().Where("id IN ?", ids).Delete(&models.Product{})
+		purged = result.RowsAffected
+		return result.Error
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	// File dihapus setelah commit agar gambar tidak hilang jika transaksi gagal
+	if s.ImageStorage != nil {
+		deleteImageFiles(s.ImageStorage, images)
+	}
+	return purged, nil
+}
+
+// StartPurgeScheduler menjalankan PurgeDeletedProducts secara berkala di background
+func (s *ProductService) StartPurgeScheduler(interval, retention time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			purged, err := s.PurgeDeletedProducts(retention)
+			if err != nil {
+				log.Printf("Could not purge deleted products: %v", err)
+				continue
+			}
+			if purged > 0 {
+				log.Printf("Purged %d deleted products", purged)
+			}
+		}
+	}()
+}
@@ -0,0 +1,206 @@
+package services
+
+import (
+	"errors"
+	"log"
+	"products-api-with-jwt/models"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrInvalidStatusTransition dikembalikan jika transisi status tidak mengikuti draft -> published -> archived
+	ErrInvalidStatusTransition = errors.New("status transition is not allowed")
+	// ErrProductIncomplete dikembalikan jika produk belum lolos pemeriksaan kelengkapan untuk di-publish
+	ErrProductIncomplete = errors.New("product is not complete enough to be published")
+	// ErrInvalidPublishSchedule dikembalikan jika publish_at diisi untuk produk yang bukan draft
+	ErrInvalidPublishSchedule = errors.New("publish_at can only be set on draft products")
+	// ErrInvalidUnpublishSchedule dikembalikan jika unpublish_at tidak valid untuk status atau jadwal publish produk
+	ErrInvalidUnpublishSchedule = errors.New("unpublish_at must be after publish_at and cannot be set on archived products")
+)
+
+// ProductIncompleteError berisi field yang belum memenuhi syarat publish
+type ProductIncompleteError struct {
+	Fields []models.FieldError
+}
+
+func (e *ProductIncompleteError) Error() string {
+	names := make([]string, len(e.Fields))
+	for i, field := range e.Fields {
+		names[i] = field.Field
+	}
+	return ErrProductIncomplete.Error() + ": " + strings.Join(names, ", ")
+}
+
+func (e *ProductIncompleteError) Unwrap() error {
+	return ErrProductIncomplete
+}
+
+// ChangeProductStatus memindahkan produk ke status berikutnya. Publish memerlukan produk yang lengkap.
+func (s *ProductService) ChangeProductStatus(id int, status string) (*models.Product, error) {
+	var product *models.Product
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		if product, err = findProduct(tx, id); err != nil {
+			return err
+		}
+		switch {
+		case product.Status == models.ProductDraft && status == models.ProductPublished:
+			return s.publishProduct(tx, product, time.Now())
+		case product.Status == models.ProductPublished && status == models.ProductArchived:
+			return archiveProduct(tx, product)
+		default:
+			return ErrInvalidStatusTransition
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.productWithPrices(product)
+}
+
+// ScheduleProductStatus mengganti jadwal publish dan archive produk
+func (s *ProductService) ScheduleProductStatus(id int, input *models.ProductStatusScheduleInput) (*models.Product, error) {
+	var product *models.Product
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		if product, err = findProduct(tx, id); err != nil {
+			return err
+		}
+		if input.PublishAt != nil && product.Status != models.ProductDraft {
+			return ErrInvalidPublishSchedule
+		}
+		if input.UnpublishAt != nil {
+			if product.Status == models.ProductArchived || (input.PublishAt != nil && !input.UnpublishAt.After(*input.PublishAt)) {
+				return ErrInvalidUnpublishSchedule
+			}
+		}
+		product.PublishAt = input.PublishAt
+		product.UnpublishAt = input.UnpublishAt
+		return tx.Model(product).Select("publish_at", "unpublish_at").Updates(product).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.productWithPrices(product)
+}
+
+// ApplyStatusSchedules mem-publish draft yang jadwal publish-nya sudah tiba dan meng-archive produk yang
+// jadwal unpublish-nya sudah tiba. Draft yang belum lengkap tidak di-publish dan jadwalnya dihapus.
+// Mengembalikan jumlah produk yang statusnya berubah.
+func (s *ProductService) ApplyStatusSchedules(now time.Time) (int, error) {
+	changed := 0
+
+	var drafts []models.Product
+	if err := s.DB.Where("status = ? AND publish_at <= ?", models.ProductDraft, now).Order("publish_at").Find(&drafts).Error; err != nil {
+		return changed, err
+	}
+	for i := range drafts {
+		err := s.DB.Transaction(func(tx *gorm.DB) error {
+			return s.publishProduct(tx, &drafts[i], now)
+		})
+		if errors.Is(err, ErrProductIncomplete) {
+			log.Printf("Scheduled publish of product %d skipped: %v", drafts[i].ID, err)
+			if err := s.DB.Model(&drafts[i]).Update("publish_at", nil).Error; err != nil {
+				return changed, err
+			}
+			continue
+		}
+		if err != nil {
+			return changed, err
+		}
+		changed++
+	}
+
+	// Produk yang baru di-publish di atas ikut diperiksa, sehingga jadwal yang sudah lewat keduanya langsung di-archive
+	var published []models.Product
+	if err := s.DB.Where("status = ? AND unpublish_at <= ?", models.ProductPublished, now).Find(&published).Error; err != nil {
+		return changed, err
+	}
+	for i := range published {
+		if err := archiveProduct(s.DB, &published[i]); err != nil {
+			return changed, err
+		}
+		changed++
+	}
+	return changed, nil
+}
+
+// StartStatusScheduler menjalankan ApplyStatusSchedules secara berkala di background
+func (s *ProductService) StartStatusScheduler(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			changed, err := s.ApplyStatusSchedules(time.Now())
+			if err != nil {
+				log.Printf("Failed to apply product status schedules: %v", err)
+				continue
+			}
+			if changed > 0 {
+				log.Printf("Changed the status of %d scheduled products", changed)
+			}
+		}
+	}()
+}
+
+// checkCompleteness memastikan produk punya deskripsi, harga, dan minimal satu gambar jika produk pernah punya gambar.
+// Bundle yang harganya dihitung dari komponen dicek dengan harga hasil hitungan, bukan harga yang tersimpan.
+func (s *ProductService) checkCompleteness(tx *gorm.DB, product *models.Product) error {
+	var fields []models.FieldError
+	if strings.TrimSpace(product.Deskripsi) == "" {
+		fields = append(fields, models.FieldError{Field: "deskripsi", Code: models.ValidationRequired, Message: "is required to publish"})
+	}
+	if product.Type == models.ProductBundle && product.BundlePricing == models.BundlePricingComponents {
+		priced := []models.Product{*product}
+		if err := applyBundles(tx, priced); err != nil {
+			return err
+		}
+		if priced[0].EffectivePriceMinor <= 0 {
+			fields = append(fields, models.FieldError{Field: "components", Code: models.ValidationRequired, Message: "must give the bundle a price greater than 0 to publish"})
+		}
+	} else if product.HargaMinor <= 0 {
+		fields = append(fields, models.FieldError{Field: "harga", Code: models.ValidationRequired, Message: "must be greater than 0 to publish"})
+	}
+	if product.HasHadImages {
+		var count int64
+		if err := tx.Model(&models.ProductImage{}).Where("product_id = ?", product.ID).Count(&count).Error; err != nil {
+			return err
+		}
+		if count == 0 {
+			fields = append(fields, models.FieldError{Field: "images", Code: models.ValidationRequired, Message: "at least one image is required to publish"})
+		}
+	}
+	if len(fields) > 0 {
+		return &ProductIncompleteError{Fields: fields}
+	}
+	return nil
+}
+
+func (s *ProductService) publishProduct(tx *gorm.DB, product *models.Product, now time.Time) error {
+	if err := s.checkCompleteness(tx, product); err != nil {
+		return err
+	}
+	product.Status = models.ProductPublished
+	product.PublishAt = nil
+	product.PublishedAt = &now
+	return tx.Model(product).Select("status", "publish_at", "published_at").Updates(product).Error
+}
+
+func archiveProduct(tx *gorm.DB, product *models.Product) error {
+	product.Status = models.ProductArchived
+	product.UnpublishAt = nil
+	return tx.Model(product).Select("status", "unpublish_at").Updates(product).Error
+}
+
+// productWithPrices mengisi harga berlaku produk sebelum dikembalikan ke client
+func (s *ProductService) productWithPrices(product *models.Product) (*models.Product, error) {
+	products := []models.Product{*product}
+	if err := applyEffectivePrices(s.DB, products); err != nil {
+		return nil, err
+	}
+	return &products[0], nil
+}
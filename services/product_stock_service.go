@@ -0,0 +1,413 @@
+package services
+
+import (
+	"errors"
+	"log"
+	"products-api-with-jwt/models"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrInsufficientStock dikembalikan jika pergerakan stok akan membuat stok negatif
+	ErrInsufficientStock = errors.New("insufficient stock")
+	// ErrInvalidStockQuantity dikembalikan jika quantity tidak sesuai dengan jenis pergerakan
+	ErrInvalidStockQuantity = errors.New("quantity must be positive for receipt, sale and return")
+	// ErrStockReasonRequired dikembalikan jika adjustment dicatat tanpa alasan
+	ErrStockReasonRequired = errors.New("reason is required for an adjustment")
+)
+
+// AdjustStock mencatat pergerakan stok ke ledger dan memperbarui Stok produk dalam satu transaksi.
+// Pengurangan tanpa warehouse bisa dipecah menjadi beberapa entri, satu per warehouse.
+// Sale dan return bundle dicatat di ledger setiap komponennya. Dengan VariantID, yang bergerak
+// adalah stok variant, bukan stok produk.
+func (s *ProductService) AdjustStock(productID int, input *models.StockAdjustInput, userID int) ([]models.StockMovement, error) {
+	quantity := input.Quantity
+	if input.Type == models.StockMovementAdjustment && strings.TrimSpace(input.Reason) == "" {
+		return nil, ErrStockReasonRequired
+	}
+	if input.Type != models.StockMovementAdjustment {
+		if quantity <= 0 {
+			return nil, ErrInvalidStockQuantity
+		}
+		// Sale mengurangi stok
+		if input.Type == models.StockMovementSale {
+			quantity = -quantity
+		}
+	}
+
+	movement := models.StockMovement{
+		ProductID:   productID,
+		WarehouseID: input.WarehouseID,
+		Type:        input.Type,
+		Quantity:    quantity,
+		UserID:      userID,
+		Reason:      input.Reason,
+		Reference:   input.Reference,
+	}
+	if input.VariantID != 0 {
+		variantID := input.VariantID
+		movement.VariantID = &variantID
+	}
+
+	var movements []models.StockMovement
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		// Bundle tidak punya stok sendiri: penjualan dan retur diteruskan ke komponennya
+		var product models.Product
+		if err := tx.Select("id", "type").First(&product, productID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrProductNotFound
+			}
+			return err
+		}
+		if product.Type == models.ProductBundle && (movement.VariantID != nil || input.Type != models.StockMovementSale && input.Type != models.StockMovementReturn) {
+			return ErrBundleStock
+		}
+
+		var err error
+		if movement.VariantID != nil {
+			if _, err := findVariant(tx, productID, *movement.VariantID); err != nil {
+				return err
+			}
+			movements, err = s.postStockMovement(tx, movement)
+			return err
+		}
+		movements, err = s.postProductMovement(tx, movement)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.evaluateLowStock(movedProductIDs(movements)...)
+	return movements, nil
+}
+
+// TransferStock memindahkan stok antar warehouse secara atomik. Total stok produk tidak berubah,
+// ledger mencatat dua entri transfer (keluar dan masuk) dengan referensi yang sama.
+func (s *ProductService) TransferStock(productID int, input *models.StockTransferInput, userID int) ([]models.StockMovement, error) {
+	var movements []models.StockMovement
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		var product models.Product
+		if err := tx.Select("id", "stok", "type").First(&product, productID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrProductNotFound
+			}
+			return err
+		}
+		if product.Type == models.ProductBundle {
+			return ErrBundleStock
+		}
+
+		for _, warehouseID := range []int{input.FromWarehouseID, input.ToWarehouseID} {
+			if err := tx.First(&models.Warehouse{}, warehouseID).Error; err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					return ErrWarehouseNotFound
+				}
+				return err
+			}
+		}
+
+		if err := applyWarehouseDelta(tx, input.FromWarehouseID, productID, nil, -input.Quantity); err != nil {
+			return err
+		}
+		if err := applyWarehouseDelta(tx, input.ToWarehouseID, productID, nil, input.Quantity); err != nil {
+			return err
+		}
+
+		movements = []models.StockMovement{
+			{WarehouseID: input.FromWarehouseID, Quantity: -input.Quantity},
+			{WarehouseID: input.ToWarehouseID, Quantity: input.Quantity},
+		}
+		for i := range movements {
+			movements[i].ProductID = productID
+			movements[i].Type = models.StockMovementTransfer
+			movements[i].BalanceAfter = product.Stok
+			movements[i].UserID = userID
+			movements[i].Reason = input.Reason
+			movements[i].Reference = input.Reference
+		}
+		return tx.Create(&movements).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return movements, nil
+}
+
+// GetStockHistory mengambil ledger stok sebuah produk, terbaru lebih dulu
+func (s *ProductService) GetStockHistory(productID int) ([]models.StockMovement, error) {
+	if _, err := s.GetProductByID(productID); err != nil {
+		return nil, err
+	}
+
+	var movements []models.StockMovement
+	if err := s.DB.Where("product_id = ?", productID).Order("id desc").Find(&movements).Error; err != nil {
+		return nil, err
+	}
+	return movements, nil
+}
+
+// postStockMovement menerapkan pergerakan stok produk atau variant di dalam transaksi tx dan mengembalikan
+// entri ledger yang dibuat. Tanpa warehouse, stok masuk ke warehouse default, sedangkan stok keluar
+// dialokasikan dari warehouse dengan stok terbanyak lebih dulu.
+func (s *ProductService) postStockMovement(tx *gorm.DB, movement models.StockMovement) ([]models.StockMovement, error) {
+	if movement.WarehouseID != 0 {
+		if err := tx.First(&models.Warehouse{}, movement.WarehouseID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, ErrWarehouseNotFound
+			}
+			return nil, err
+		}
+		if err := s.applyStockMovement(tx, &movement); err != nil {
+			return nil, err
+		}
+		return []models.StockMovement{movement}, nil
+	}
+
+	if movement.Quantity >= 0 {
+		warehouseID, err := defaultWarehouseID(tx)
+		if err != nil {
+			return nil, err
+		}
+		movement.WarehouseID = warehouseID
+		if err := s.applyStockMovement(tx, &movement); err != nil {
+			return nil, err
+		}
+		return []models.StockMovement{movement}, nil
+	}
+
+	var locations []models.WarehouseStock
+	if err := stockOfVariant(tx.Where("product_id = ? AND quantity > 0", movement.ProductID), movement.VariantID).Order("quantity desc, warehouse_id").Find(&locations).Error; err != nil {
+		return nil, err
+	}
+
+	var movements []models.StockMovement
+	remaining := -movement.Quantity
+	for _, location := range locations {
+		if remaining == 0 {
+			break
+		}
+		take := min(remaining, location.Quantity)
+		split := movement
+		split.WarehouseID = location.WarehouseID
+		split.Quantity = -take
+		if err := s.applyStockMovement(tx, &split); err != nil {
+			return nil, err
+		}
+		movements = append(movements, split)
+		remaining -= take
+	}
+	if remaining > 0 {
+		// Cek dulu apakah produknya ada agar error yang dikembalikan tepat
+		var count int64
+		if err := tx.Model(&models.Product{}).Where("id = ?", movement.ProductID).Count(&count).Error; err != nil {
+			return nil, err
+		}
+		if count == 0 {
+			return nil, ErrProductNotFound
+		}
+		return nil, ErrInsufficientStock
+	}
+	return movements, nil
+}
+
+// stockOfVariant membatasi query stock_movements atau warehouse_stocks ke stok produk itu sendiri
+// (variantID nil) atau ke stok satu variant
+func stockOfVariant(query *gorm.DB, variantID *int) *gorm.DB {
+	if variantID == nil {
+		return query.Where("variant_id IS NULL")
+	}
+	return query.Where("variant_id = ?", *variantID)
+}
+
+// applyStockMovement menerapkan satu pergerakan stok di satu warehouse di dalam transaksi tx.
+// Update stok bersifat kondisional sehingga stok tidak pernah turun di bawah jumlah yang
+// sedang di-reserve (dan tidak pernah negatif) walaupun ada request bersamaan.
+func (s *ProductService) applyStockMovement(tx *gorm.DB, movement *models.StockMovement) error {
+	if movement.VariantID != nil {
+		return applyVariantMovement(tx, movement)
+	}
+
+	result := tx.Model(&models.Product{}).
+		Where("id = ? AND stok + ? >= reserved", movement.ProductID, movement.Quantity).
+		Update("stok", gorm.Expr("stok + ?", movement.Quantity))
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		var count int64
+		if err := tx.Model(&models.Product{}).Where("id = ?", movement.ProductID).Count(&count).Error; err != nil {
+			return err
+		}
+		if count == 0 {
+			return ErrProductNotFound
+		}
+		return ErrInsufficientStock
+	}
+
+	if err := applyWarehouseDelta(tx, movement.WarehouseID, movement.ProductID, nil, movement.Quantity); err != nil {
+		return err
+	}
+
+	if err := tx.Model(&models.Product{}).Select("stok").Where("id = ?", movement.ProductID).Scan(&movement.BalanceAfter).Error; err != nil {
+		return err
+	}
+	return tx.Create(movement).Error
+}
+
+// applyVariantMovement seperti applyStockMovement untuk stok variant. Variant tidak bisa di-reserve,
+// jadi stoknya hanya dijaga agar tidak negatif.
+func applyVariantMovement(tx *gorm.DB, movement *models.StockMovement) error {
+	result := tx.Model(&models.ProductVariant{}).
+		Where("id = ? AND product_id = ? AND stok + ? >= 0", *movement.VariantID, movement.ProductID, movement.Quantity).
+		Update("stok", gorm.Expr("stok + ?", movement.Quantity))
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		if _, err := findVariant(tx, movement.ProductID, *movement.VariantID); err != nil {
+			return err
+		}
+		return ErrInsufficientStock
+	}
+
+	if err := applyWarehouseDelta(tx, movement.WarehouseID, movement.ProductID, movement.VariantID, movement.Quantity); err != nil {
+		return err
+	}
+
+	if err := tx.Model(&models.ProductVariant{}).Select("stok").Where("id = ?", *movement.VariantID).Scan(&movement.BalanceAfter).Error; err != nil {
+		return err
+	}
+	return tx.Create(movement).Error
+}
+
+// applyWarehouseDelta mengubah stok produk (atau variant-nya) di satu warehouse, tidak boleh menjadi negatif
+func applyWarehouseDelta(tx *gorm.DB, warehouseID, productID int, variantID *int, delta int) error {
+	result := stockOfVariant(tx.Model(&models.WarehouseStock{}), variantID).
+		Where("warehouse_id = ? AND product_id = ? AND quantity + ? >= 0", warehouseID, productID, delta).
+		Update("quantity", gorm.Expr("quantity + ?", delta))
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected > 0 {
+		return nil
+	}
+
+	var count int64
+	if err := stockOfVariant(tx.Model(&models.WarehouseStock{}), variantID).Where("warehouse_id = ? AND product_id = ?", warehouseID, productID).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 || delta < 0 {
+		return ErrInsufficientStock
+	}
+	return tx.Create(&models.WarehouseStock{WarehouseID: warehouseID, ProductID: productID, VariantID: variantID, Quantity: delta}).Error
+}
+
+// ReconcileStock mencocokkan stok setiap produk dan variant dengan ledger. Produk atau variant lama yang
+// belum punya ledger mendapat entri saldo awal di warehouse default; jika ada selisih, ledger dianggap
+// sebagai sumber kebenaran untuk stok per warehouse dan total Stok.
+func (s *ProductService) ReconcileStock() error {
+	warehouseID, err := defaultWarehouseID(s.DB)
+	if err != nil {
+		return err
+	}
+
+	// Entri ledger dari sebelum ada warehouse dianggap berada di warehouse default
+	if err := s.DB.Model(&models.StockMovement{}).Where("warehouse_id = 0").Update("warehouse_id", warehouseID).Error; err != nil {
+		return err
+	}
+
+	var products []models.Product
+	if err := s.DB.Unscoped().Find(&products).Error; err != nil {
+		return err
+	}
+	for _, product := range products {
+		if err := s.reconcileBalance(product.ID, nil, product.Stok, warehouseID); err != nil {
+			return err
+		}
+	}
+
+	var variants []models.ProductVariant
+	if err := s.DB.Find(&variants).Error; err != nil {
+		return err
+	}
+	for _, variant := range variants {
+		if err := s.reconcileBalance(variant.ProductID, &variant.ID, variant.Stok, warehouseID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// reconcileBalance mencocokkan Stok sebuah produk (variantID nil) atau sebuah variant dengan ledger-nya
+func (s *ProductService) reconcileBalance(productID int, variantID *int, stok, defaultWarehouseID int) error {
+	var balances []struct {
+		WarehouseID int
+		Total       int
+	}
+	err := stockOfVariant(s.DB.Model(&models.StockMovement{}), variantID).
+		Select("warehouse_id, SUM(quantity) AS total").
+		Where("product_id = ?", productID).
+		Group("warehouse_id").
+		Scan(&balances).Error
+	if err != nil {
+		return err
+	}
+
+	if len(balances) == 0 {
+		if stok == 0 {
+			return nil
+		}
+		opening := models.StockMovement{
+			ProductID:    productID,
+			VariantID:    variantID,
+			WarehouseID:  defaultWarehouseID,
+			Type:         models.StockMovementAdjustment,
+			Quantity:     stok,
+			BalanceAfter: stok,
+			Reason:       "opening balance",
+		}
+		if err := s.DB.Create(&opening).Error; err != nil {
+			return err
+		}
+		balances = append(balances, struct {
+			WarehouseID int
+			Total       int
+		}{defaultWarehouseID, stok})
+	}
+
+	total := 0
+	for _, balance := range balances {
+		total += balance.Total
+		if err := s.reconcileWarehouseStock(productID, variantID, balance.WarehouseID, balance.Total); err != nil {
+			return err
+		}
+	}
+	if total == stok {
+		return nil
+	}
+
+	if variantID != nil {
+		log.Printf("Stock mismatch for variant %d of product %d: stok=%d ledger=%d, resetting to ledger", *variantID, productID, stok, total)
+		return s.DB.Model(&models.ProductVariant{}).Where("id = ?", *variantID).Update("stok", total).Error
+	}
+	log.Printf("Stock mismatch for product %d: stok=%d ledger=%d, resetting to ledger", productID, stok, total)
+	return s.DB.Unscoped().Model(&models.Product{}).Where("id = ?", productID).Update("stok", total).Error
+}
+
+func (s *ProductService) reconcileWarehouseStock(productID int, variantID *int, warehouseID, total int) error {
+	var location models.WarehouseStock
+	err := stockOfVariant(s.DB, variantID).Where("warehouse_id = ? AND product_id = ?", warehouseID, productID).First(&location).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return s.DB.Create(&models.WarehouseStock{WarehouseID: warehouseID, ProductID: productID, VariantID: variantID, Quantity: total}).Error
+	}
+	if err != nil {
+		return err
+	}
+	if location.Quantity != total {
+		log.Printf("Stock mismatch for product %d in warehouse %d: quantity=%d ledger=%d, resetting to ledger", productID, warehouseID, location.Quantity, total)
+		return s.DB.Model(&location).Update("quantity", total).Error
+	}
+	return nil
+}
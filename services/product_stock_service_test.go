@@ -0,0 +1,102 @@
+package services
+
+import (
+	"errors"
+	"products-api-with-jwt/models"
+	"testing"
+)
+
+func TestAdjustStock(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       models.StockAdjustInput
+		wantErr     error
+		wantStok    int
+		wantEntries int64 // Entri ledger baru
+	}{
+		{name: "receipt appends an entry", input: models.StockAdjustInput{Type: models.StockMovementReceipt, Quantity: 5}, wantStok: 15, wantEntries: 1},
+		{name: "sale", input: models.StockAdjustInput{Type: models.StockMovementSale, Quantity: 4}, wantStok: 6, wantEntries: 1},
+		{name: "negative adjustment with reason", input: models.StockAdjustInput{Type: models.StockMovementAdjustment, Quantity: -3, Reason: "damaged"}, wantStok: 7, wantEntries: 1},
+		{name: "negative adjustment without reason", input: models.StockAdjustInput{Type: models.StockMovementAdjustment, Quantity: -3}, wantErr: ErrStockReasonRequired, wantStok: 10},
+		{name: "blank reason", input: models.StockAdjustInput{Type: models.StockMovementAdjustment, Quantity: -3, Reason: "  "}, wantErr: ErrStockReasonRequired, wantStok: 10},
+		{name: "sale beyond stock", input: models.StockAdjustInput{Type: models.StockMovementSale, Quantity: 11}, wantErr: ErrInsufficientStock, wantStok: 10},
+		{name: "adjustment below zero", input: models.StockAdjustInput{Type: models.StockMovementAdjustment, Quantity: -11, Reason: "count"}, wantErr: ErrInsufficientStock, wantStok: 10},
+		{name: "non-positive receipt", input: models.StockAdjustInput{Type: models.StockMovementReceipt, Quantity: -1}, wantErr: ErrInvalidStockQuantity, wantStok: 10},
+		{name: "unknown warehouse", input: models.StockAdjustInput{Type: models.StockMovementReceipt, Quantity: 1, WarehouseID: 99}, wantErr: ErrWarehouseNotFound, wantStok: 10},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := newTestDB(t)
+			ps := NewProductService(db)
+			id := createPublishedProduct(t, ps, "Product", 1000, 10)
+			before := movementCount(t, db, id)
+
+			movements, err := ps.AdjustStock(id, &tt.input, 1)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("AdjustStock() error = %v, want %v", err, tt.wantErr)
+			}
+			if got := stockOf(t, db, id); got != tt.wantStok {
+				t.Errorf("stock = %d, want %d", got, tt.wantStok)
+			}
+			if got := movementCount(t, db, id) - before; got != tt.wantEntries {
+				t.Errorf("ledger grew by %d entries, want %d", got, tt.wantEntries)
+			}
+			if tt.wantErr == nil && movements[len(movements)-1].BalanceAfter != tt.wantStok {
+				t.Errorf("BalanceAfter = %d, want %d", movements[len(movements)-1].BalanceAfter, tt.wantStok)
+			}
+		})
+	}
+}
+
+func TestReconcileStock(t *testing.T) {
+	db := newTestDB(t)
+	ps := NewProductService(db)
+
+	tracked := createPublishedProduct(t, ps, "Tracked", 1000, 10)
+	if _, err := ps.AdjustStock(tracked, &models.StockAdjustInput{Type: models.StockMovementReceipt, Quantity: 5}, 1); err != nil {
+		t.Fatal(err)
+	}
+	// Stok yang diubah di luar ledger harus dikembalikan ke jumlah ledger
+	if err := db.Model(&models.Product{}).Where("id = ?", tracked).Update("stok", 99).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Model(&models.WarehouseStock{}).Where("product_id = ?", tracked).Update("quantity", 1).Error; err != nil {
+		t.Fatal(err)
+	}
+	// Produk lama tanpa ledger mendapat entri saldo awal
+	legacy := models.Product{NamaProduk: "Legacy", Currency: models.DefaultCurrency, Status: models.ProductPublished, Stok: 7}
+	if err := db.Create(&legacy).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ps.ReconcileStock(); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name        string
+		productID   int
+		wantStok    int
+		wantEntries int64
+	}{
+		{name: "stock rebuilt from ledger", productID: tracked, wantStok: 15, wantEntries: 2},
+		{name: "opening balance for legacy stock", productID: legacy.ID, wantStok: 7, wantEntries: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := stockOf(t, db, tt.productID); got != tt.wantStok {
+				t.Errorf("stock = %d, want %d", got, tt.wantStok)
+			}
+			if got := movementCount(t, db, tt.productID); got != tt.wantEntries {
+				t.Errorf("ledger has %d entries, want %d", got, tt.wantEntries)
+			}
+			var location models.WarehouseStock
+			if err := db.Where("product_id = ? AND variant_id IS NULL", tt.productID).First(&location).Error; err != nil {
+				t.Fatal(err)
+			}
+			if location.Quantity != tt.wantStok {
+				t.Errorf("warehouse quantity = %d, want %d", location.Quantity, tt.wantStok)
+			}
+		})
+	}
+}
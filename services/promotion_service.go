@@ -0,0 +1,398 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"products-api-with-jwt/models"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrPromotionNotFound dikembalikan jika promo dengan ID yang diminta tidak ada
+	ErrPromotionNotFound = errors.New("promotion not found")
+	// ErrPromotionTargetsRequired dikembalikan jika promo tidak punya target produk, kategori maupun tag
+	ErrPromotionTargetsRequired = errors.New("at least one of product_ids, category_ids or tags is required")
+	// ErrInvalidPromotionProduct dikembalikan jika target produk promo tidak ada
+	ErrInvalidPromotionProduct = errors.New("one or more products do not exist")
+	// ErrInvalidPromotionWindow dikembalikan jika ends_at tidak setelah starts_at
+	ErrInvalidPromotionWindow = errors.New("must be after starts_at")
+)
+
+type PromotionService struct {
+	DB *gorm.DB
+}
+
+// NewPromotionService menginisialisasi PromotionService baru
+func NewPromotionService(db *gorm.DB) *PromotionService {
+	return &PromotionService{DB: db}
+}
+
+// GetAllPromotions mengambil semua promo, urut sesuai urutan penerapannya
+func (s *PromotionService) GetAllPromotions() ([]models.Promotion, error) {
+	var promotions []models.Promotion
+	if err := s.DB.Order("priority desc, id").Find(&promotions).Error; err != nil {
+		return nil, err
+	}
+	return promotions, nil
+}
+
+// GetPromotionByID mengambil promo berdasarkan ID
+func (s *PromotionService) GetPromotionByID(id int) (*models.Promotion, error) {
+	var promotion models.Promotion
+	if err := s.DB.First(&promotion, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrPromotionNotFound
+		}
+		return nil, err
+	}
+	return &promotion, nil
+}
+
+// CreatePromotion menambah promo baru
+func (s *PromotionService) CreatePromotion(input *models.PromotionInput) (*models.Promotion, error) {
+	promotion, err := s.buildPromotion(input)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.DB.Create(promotion).Error; err != nil {
+		return nil, err
+	}
+	return promotion, nil
+}
+
+// UpdatePromotion mengganti seluruh isi promo
+func (s *PromotionService) UpdatePromotion(id int, input *models.PromotionInput) (*models.Promotion, error) {
+	existing, err := s.GetPromotionByID(id)
+	if err != nil {
+		return nil, err
+	}
+	promotion, err := s.buildPromotion(input)
+	if err != nil {
+		return nil, err
+	}
+	promotion.ID = existing.ID
+	promotion.CreatedAt = existing.CreatedAt
+	if err := s.DB.Select("*").Save(promotion).Error; err != nil {
+		return nil, err
+	}
+	return promotion, nil
+}
+
+// DeletePromotion menghapus promo
+func (s *PromotionService) DeletePromotion(id int) error {
+	result := s.DB.Delete(&models.Promotion{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrPromotionNotFound
+	}
+	return nil
+}
+
+// QuotePrice menghitung harga akhir sejumlah unit produk dengan price list user, semua promo
+// dan pajak region yang berlaku saat ini. Tanpa includeDrafts hanya produk published yang bisa dihitung.
+func (s *PromotionService) QuotePrice(productID, quantity int, region string, userID int, includeDrafts bool) (*models.PriceQuote, error) {
+	region, err := normalizeTaxRegion(region)
+	if err != nil {
+		return nil, err
+	}
+	if !includeDrafts {
+		product, err := findProduct(s.DB, productID)
+		if err != nil {
+			return nil, err
+		}
+		if product.Status != models.ProductPublished {
+			return nil, ErrProductNotAvailable
+		}
+	}
+	priceList, err := userPriceList(s.DB, userID)
+	if err != nil {
+		return nil, err
+	}
+	return quotePrice(s.DB, productID, quantity, time.Now(), nil, region, priceList)
+}
+
+// DryRun menghitung harga seperti QuotePrice pada waktu tertentu, opsional dengan promo yang belum disimpan.
+// Waktu hanya memengaruhi promo dan tarif pajak mana yang berlaku; harga dasar tetap harga yang berlaku sekarang.
+func (s *PromotionService) DryRun(input *models.PromotionDryRunInput) (*models.PriceQuote, error) {
+	region, err := normalizeTaxRegion(input.Region)
+	if err != nil {
+		return nil, err
+	}
+	priceList, err := userPriceList(s.DB, input.UserID)
+	if err != nil {
+		return nil, err
+	}
+	at := time.Now()
+	if input.At != nil {
+		at = *input.At
+	}
+	var draft *models.Promotion
+	if input.Promotion != nil {
+		if draft, err = s.buildPromotion(input.Promotion); err != nil {
+			return nil, err
+		}
+	}
+	return quotePrice(s.DB, input.ProductID, input.Quantity, at, draft, region, priceList)
+}
+
+// quotePrice menghitung harga dengan koneksi db, sehingga bisa dipakai di dalam transaksi.
+// region harus sudah dinormalisasi dengan normalizeTaxRegion. priceList nil berarti harga katalog.
+func quotePrice(db *gorm.DB, productID, quantity int, at time.Time, draft *models.Promotion, region string, priceList *models.PriceList) (*models.PriceQuote, error) {
+	var product models.Product
+	if err := db.Preload("Categories").Preload("Tags").First(&product, productID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrProductNotFound
+		}
+		return nil, err
+	}
+	products := []models.Product{product}
+	if err := applyEffectivePrices(db, products); err != nil {
+		return nil, err
+	}
+	promotions, err := activePromotions(db, at, draft)
+	if err != nil {
+		return nil, err
+	}
+	return quoteProduct(db, products[0], quantity, at, promotions, region, priceList)
+}
+
+// activePromotions mengambil promo aktif pada waktu at, ditambah promo draft jika berlaku saat itu
+func activePromotions(db *gorm.DB, at time.Time, draft *models.Promotion) ([]models.Promotion, error) {
+	var promotions []models.Promotion
+	err := db.Where("active = ?", true).
+		Where("(starts_at IS NULL OR starts_at <= ?) AND (ends_at IS NULL OR ends_at > ?)", at, at).
+		Find(&promotions).Error
+	if err != nil {
+		return nil, err
+	}
+	if draft != nil && promotionActiveAt(draft, at) {
+		promotions = append(promotions, *draft)
+	}
+	return promotions, nil
+}
+
+// quoteProduct menghitung harga produk yang sudah dimuat dengan Categories, Tags dan harga berlakunya,
+// memakai promo yang sudah diambil. Dipakai langsung oleh cart agar produk dan promo tidak dimuat ulang per item.
+func quoteProduct(db *gorm.DB, product models.Product, quantity int, at time.Time, promotions []models.Promotion, region string, priceList *models.PriceList) (*models.PriceQuote, error) {
+	products := []models.Product{product}
+	if err := applyPriceList(db, products, priceList, quantity); err != nil {
+		return nil, err
+	}
+	product = products[0]
+
+	categoryIDs := make([]int, 0, len(product.Categories))
+	for _, category := range product.Categories {
+		categoryIDs = append(categoryIDs, category.ID)
+	}
+	categoryIDs, err := categoryAncestorIDs(db, categoryIDs)
+	if err != nil {
+		return nil, err
+	}
+	tags := make([]string, 0, len(product.Tags))
+	for _, tag := range product.Tags {
+		tags = append(tags, tag.Name)
+	}
+
+	matching := make([]models.Promotion, 0, len(promotions))
+	for _, promotion := range promotions {
+		if promotionMatches(&promotion, product.ID, categoryIDs, tags) {
+			matching = append(matching, promotion)
+		}
+	}
+	quote := evaluatePromotions(&product, quantity, matching)
+	quote.ListPriceMinor = product.ListPriceMinor
+	quote.PriceListID = product.PriceListID
+	quote.EvaluatedAt = at
+	if err := applyTax(db, quote, &product, region); err != nil {
+		return nil, err
+	}
+	return quote, nil
+}
+
+// buildPromotion memvalidasi input dan mengubahnya menjadi Promotion yang siap disimpan
+func (s *PromotionService) buildPromotion(input *models.PromotionInput) (*models.Promotion, error) {
+	if len(input.ProductIDs) == 0 && len(input.CategoryIDs) == 0 && len(input.Tags) == 0 {
+		return nil, ErrPromotionTargetsRequired
+	}
+	if input.StartsAt != nil && input.EndsAt != nil && !input.EndsAt.After(*input.StartsAt) {
+		return nil, ErrInvalidPromotionWindow
+	}
+
+	productIDs := uniqueInts(input.ProductIDs)
+	if len(productIDs) > 0 {
+		var count int64
+		if err := s.DB.Model(&models.Product{}).Where("id IN ?", productIDs).Count(&count).Error; err != nil {
+			return nil, err
+		}
+		if int(count) != len(productIDs) {
+			return nil, ErrInvalidPromotionProduct
+		}
+	}
+	categoryIDs := uniqueInts(input.CategoryIDs)
+	if _, err := findCategories(s.DB, categoryIDs); err != nil {
+		return nil, err
+	}
+
+	promotion := &models.Promotion{
+		Name:        strings.TrimSpace(input.Name),
+		Type:        input.Type,
+		MinQuantity: max(input.MinQuantity, 1),
+		ProductIDs:  productIDs,
+		CategoryIDs: categoryIDs,
+		Tags:        NormalizeTags(input.Tags),
+		StartsAt:    input.StartsAt,
+		EndsAt:      input.EndsAt,
+		Active:      input.Active == nil || *input.Active,
+		Priority:    input.Priority,
+		Exclusive:   input.Exclusive,
+	}
+	switch input.Type {
+	case models.PromotionPercentage:
+		promotion.Percentage = input.Percentage
+	case models.PromotionFixed:
+		promotion.Currency = input.Currency
+		if promotion.Currency == "" {
+			promotion.Currency = models.DefaultCurrency
+		}
+		promotion.AmountMinor = models.ToMinor(input.Amount, promotion.Currency)
+	case models.PromotionBuyXGetY:
+		promotion.BuyQuantity = input.BuyQuantity
+		promotion.GetQuantity = input.GetQuantity
+	}
+	return promotion, nil
+}
+
+// evaluatePromotions menerapkan promo berurutan dari prioritas tertinggi pada subtotal produk.
+// Setiap promo dihitung dari sisa harga setelah promo sebelumnya dan tidak pernah membuat harga negatif.
+func evaluatePromotions(product *models.Product, quantity int, promotions []models.Promotion) *models.PriceQuote {
+	sort.SliceStable(promotions, func(i, j int) bool {
+		if promotions[i].Priority != promotions[j].Priority {
+			return promotions[i].Priority > promotions[j].Priority
+		}
+		return promotions[i].ID < promotions[j].ID
+	})
+
+	unit := product.EffectivePriceMinor
+	subtotal := unit * int64(quantity)
+	quote := &models.PriceQuote{
+		ProductID:      product.ID,
+		Quantity:       quantity,
+		Currency:       product.Currency,
+		UnitPriceMinor: unit,
+		SubtotalMinor:  subtotal,
+		Lines: []models.PriceLine{{
+			Description: fmt.Sprintf("%d x %s %s", quantity, strconv.FormatFloat(models.FromMinor(unit, product.Currency), 'f', -1, 64), product.Currency),
+			AmountMinor: subtotal,
+			Amount:      models.FromMinor(subtotal, product.Currency),
+		}},
+	}
+
+	remaining := subtotal
+	exclusiveApplied := false
+	for _, promotion := range promotions {
+		skip := func(reason string) {
+			quote.Skipped = append(quote.Skipped, models.SkippedPromotion{PromotionID: promotion.ID, Name: promotion.Name, Reason: reason})
+		}
+		switch {
+		case exclusiveApplied:
+			skip("an exclusive promotion was already applied")
+			continue
+		case promotion.Exclusive && len(quote.Lines) > 1:
+			skip("exclusive promotion cannot be combined with promotions already applied")
+			continue
+		case quantity < promotion.MinQuantity:
+			skip(fmt.Sprintf("requires at least %d units", promotion.MinQuantity))
+			continue
+		}
+
+		var discount int64
+		var description string
+		switch promotion.Type {
+		case models.PromotionPercentage:
+			percentage, _ := new(big.Rat).SetString(strconv.FormatFloat(promotion.Percentage, 'f', -1, 64))
+			value := new(big.Rat).Mul(new(big.Rat).SetInt64(remaining), percentage)
+			discount = models.RoundHalfUp(value.Quo(value, big.NewRat(100, 1)))
+			description = fmt.Sprintf("%s (%s%% off)", promotion.Name, strconv.FormatFloat(promotion.Percentage, 'f', -1, 64))
+		case models.PromotionFixed:
+			if promotion.Currency != product.Currency {
+				skip(fmt.Sprintf("promotion currency %s does not match product currency %s", promotion.Currency, product.Currency))
+				continue
+			}
+			discount = promotion.AmountMinor * int64(quantity)
+			description = fmt.Sprintf("%s (%s %s off per unit)", promotion.Name,
+				strconv.FormatFloat(models.FromMinor(promotion.AmountMinor, promotion.Currency), 'f', -1, 64), promotion.Currency)
+		case models.PromotionBuyXGetY:
+			free := quantity / (promotion.BuyQuantity + promotion.GetQuantity) * promotion.GetQuantity
+			if free == 0 {
+				skip(fmt.Sprintf("requires at least %d units", promotion.BuyQuantity+promotion.GetQuantity))
+				continue
+			}
+			discount = unit * int64(free)
+			description = fmt.Sprintf("%s (buy %d get %d, %d free)", promotion.Name, promotion.BuyQuantity, promotion.GetQuantity, free)
+		}
+
+		discount = min(discount, remaining)
+		if discount <= 0 {
+			skip("no discount left to apply")
+			continue
+		}
+		remaining -= discount
+		id := promotion.ID
+		quote.Lines = append(quote.Lines, models.PriceLine{
+			Description: description,
+			PromotionID: &id,
+			AmountMinor: -discount,
+			Amount:      -models.FromMinor(discount, product.Currency),
+		})
+		exclusiveApplied = promotion.Exclusive
+	}
+
+	quote.DiscountMinor = subtotal - remaining
+	quote.TotalMinor = remaining
+	quote.Total = models.FromMinor(remaining, product.Currency)
+	return quote
+}
+
+// promotionMatches memeriksa apakah produk termasuk target promo lewat ID, kategori (termasuk induknya) atau tag
+func promotionMatches(promotion *models.Promotion, productID int, categoryIDs []int, tags []string) bool {
+	if slices.Contains(promotion.ProductIDs, productID) {
+		return true
+	}
+	for _, id := range categoryIDs {
+		if slices.Contains(promotion.CategoryIDs, id) {
+			return true
+		}
+	}
+	for _, tag := range tags {
+		if slices.Contains(promotion.Tags, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// promotionActiveAt memeriksa apakah promo aktif dan jendela waktunya mencakup at
+func promotionActiveAt(promotion *models.Promotion, at time.Time) bool {
+	return promotion.Active &&
+		(promotion.StartsAt == nil || !promotion.StartsAt.After(at)) &&
+		(promotion.EndsAt == nil || promotion.EndsAt.After(at))
+}
+
+func uniqueInts(values []int) []int {
+	unique := make([]int, 0, len(values))
+	for _, value := range values {
+		if !slices.Contains(unique, value) {
+			unique = append(unique, value)
+		}
+	}
+	return unique
+}
@@ -0,0 +1,104 @@
+package services
+
+import (
+	"products-api-with-jwt/models"
+	"testing"
+)
+
+func TestEvaluatePromotions(t *testing.T) {
+	product := &models.Product{ID: 1, Currency: "IDR", EffectivePriceMinor: 10000}
+
+	tests := []struct {
+		name         string
+		quantity     int
+		promotions   []models.Promotion
+		wantDiscount int64
+		wantTotal    int64
+		wantSkipped  int
+	}{
+		{name: "no promotions", quantity: 2, wantTotal: 20000},
+		{
+			name:         "percentage",
+			quantity:     3,
+			promotions:   []models.Promotion{{ID: 1, Type: models.PromotionPercentage, Percentage: 10}},
+			wantDiscount: 3000, wantTotal: 27000,
+		},
+		{
+			name:         "fixed per unit",
+			quantity:     2,
+			promotions:   []models.Promotion{{ID: 1, Type: models.PromotionFixed, AmountMinor: 1500, Currency: "IDR"}},
+			wantDiscount: 3000, wantTotal: 17000,
+		},
+		{
+			name:       "fixed in another currency is skipped",
+			quantity:   2,
+			promotions: []models.Promotion{{ID: 1, Type: models.PromotionFixed, AmountMinor: 100, Currency: "USD"}},
+			wantTotal:  20000, wantSkipped: 1,
+		},
+		{
+			name:         "buy 2 get 1",
+			quantity:     7,
+			promotions:   []models.Promotion{{ID: 1, Type: models.PromotionBuyXGetY, BuyQuantity: 2, GetQuantity: 1}},
+			wantDiscount: 20000, wantTotal: 50000,
+		},
+		{
+			name:       "buy 2 get 1 below the threshold",
+			quantity:   2,
+			promotions: []models.Promotion{{ID: 1, Type: models.PromotionBuyXGetY, BuyQuantity: 2, GetQuantity: 1}},
+			wantTotal:  20000, wantSkipped: 1,
+		},
+		{
+			name:       "minimum quantity not reached",
+			quantity:   2,
+			promotions: []models.Promotion{{ID: 1, Type: models.PromotionPercentage, Percentage: 10, MinQuantity: 5}},
+			wantTotal:  20000, wantSkipped: 1,
+		},
+		{
+			name:     "stacked in priority order",
+			quantity: 1,
+			promotions: []models.Promotion{
+				{ID: 1, Type: models.PromotionFixed, AmountMinor: 1000, Currency: "IDR"},
+				{ID: 2, Type: models.PromotionPercentage, Percentage: 10, Priority: 1},
+			},
+			wantDiscount: 2000, wantTotal: 8000,
+		},
+		{
+			name:     "exclusive first blocks the rest",
+			quantity: 1,
+			promotions: []models.Promotion{
+				{ID: 1, Type: models.PromotionPercentage, Percentage: 10, Priority: 1},
+				{ID: 2, Type: models.PromotionPercentage, Percentage: 20, Priority: 2, Exclusive: true},
+			},
+			wantDiscount: 2000, wantTotal: 8000, wantSkipped: 1,
+		},
+		{
+			name:     "exclusive after another promotion is skipped",
+			quantity: 1,
+			promotions: []models.Promotion{
+				{ID: 1, Type: models.PromotionPercentage, Percentage: 10, Priority: 2},
+				{ID: 2, Type: models.PromotionPercentage, Percentage: 50, Priority: 1, Exclusive: true},
+			},
+			wantDiscount: 1000, wantTotal: 9000, wantSkipped: 1,
+		},
+		{
+			name:         "discount capped at the subtotal",
+			quantity:     1,
+			promotions:   []models.Promotion{{ID: 1, Type: models.PromotionFixed, AmountMinor: 15000, Currency: "IDR"}},
+			wantDiscount: 10000, wantTotal: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			quote := evaluatePromotions(product, tt.quantity, tt.promotions)
+			if quote.DiscountMinor != tt.wantDiscount || quote.TotalMinor != tt.wantTotal {
+				t.Errorf("discount, total = %d, %d, want %d, %d", quote.DiscountMinor, quote.TotalMinor, tt.wantDiscount, tt.wantTotal)
+			}
+			if len(quote.Skipped) != tt.wantSkipped {
+				t.Errorf("skipped %d promotions (%v), want %d", len(quote.Skipped), quote.Skipped, tt.wantSkipped)
+			}
+			if quote.SubtotalMinor-quote.DiscountMinor != quote.TotalMinor {
+				t.Errorf("subtotal %d - discount %d != total %d", quote.SubtotalMinor, quote.DiscountMinor, quote.TotalMinor)
+			}
+		})
+	}
+}
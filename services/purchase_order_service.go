@@ -0,0 +1,307 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"products-api-with-jwt/models"
+	"slices"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrPurchaseOrderNotFound dikembalikan jika purchase order dengan ID yang diminta tidak ada
+	ErrPurchaseOrderNotFound = errors.New("purchase order not found")
+	// ErrInvalidPurchaseOrderStatus dikembalikan jika aksi tidak diizinkan pada status purchase order saat ini
+	ErrInvalidPurchaseOrderStatus = errors.New("action is not allowed in the purchase order's current status")
+	// ErrInvalidPurchaseOrderProduct dikembalikan jika produk tidak bisa dibeli dari supplier, misalnya bundle
+	ErrInvalidPurchaseOrderProduct = errors.New("bundles cannot be purchased, purchase their components instead")
+	// ErrUnitCostRequired dikembalikan jika unit_cost kosong dan produk belum punya harga beli di supplier
+	ErrUnitCostRequired = errors.New("unit_cost is required for products that are not linked to the supplier")
+	// ErrDuplicatePurchaseOrderLine dikembalikan jika satu produk muncul di lebih dari satu baris
+	ErrDuplicatePurchaseOrderLine = errors.New("each product can only appear once in a purchase order")
+	// ErrPurchaseOrderLineNotFound dikembalikan jika line_id bukan baris purchase order ini
+	ErrPurchaseOrderLineNotFound = errors.New("purchase order line not found")
+	// ErrOverReceipt dikembalikan jika jumlah yang diterima melebihi jumlah yang dipesan
+	ErrOverReceipt = errors.New("received quantity cannot exceed the ordered quantity")
+)
+
+type PurchaseOrderService struct {
+	DB             *gorm.DB
+	ProductService *ProductService
+}
+
+// NewPurchaseOrderService menginisialisasi PurchaseOrderService baru
+func NewPurchaseOrderService(db *gorm.DB, productService *ProductService) *PurchaseOrderService {
+	return &PurchaseOrderService{DB: db, ProductService: productService}
+}
+
+// GetPurchaseOrders mengambil purchase order terbaru lebih dulu, opsional difilter status dan supplier
+func (s *PurchaseOrderService) GetPurchaseOrders(status string, supplierID int) ([]models.PurchaseOrder, error) {
+	query := s.DB.Preload("Supplier").Order("id desc")
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if supplierID != 0 {
+		query = query.Where("supplier_id = ?", supplierID)
+	}
+	var orders []models.PurchaseOrder
+	if err := query.Find(&orders).Error; err != nil {
+		return nil, err
+	}
+	return orders, nil
+}
+
+// GetPurchaseOrderByID mengambil purchase order beserta supplier dan barisnya
+func (s *PurchaseOrderService) GetPurchaseOrderByID(id int) (*models.PurchaseOrder, error) {
+	return findPurchaseOrder(s.DB.Preload("Supplier"), id)
+}
+
+// CreatePurchaseOrder membuat purchase order baru sebagai draft
+func (s *PurchaseOrderService) CreatePurchaseOrder(input *models.PurchaseOrderInput, userID int) (*models.PurchaseOrder, error) {
+	var order models.PurchaseOrder
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		supplier, err := findSupplier(tx, input.SupplierID)
+		if err != nil {
+			return err
+		}
+		lines, total, err := purchaseOrderLines(tx, supplier, input.Lines)
+		if err != nil {
+			return err
+		}
+		order = models.PurchaseOrder{
+			SupplierID: supplier.ID,
+			Status:     models.PurchaseOrderDraft,
+			Currency:   supplier.Currency,
+			Notes:      strings.TrimSpace(input.Notes),
+			TotalMinor: total,
+			Lines:      lines,
+			UserID:     userID,
+		}
+		return tx.Create(&order).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetPurchaseOrderByID(order.ID)
+}
+
+// UpdatePurchaseOrder mengganti supplier, catatan dan semua baris purchase order yang masih draft
+func (s *PurchaseOrderService) UpdatePurchaseOrder(id int, input *models.PurchaseOrderInput) (*models.PurchaseOrder, error) {
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		order, err := findPurchaseOrder(tx, id)
+		if err != nil {
+			return err
+		}
+		if order.Status != models.PurchaseOrderDraft {
+			return ErrInvalidPurchaseOrderStatus
+		}
+		supplier, err := findSupplier(tx, input.SupplierID)
+		if err != nil {
+			return err
+		}
+		lines, total, err := purchaseOrderLines(tx, supplier, input.Lines)
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Where("purchase_order_id = ?", id).Delete(&models.PurchaseOrderLine{}).Error; err != nil {
+			return err
+		}
+		for i := range lines {
+			lines[i].PurchaseOrderID = id
+		}
+		if err := tx.Create(&lines).Error; err != nil {
+			return err
+		}
+		order.SupplierID = supplier.ID
+		order.Currency = supplier.Currency
+		order.Notes = strings.TrimSpace(input.Notes)
+		order.TotalMinor = total
+		return tx.Model(order).Select("supplier_id", "currency", "notes", "total_minor").Updates(order).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetPurchaseOrderByID(id)
+}
+
+// SendPurchaseOrder menandai draft sudah dikirim ke supplier dan menghitung perkiraan tanggal barang tiba
+func (s *PurchaseOrderService) SendPurchaseOrder(id int) (*models.PurchaseOrder, error) {
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		order, err := findPurchaseOrder(tx, id)
+		if err != nil {
+			return err
+		}
+		if order.Status != models.PurchaseOrderDraft {
+			return ErrInvalidPurchaseOrderStatus
+		}
+
+		productIDs := make([]int, len(order.Lines))
+		for i, line := range order.Lines {
+			productIDs[i] = line.ProductID
+		}
+		var leadTime int
+		err = tx.Model(&models.SupplierProduct{}).
+			Select("COALESCE(MAX(lead_time_days), 0)").
+			Where("supplier_id = ? AND product_id IN ?", order.SupplierID, productIDs).
+			Scan(&leadTime).Error
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		expected := now.AddDate(0, 0, leadTime)
+		order.Status = models.PurchaseOrderSent
+		order.SentAt = &now
+		order.ExpectedAt = &expected
+		return tx.Model(order).Select("status", "sent_at", "expected_at").Updates(order).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetPurchaseOrderByID(id)
+}
+
+// CancelPurchaseOrder membatalkan purchase order yang belum diterima sama sekali
+func (s *PurchaseOrderService) CancelPurchaseOrder(id int) (*models.PurchaseOrder, error) {
+	result := s.DB.Model(&models.PurchaseOrder{}).
+		Where("id = ? AND status IN ?", id, []string{models.PurchaseOrderDraft, models.PurchaseOrderSent}).
+		Update("status", models.PurchaseOrderCancelled)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		if _, err := findPurchaseOrder(s.DB, id); err != nil {
+			return nil, err
+		}
+		return nil, ErrInvalidPurchaseOrderStatus
+	}
+	return s.GetPurchaseOrderByID(id)
+}
+
+// ReceivePurchaseOrder mencatat barang yang diterima. Setiap baris diposting sebagai receipt di ledger stok
+// dalam satu transaksi, lalu status menjadi partially_received atau received jika semua baris sudah lengkap.
+func (s *PurchaseOrderService) ReceivePurchaseOrder(id int, input *models.ReceivePurchaseOrderInput, userID int) (*models.PurchaseOrder, error) {
+	var productIDs []int
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		order, err := findPurchaseOrder(tx, id)
+		if err != nil {
+			return err
+		}
+		if order.Status != models.PurchaseOrderSent && order.Status != models.PurchaseOrderPartiallyReceived {
+			return ErrInvalidPurchaseOrderStatus
+		}
+
+		for _, received := range input.Lines {
+			index := slices.IndexFunc(order.Lines, func(line models.PurchaseOrderLine) bool { return line.ID == received.LineID })
+			if index < 0 {
+				return ErrPurchaseOrderLineNotFound
+			}
+			line := &order.Lines[index]
+
+			// Update kondisional agar penerimaan bersamaan tidak melebihi jumlah yang dipesan
+			result := tx.Model(&models.PurchaseOrderLine{}).
+				Where("id = ? AND received_quantity + ? <= quantity", line.ID, received.Quantity).
+				Update("received_quantity", gorm.Expr("received_quantity + ?", received.Quantity))
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected == 0 {
+				return ErrOverReceipt
+			}
+			line.ReceivedQuantity += received.Quantity
+
+			movement := models.StockMovement{
+				ProductID:   line.ProductID,
+				WarehouseID: input.WarehouseID,
+				Type:        models.StockMovementReceipt,
+				Quantity:    received.Quantity,
+				UserID:      userID,
+				Reason:      "purchase order received",
+				Reference:   fmt.Sprintf("po:%d", order.ID),
+			}
+			if _, err := s.ProductService.postStockMovement(tx, movement); err != nil {
+				return err
+			}
+			productIDs = append(productIDs, line.ProductID)
+		}
+
+		order.Status = models.PurchaseOrderReceived
+		for _, line := range order.Lines {
+			if line.ReceivedQuantity < line.Quantity {
+				order.Status = models.PurchaseOrderPartiallyReceived
+				break
+			}
+		}
+		if order.Status == models.PurchaseOrderReceived {
+			now := time.Now()
+			order.ReceivedAt = &now
+		}
+		return tx.Model(order).Select("status", "received_at").Updates(order).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.ProductService.evaluateLowStock(uniqueInts(productIDs)...)
+	return s.GetPurchaseOrderByID(id)
+}
+
+// purchaseOrderLines menyusun baris purchase order. Tanpa unit_cost, harga beli produk di supplier dipakai.
+func purchaseOrderLines(tx *gorm.DB, supplier *models.Supplier, inputs []models.PurchaseOrderLineInput) ([]models.PurchaseOrderLine, int64, error) {
+	lines := make([]models.PurchaseOrderLine, 0, len(inputs))
+	var total int64
+	for _, input := range inputs {
+		if slices.ContainsFunc(lines, func(line models.PurchaseOrderLine) bool { return line.ProductID == input.ProductID }) {
+			return nil, 0, ErrDuplicatePurchaseOrderLine
+		}
+		product, err := findProduct(tx, input.ProductID)
+		if err != nil {
+			return nil, 0, err
+		}
+		if product.Type == models.ProductBundle {
+			return nil, 0, ErrInvalidPurchaseOrderProduct
+		}
+
+		var cost int64
+		if input.UnitCost != nil {
+			cost = models.ToMinor(*input.UnitCost, supplier.Currency)
+		} else {
+			var link models.SupplierProduct
+			err := tx.Where("supplier_id = ? AND product_id = ?", supplier.ID, input.ProductID).First(&link).Error
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, 0, ErrUnitCostRequired
+			}
+			if err != nil {
+				return nil, 0, err
+			}
+			cost = link.CostMinor
+		}
+
+		lines = append(lines, models.PurchaseOrderLine{
+			ProductID:     input.ProductID,
+			Quantity:      input.Quantity,
+			UnitCostMinor: cost,
+			UnitCost:      models.FromMinor(cost, supplier.Currency),
+			Currency:      supplier.Currency,
+		})
+		total += cost * int64(input.Quantity)
+	}
+	return lines, total, nil
+}
+
+func findPurchaseOrder(tx *gorm.DB, id int) (*models.PurchaseOrder, error) {
+	var order models.PurchaseOrder
+	err := tx.Preload("Lines", func(db *gorm.DB) *gorm.DB {
+		return db.Order("id")
+	}).First(&order, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrPurchaseOrderNotFound
+		}
+		return nil, err
+	}
+	return &order, nil
+}
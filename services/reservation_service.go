@@ -0,0 +1,218 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"products-api-with-jwt/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrReservationNotFound dikembalikan jika reservation tidak ada untuk produk tersebut atau bukan milik user yang meminta
+	ErrReservationNotFound = errors.New("reservation not found")
+	// ErrReservationNotActive dikembalikan jika reservation sudah dikonfirmasi, dilepas, atau kedaluwarsa
+	ErrReservationNotActive = errors.New("reservation is no longer held")
+)
+
+type ReservationService struct {
+	DB             *gorm.DB
+	ProductService *ProductService
+	DefaultTTL     time.Duration
+}
+
+// NewReservationService menginisialisasi ReservationService baru
+func NewReservationService(db *gorm.DB, productService *ProductService, defaultTTL time.Duration) *ReservationService {
+	return &ReservationService{DB: db, ProductService: productService, DefaultTTL: defaultTTL}
+}
+
+// Reserve menahan stok untuk sementara. Update kondisional di dalam transaksi memastikan
+// stok tidak oversold walaupun ada beberapa reservation bersamaan.
+func (s *ReservationService) Reserve(productID int, input *models.ReservationInput, userID int) (*models.Reservation, error) {
+	ttl := s.DefaultTTL
+	if input.TTLSeconds > 0 {
+		ttl = time.Duration(input.TTLSeconds) * time.Second
+	}
+
+	reservation := models.Reservation{
+		ProductID: productID,
+		Quantity:  input.Quantity,
+		Status:    models.ReservationHeld,
+		UserID:    userID,
+		Reference: input.Reference,
+		ExpiresAt: time.Now().Add(ttl),
+	}
+
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		// Reservation bundle menahan stok semua komponennya sekaligus
+		lines, err := stockLines(tx, productID, input.Quantity)
+		if err != nil {
+			return err
+		}
+		for _, line := range lines {
+			result := tx.Model(&models.Product{}).
+				Where("id = ? AND stok - reserved >= ?", line.ProductID, line.Quantity).
+				Update("reserved", gorm.Expr("reserved + ?", line.Quantity))
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected == 0 {
+				return ErrInsufficientStock
+			}
+		}
+		return tx.Create(&reservation).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &reservation, nil
+}
+
+// Confirm mengubah reservation menjadi penjualan: stok yang ditahan dikurangi lewat ledger.
+// Untuk bundle, stok setiap komponen yang dikurangi. User biasa hanya boleh mengonfirmasi reservation miliknya.
+func (s *ReservationService) Confirm(productID, reservationID, userID int, admin bool) (*models.Reservation, error) {
+	var reservation models.Reservation
+	var movements []models.StockMovement
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		if err := s.finish(tx, productID, reservationID, reservationOwner(userID, admin), models.ReservationConfirmed, &reservation); err != nil {
+			return err
+		}
+
+		movement := models.StockMovement{
+			ProductID: productID,
+			Type:      models.StockMovementSale,
+			Quantity:  -reservation.Quantity,
+			UserID:    userID,
+			Reason:    "reservation confirmed",
+			Reference: reservationReference(&reservation),
+		}
+		var err error
+		movements, err = s.ProductService.postProductMovement(tx, movement)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.ProductService.evaluateLowStock(movedProductIDs(movements)...)
+	return &reservation, nil
+}
+
+// Release melepas stok yang ditahan tanpa mengubah stok fisik. User biasa hanya boleh melepas reservation miliknya.
+func (s *ReservationService) Release(productID, reservationID, userID int, admin bool) (*models.Reservation, error) {
+	var reservation models.Reservation
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		return s.finish(tx, productID, reservationID, reservationOwner(userID, admin), models.ReservationReleased, &reservation)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &reservation, nil
+}
+
+// ExpireReservations mengembalikan stok dari reservation yang sudah lewat TTL
+func (s *ReservationService) ExpireReservations() (int, error) {
+	var expired []models.Reservation
+	if err := s.DB.Where("status = ? AND expires_at <= ?", models.ReservationHeld, time.Now()).Find(&expired).Error; err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for _, r := range expired {
+		var reservation models.Reservation
+		err := s.DB.Transaction(func(tx *gorm.DB) error {
+			return s.finish(tx, r.ProductID, r.ID, 0, models.ReservationExpired, &reservation)
+		})
+		if errors.Is(err, ErrReservationNotActive) {
+			// Sudah dikonfirmasi atau dilepas oleh request lain
+			continue
+		}
+		if err != nil {
+			return count, err
+		}
+		count++
+	}
+	return count, nil
+}
+
+// StartReservationExpirer menjalankan ExpireReservations secara berkala di background
+func (s *ReservationService) StartReservationExpirer(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			expired, err := s.ExpireReservations()
+			if err != nil {
+				log.Printf("Could not expire reservations: %v", err)
+				continue
+			}
+			if expired > 0 {
+				log.Printf("Expired %d reservations", expired)
+			}
+		}
+	}()
+}
+
+// finish memindahkan reservation dari status held ke status akhir dan melepas stok yang ditahan.
+// Update status bersifat kondisional sehingga satu reservation hanya bisa diselesaikan sekali.
+// ownerID 0 berarti reservation milik siapa pun.
+func (s *ReservationService) finish(tx *gorm.DB, productID, reservationID, ownerID int, status string, reservation *models.Reservation) error {
+	lookup := tx.Where("id = ? AND product_id = ?", reservationID, productID)
+	if ownerID != 0 {
+		lookup = lookup.Where("user_id = ?", ownerID)
+	}
+	if err := lookup.First(reservation).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrReservationNotFound
+		}
+		return err
+	}
+
+	query := tx.Model(&models.Reservation{}).Where("id = ? AND status = ?", reservationID, models.ReservationHeld)
+	if status == models.ReservationConfirmed {
+		// Reservation yang sudah lewat TTL tidak bisa dikonfirmasi walaupun expirer belum berjalan
+		query = query.Where("expires_at > ?", time.Now())
+	}
+	result := query.Update("status", status)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrReservationNotActive
+	}
+	reservation.Status = status
+
+	// Unscoped agar stok tetap dilepas walaupun produk sudah di-soft delete. Session agar kondisi
+	// query tidak terbawa antar pemakaian.
+	unscoped := tx.Unscoped().Session(&gorm.Session{})
+	lines, err := stockLines(unscoped, productID, reservation.Quantity)
+	if err != nil {
+		return err
+	}
+	for _, line := range lines {
+		err := unscoped.Model(&models.Product{}).
+			Where("id = ?", line.ProductID).
+			Update("reserved", gorm.Expr("reserved - ?", line.Quantity)).Error
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// reservationOwner mengembalikan user yang reservation-nya boleh diselesaikan, 0 untuk admin
+func reservationOwner(userID int, admin bool) int {
+	if admin {
+		return 0
+	}
+	return userID
+}
+
+func reservationReference(reservation *models.Reservation) string {
+	if reservation.Reference != "" {
+		return reservation.Reference
+	}
+	return fmt.Sprintf("reservation:%d", reservation.ID)
+}
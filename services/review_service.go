@@ -0,0 +1,195 @@
+package services
+
+import (
+	"errors"
+	"products-api-with-jwt/models"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrReviewNotFound dikembalikan jika review tidak ada, bukan milik produk tersebut, atau bukan milik user
+	ErrReviewNotFound = errors.New("review not found")
+	// ErrDuplicateReview dikembalikan jika user sudah pernah menulis review untuk produk tersebut
+	ErrDuplicateReview = errors.New("you have already reviewed this product, update your review instead")
+)
+
+type ReviewService struct {
+	DB *gorm.DB
+}
+
+// NewReviewService menginisialisasi ReviewService baru
+func NewReviewService(db *gorm.DB) *ReviewService {
+	return &ReviewService{DB: db}
+}
+
+// GetProductReviews mengambil review produk terbaru lebih dulu, default hanya yang approved
+func (s *ReviewService) GetProductReviews(productID int, status string) ([]models.Review, error) {
+	if _, err := findProduct(s.DB, productID); err != nil {
+		return nil, err
+	}
+	if status == "" {
+		status = models.ReviewApproved
+	}
+	var reviews []models.Review
+	if err := s.DB.Where("product_id = ? AND status = ?", productID, status).Order("id desc").Find(&reviews).Error; err != nil {
+		return nil, err
+	}
+	return reviews, nil
+}
+
+// GetReviews mengambil review semua produk untuk moderasi, yang paling lama menunggu lebih dulu
+func (s *ReviewService) GetReviews(status string) ([]models.Review, error) {
+	query := s.DB.Order("id")
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	var reviews []models.Review
+	if err := query.Find(&reviews).Error; err != nil {
+		return nil, err
+	}
+	return reviews, nil
+}
+
+// CreateReview menyimpan review baru sebagai pending sampai dimoderasi admin
+func (s *ReviewService) CreateReview(productID int, input *models.ReviewInput, userID int) (*models.Review, error) {
+	review := models.Review{
+		ProductID: productID,
+		UserID:    userID,
+		Rating:    input.Rating,
+		Title:     strings.TrimSpace(input.Title),
+		Body:      strings.TrimSpace(input.Body),
+		Status:    models.ReviewPending,
+	}
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		product, err := findProduct(tx, productID)
+		if err != nil {
+			return err
+		}
+		if product.Status != models.ProductPublished {
+			return ErrProductNotAvailable
+		}
+		var count int64
+		if err := tx.Model(&models.Review{}).Where("product_id = ? AND user_id = ?", productID, userID).Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return ErrDuplicateReview
+		}
+		if err := tx.Model(&models.User{}).Select("username").Where("id = ?", userID).Scan(&review.Username).Error; err != nil {
+			return err
+		}
+		return tx.Create(&review).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &review, nil
+}
+
+// UpdateReview mengubah review milik user. Review yang diubah kembali pending dan
+// keluar dari rating produk sampai dimoderasi lagi.
+func (s *ReviewService) UpdateReview(productID, reviewID int, input *models.ReviewInput, userID int) (*models.Review, error) {
+	var review *models.Review
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		if review, err = findReview(tx, productID, reviewID, userID); err != nil {
+			return err
+		}
+		review.Rating = input.Rating
+		review.Title = strings.TrimSpace(input.Title)
+		review.Body = strings.TrimSpace(input.Body)
+		review.Status = models.ReviewPending
+		review.ModerationNote = ""
+		review.ModeratedBy = nil
+		review.ModeratedAt = nil
+		err = tx.Model(review).
+			Select("rating", "title", "body", "status", "moderation_note", "moderated_by", "moderated_at").
+			Updates(review).Error
+		if err != nil {
+			return err
+		}
+		return refreshProductRating(tx, productID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return review, nil
+}
+
+// DeleteReview menghapus review. userID 0 berarti admin yang boleh menghapus review siapa pun.
+func (s *ReviewService) DeleteReview(productID, reviewID, userID int) error {
+	return s.DB.Transaction(func(tx *gorm.DB) error {
+		review, err := findReview(tx, productID, reviewID, userID)
+		if err != nil {
+			return err
+		}
+		if err := tx.Delete(review).Error; err != nil {
+			return err
+		}
+		return refreshProductRating(tx, productID)
+	})
+}
+
+// ModerateReview menyetujui atau menolak review lalu menghitung ulang rating produknya
+func (s *ReviewService) ModerateReview(id int, input *models.ReviewModerationInput, userID int) (*models.Review, error) {
+	var review models.Review
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&review, id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrReviewNotFound
+			}
+			return err
+		}
+		now := time.Now()
+		review.Status = input.Status
+		review.ModerationNote = strings.TrimSpace(input.Note)
+		review.ModeratedBy = &userID
+		review.ModeratedAt = &now
+		err := tx.Model(&review).Select("status", "moderation_note", "moderated_by", "moderated_at").Updates(&review).Error
+		if err != nil {
+			return err
+		}
+		return refreshProductRating(tx, review.ProductID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &review, nil
+}
+
+// refreshProductRating menghitung ulang RatingAverage dan RatingCount produk dari review approved.
+// Dihitung dari agregat, bukan ditambah atau dikurangi, sehingga selalu konsisten dengan isi tabel review.
+func refreshProductRating(tx *gorm.DB, productID int) error {
+	var stats struct {
+		Count   int
+		Average float64
+	}
+	err := tx.Model(&models.Review{}).
+		Select("COUNT(*) AS count, COALESCE(ROUND(AVG(rating), 2), 0) AS average").
+		Where("product_id = ? AND status = ?", productID, models.ReviewApproved).
+		Scan(&stats).Error
+	if err != nil {
+		return err
+	}
+	return tx.Unscoped().Model(&models.Product{}).Where("id = ?", productID).
+		Updates(map[string]any{"rating_count": stats.Count, "rating_average": stats.Average}).Error
+}
+
+// findReview mengambil review produk. userID selain 0 membatasi ke review milik user tersebut.
+func findReview(tx *gorm.DB, productID, reviewID, userID int) (*models.Review, error) {
+	query := tx.Where("product_id = ?", productID)
+	if userID != 0 {
+		query = query.Where("user_id = ?", userID)
+	}
+	var review models.Review
+	if err := query.First(&review, reviewID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrReviewNotFound
+		}
+		return nil, err
+	}
+	return &review, nil
+}
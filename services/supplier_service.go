@@ -0,0 +1,201 @@
+package services
+
+import (
+	"errors"
+	"products-api-with-jwt/models"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrSupplierNotFound dikembalikan jika supplier dengan ID yang diminta tidak ada
+	ErrSupplierNotFound = errors.New("supplier not found")
+	// ErrDuplicateSupplierName dikembalikan jika nama supplier sudah dipakai
+	ErrDuplicateSupplierName = errors.New("supplier name already exists")
+	// ErrSupplierCurrencyChange dikembalikan jika mata uang supplier diubah setelah dibuat
+	ErrSupplierCurrencyChange = errors.New("supplier currency cannot be changed")
+	// ErrSupplierInUse dikembalikan jika supplier yang akan dihapus masih punya purchase order
+	ErrSupplierInUse = errors.New("supplier still has purchase orders")
+	// ErrSupplierProductNotFound dikembalikan jika produk belum dihubungkan ke supplier
+	ErrSupplierProductNotFound = errors.New("product is not linked to this supplier")
+)
+
+type SupplierService struct {
+	DB *gorm.DB
+}
+
+// NewSupplierService menginisialisasi SupplierService baru
+func NewSupplierService(db *gorm.DB) *SupplierService {
+	return &SupplierService{DB: db}
+}
+
+// GetAllSuppliers mengambil semua supplier
+func (s *SupplierService) GetAllSuppliers() ([]models.Supplier, error) {
+	var suppliers []models.Supplier
+	if err := s.DB.Order("name").Find(&suppliers).Error; err != nil {
+		return nil, err
+	}
+	return suppliers, nil
+}
+
+// GetSupplierByID mengambil supplier berdasarkan ID
+func (s *SupplierService) GetSupplierByID(id int) (*models.Supplier, error) {
+	return findSupplier(s.DB, id)
+}
+
+// CreateSupplier menambah supplier baru
+func (s *SupplierService) CreateSupplier(input *models.SupplierInput) (*models.Supplier, error) {
+	supplier := models.Supplier{
+		Name:     strings.TrimSpace(input.Name),
+		Email:    strings.TrimSpace(input.Email),
+		Phone:    strings.TrimSpace(input.Phone),
+		Address:  input.Address,
+		Currency: input.Currency,
+	}
+	if supplier.Currency == "" {
+		supplier.Currency = models.DefaultCurrency
+	}
+
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		if err := checkUniqueSupplierName(tx, supplier.Name, 0); err != nil {
+			return err
+		}
+		return tx.Create(&supplier).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &supplier, nil
+}
+
+// UpdateSupplier memperbarui data supplier. Mata uang tidak bisa diubah karena dipakai harga beli yang tersimpan.
+func (s *SupplierService) UpdateSupplier(id int, input *models.SupplierInput) (*models.Supplier, error) {
+	var supplier *models.Supplier
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		if supplier, err = findSupplier(tx, id); err != nil {
+			return err
+		}
+		if input.Currency != "" && input.Currency != supplier.Currency {
+			return ErrSupplierCurrencyChange
+		}
+		supplier.Name = strings.TrimSpace(input.Name)
+		supplier.Email = strings.TrimSpace(input.Email)
+		supplier.Phone = strings.TrimSpace(input.Phone)
+		supplier.Address = input.Address
+		if err := checkUniqueSupplierName(tx, supplier.Name, supplier.ID); err != nil {
+			return err
+		}
+		return tx.Model(supplier).Select("name", "email", "phone", "address").Updates(supplier).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return supplier, nil
+}
+
+// DeleteSupplier menghapus supplier yang belum punya purchase order beserta link produknya
+func (s *SupplierService) DeleteSupplier(id int) error {
+	return s.DB.Transaction(func(tx *gorm.DB) error {
+		if _, err := findSupplier(tx, id); err != nil {
+			return err
+		}
+		var orders int64
+		if err := tx.Model(&models.PurchaseOrder{}).Where("supplier_id = ?", id).Count(&orders).Error; err != nil {
+			return err
+		}
+		if orders > 0 {
+			return ErrSupplierInUse
+		}
+		if err := tx.Where("supplier_id = ?", id).Delete(&models.SupplierProduct{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.Supplier{}, id).Error
+	})
+}
+
+// GetSupplierProducts mengambil produk yang bisa dibeli dari supplier
+func (s *SupplierService) GetSupplierProducts(supplierID int) ([]models.SupplierProduct, error) {
+	if _, err := findSupplier(s.DB, supplierID); err != nil {
+		return nil, err
+	}
+	var links []models.SupplierProduct
+	if err := s.DB.Preload("Product").Where("supplier_id = ?", supplierID).Order("product_id").Find(&links).Error; err != nil {
+		return nil, err
+	}
+	return links, nil
+}
+
+// SetSupplierProduct menghubungkan produk ke supplier atau mengganti harga beli dan lead time-nya
+func (s *SupplierService) SetSupplierProduct(supplierID, productID int, input *models.SupplierProductInput) (*models.SupplierProduct, error) {
+	var link models.SupplierProduct
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		supplier, err := findSupplier(tx, supplierID)
+		if err != nil {
+			return err
+		}
+		product, err := findProduct(tx, productID)
+		if err != nil {
+			return err
+		}
+		if product.Type == models.ProductBundle {
+			return ErrInvalidPurchaseOrderProduct
+		}
+
+		err = tx.Where("supplier_id = ? AND product_id = ?", supplierID, productID).First(&link).Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		link.SupplierID = supplierID
+		link.ProductID = productID
+		link.SupplierSKU = strings.TrimSpace(input.SupplierSKU)
+		link.Currency = supplier.Currency
+		link.CostMinor = models.ToMinor(input.Cost, supplier.Currency)
+		link.Cost = models.FromMinor(link.CostMinor, supplier.Currency)
+		link.LeadTimeDays = input.LeadTimeDays
+		return tx.Save(&link).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &link, nil
+}
+
+// DeleteSupplierProduct melepas produk dari supplier
+func (s *SupplierService) DeleteSupplierProduct(supplierID, productID int) error {
+	if _, err := findSupplier(s.DB, supplierID); err != nil {
+		return err
+	}
+	result := s.DB.Where("supplier_id = ? AND product_id = ?", supplierID, productID).Delete(&models.SupplierProduct{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrSupplierProductNotFound
+	}
+	return nil
+}
+
+func findSupplier(tx *gorm.DB, id int) (*models.Supplier, error) {
+	var supplier models.Supplier
+	if err := tx.First(&supplier, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrSupplierNotFound
+		}
+		return nil, err
+	}
+	return &supplier, nil
+}
+
+// checkUniqueSupplierName memastikan nama supplier unik (case-insensitive)
+func checkUniqueSupplierName(tx *gorm.DB, name string, excludeID int) error {
+	var count int64
+	if err := tx.Model(&models.Supplier{}).Where("LOWER(name) = LOWER(?) AND id <> ?", name, excludeID).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrDuplicateSupplierName
+	}
+	return nil
+}
@@ -0,0 +1,123 @@
+package services
+
+import (
+	"errors"
+	"products-api-with-jwt/models"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type TagService struct {
+	DB *gorm.DB
+}
+
+// NewTagService menginisialisasi TagService baru
+func NewTagService(db *gorm.DB) *TagService {
+	return &TagService{DB: db}
+}
+
+// GetAllTags mengambil semua tag beserta jumlah produk (yang tidak di-soft delete) yang memakainya
+func (s *TagService) GetAllTags() ([]models.TagUsage, error) {
+	var tags []models.TagUsage
+	err := s.DB.Table("tags").
+		Select("tags.id, tags.name, COUNT(products.id) AS product_count").
+		Joins("LEFT JOIN product_tags ON product_tags.tag_id = tags.id").
+		Joins("LEFT JOIN products ON products.id = product_tags.product_id AND products.deleted_at IS NULL").
+		Group("tags.id, tags.name").
+		Order("product_count desc, tags.name").
+		Scan(&tags).Error
+	if err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
+
+// AddProductTags menambahkan tag ke produk. Tag yang belum ada dibuat otomatis.
+func (s *TagService) AddProductTags(productID int, names []string) ([]models.Tag, error) {
+	var tags []models.Tag
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		product, err := findProduct(tx, productID)
+		if err != nil {
+			return err
+		}
+
+		newTags := make([]models.Tag, 0, len(names))
+		for _, name := range NormalizeTags(names) {
+			newTags = append(newTags, models.Tag{Name: name})
+		}
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&newTags).Error; err != nil {
+			return err
+		}
+
+		var found []models.Tag
+		if err := tx.Where("name IN ?", NormalizeTags(names)).Find(&found).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(product).Omit("Tags.*").Association("Tags").Append(found); err != nil {
+			return err
+		}
+		return tx.Model(product).Association("Tags").Find(&tags)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
+
+// RemoveProductTags melepas tag dari produk. Tag yang tidak lagi dipakai produk mana pun dihapus.
+func (s *TagService) RemoveProductTags(productID int, names []string) ([]models.Tag, error) {
+	var tags []models.Tag
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		product, err := findProduct(tx, productID)
+		if err != nil {
+			return err
+		}
+
+		var found []models.Tag
+		if err := tx.Where("name IN ?", NormalizeTags(names)).Find(&found).Error; err != nil {
+			return err
+		}
+		if len(found) > 0 {
+			if err := tx.Model(product).Association("Tags").Delete(found); err != nil {
+				return err
+			}
+			if err := tx.Exec("DELETE FROM tags WHERE id NOT IN (SELECT tag_id FROM product_tags)").Error; err != nil {
+				return err
+			}
+		}
+		return tx.Model(product).Association("Tags").Find(&tags)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
+
+// NormalizeTags mengubah tag menjadi huruf kecil, mengganti spasi dengan "-", dan membuang duplikat
+func NormalizeTags(names []string) []string {
+	seen := make(map[string]bool, len(names))
+	normalized := make([]string, 0, len(names))
+	for _, name := range names {
+		name = strings.Join(strings.Fields(strings.ToLower(name)), "-")
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		normalized = append(normalized, name)
+	}
+	return normalized
+}
+
+// findProduct mengambil produk di dalam transaksi, mengembalikan ErrProductNotFound jika tidak ada
+func findProduct(tx *gorm.DB, id int) (*models.Product, error) {
+	var product models.Product
+	if err := tx.First(&product, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrProductNotFound
+		}
+		return nil, err
+	}
+	return &product, nil
+}
@@ -0,0 +1,249 @@
+package services
+
+import (
+	"errors"
+	"math"
+	"products-api-with-jwt/models"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrTaxClassNotFound dikembalikan jika tax class dengan ID yang diminta tidak ada
+	ErrTaxClassNotFound = errors.New("tax class not found")
+	// ErrDuplicateTaxClassCode dikembalikan jika kode tax class sudah dipakai
+	ErrDuplicateTaxClassCode = errors.New("tax class code already exists")
+	// ErrTaxClassInUse dikembalikan jika tax class yang akan dihapus masih dipakai produk
+	ErrTaxClassInUse = errors.New("tax class is still assigned to products")
+	// ErrTaxRateNotFound dikembalikan jika tarif tidak ada di tax class tersebut
+	ErrTaxRateNotFound = errors.New("tax rate not found")
+	// ErrDuplicateTaxRate dikembalikan jika sudah ada tarif untuk region dan tanggal berlaku yang sama
+	ErrDuplicateTaxRate = errors.New("a rate for this region and effective date already exists")
+	// ErrInvalidTaxRegion dikembalikan jika region bukan kode ISO 3166 seperti ID atau ID-BA
+	ErrInvalidTaxRegion = errors.New("region must be an ISO 3166 code such as ID or ID-BA")
+)
+
+type TaxService struct {
+	DB *gorm.DB
+}
+
+// NewTaxService menginisialisasi TaxService baru
+func NewTaxService(db *gorm.DB) *TaxService {
+	return &TaxService{DB: db}
+}
+
+// GetAllTaxClasses mengambil semua tax class beserta tarifnya, tarif terbaru lebih dulu
+func (s *TaxService) GetAllTaxClasses() ([]models.TaxClass, error) {
+	var classes []models.TaxClass
+	err := s.DB.Preload("Rates", func(db *gorm.DB) *gorm.DB {
+		return db.Order("region, effective_from desc")
+	}).Order("code").Find(&classes).Error
+	if err != nil {
+		return nil, err
+	}
+	return classes, nil
+}
+
+// GetTaxClassByID mengambil tax class beserta tarifnya
+func (s *TaxService) GetTaxClassByID(id int) (*models.TaxClass, error) {
+	class, err := findTaxClass(s.DB.Preload("Rates", func(db *gorm.DB) *gorm.DB {
+		return db.Order("region, effective_from desc")
+	}), id)
+	if err != nil {
+		return nil, err
+	}
+	return class, nil
+}
+
+// CreateTaxClass membuat tax class baru
+func (s *TaxService) CreateTaxClass(input *models.TaxClassInput) (*models.TaxClass, error) {
+	class := models.TaxClass{
+		Code: strings.ToLower(strings.TrimSpace(input.Code)),
+		Name: strings.TrimSpace(input.Name),
+	}
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		if err := checkUniqueTaxClassCode(tx, class.Code, 0); err != nil {
+			return err
+		}
+		return tx.Create(&class).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &class, nil
+}
+
+// UpdateTaxClass mengganti kode dan nama tax class
+func (s *TaxService) UpdateTaxClass(id int, input *models.TaxClassInput) (*models.TaxClass, error) {
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		class, err := findTaxClass(tx, id)
+		if err != nil {
+			return err
+		}
+		class.Code = strings.ToLower(strings.TrimSpace(input.Code))
+		class.Name = strings.TrimSpace(input.Name)
+		if err := checkUniqueTaxClassCode(tx, class.Code, id); err != nil {
+			return err
+		}
+		return tx.Model(class).Select("code", "name").Updates(class).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetTaxClassByID(id)
+}
+
+// DeleteTaxClass menghapus tax class beserta tarifnya. Produk di tempat sampah juga dihitung,
+// agar produk yang di-restore tidak menunjuk tax class yang sudah tidak ada.
+func (s *TaxService) DeleteTaxClass(id int) error {
+	return s.DB.Transaction(func(tx *gorm.DB) error {
+		if _, err := findTaxClass(tx, id); err != nil {
+			return err
+		}
+		var count int64
+		if err := tx.Unscoped().Model(&models.Product{}).Where("tax_class_id = ?", id).Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return ErrTaxClassInUse
+		}
+		if err := tx.Where("tax_class_id = ?", id).Delete(&models.TaxRate{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.TaxClass{}, id).Error
+	})
+}
+
+// AddTaxRate menambah tarif tax class di satu region mulai tanggal tertentu, misalnya kenaikan PPN
+func (s *TaxService) AddTaxRate(classID int, input *models.TaxRateInput) (*models.TaxRate, error) {
+	region, err := normalizeTaxRegion(input.Region)
+	if err != nil {
+		return nil, err
+	}
+	rate := models.TaxRate{
+		TaxClassID:    classID,
+		Region:        region,
+		Rate:          math.Round(*input.Rate*100) / 100,
+		EffectiveFrom: input.EffectiveFrom.UTC(),
+	}
+	err = s.DB.Transaction(func(tx *gorm.DB) error {
+		if _, err := findTaxClass(tx, classID); err != nil {
+			return err
+		}
+		var count int64
+		err := tx.Model(&models.TaxRate{}).
+			Where("tax_class_id = ? AND region = ? AND effective_from = ?", classID, rate.Region, rate.EffectiveFrom).
+			Count(&count).Error
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			return ErrDuplicateTaxRate
+		}
+		return tx.Create(&rate).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &rate, nil
+}
+
+// DeleteTaxRate menghapus tarif dari tax class
+func (s *TaxService) DeleteTaxRate(classID, rateID int) error {
+	result := s.DB.Where("tax_class_id = ?", classID).Delete(&models.TaxRate{}, rateID)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrTaxRateNotFound
+	}
+	return nil
+}
+
+// normalizeTaxRegion mengubah region ke huruf besar dan memvalidasinya. Region kosong menjadi DefaultTaxRegion.
+func normalizeTaxRegion(region string) (string, error) {
+	region = strings.ToUpper(strings.TrimSpace(region))
+	if region == "" {
+		return models.DefaultTaxRegion, nil
+	}
+	if !models.ValidTaxRegion.MatchString(region) {
+		return "", ErrInvalidTaxRegion
+	}
+	return region, nil
+}
+
+// taxRateAt mencari tarif tax class yang berlaku di region pada waktu at. Tarif subdivisi (ID-BA)
+// didahulukan, jika tidak ada dipakai tarif negaranya (ID). Mengembalikan nil jika tidak ada tarif.
+func taxRateAt(db *gorm.DB, classID *int, region string, at time.Time) (*models.TaxRate, error) {
+	if classID == nil {
+		return nil, nil
+	}
+	regions := []string{region}
+	if country, _, found := strings.Cut(region, "-"); found {
+		regions = append(regions, country)
+	}
+	for _, candidate := range regions {
+		var rate models.TaxRate
+		err := db.Where("tax_class_id = ? AND region = ? AND effective_from <= ?", *classID, candidate, at.UTC()).
+			Order("effective_from desc").First(&rate).Error
+		if err == nil {
+			return &rate, nil
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+	}
+	return nil, nil
+}
+
+// applyTax mengisi pajak PriceQuote dari TotalMinor-nya dengan tarif yang berlaku saat quote.EvaluatedAt
+func applyTax(db *gorm.DB, quote *models.PriceQuote, product *models.Product, region string) error {
+	rate, err := taxRateAt(db, product.TaxClassID, region, quote.EvaluatedAt)
+	if err != nil {
+		return err
+	}
+	quote.TaxRegion = region
+	quote.PriceIncludesTax = product.PriceIncludesTax
+	if rate != nil {
+		quote.TaxRate = rate.Rate
+	}
+	quote.NetMinor, quote.TaxMinor, quote.GrossMinor = calculateTax(quote.TotalMinor, quote.TaxRate, product.PriceIncludesTax)
+	quote.Gross = models.FromMinor(quote.GrossMinor, quote.Currency)
+	return nil
+}
+
+// calculateTax memisahkan amount menjadi harga tanpa pajak, pajak, dan harga termasuk pajak.
+// Perhitungan memakai basis poin dan integer agar pembulatan half-up ke minor unit selalu tepat.
+func calculateTax(amount int64, rate float64, inclusive bool) (net, tax, gross int64) {
+	basisPoints := int64(math.Round(rate * 100))
+	if inclusive {
+		net = (amount*10000 + (10000+basisPoints)/2) / (10000 + basisPoints)
+		return net, amount - net, amount
+	}
+	tax = (amount*basisPoints + 5000) / 10000
+	return amount, tax, amount + tax
+}
+
+func findTaxClass(tx *gorm.DB, id int) (*models.TaxClass, error) {
+	var class models.TaxClass
+	if err := tx.First(&class, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrTaxClassNotFound
+		}
+		return nil, err
+	}
+	return &class, nil
+}
+
+func checkUniqueTaxClassCode(tx *gorm.DB, code string, excludeID int) error {
+	var count int64
+	if err := tx.Model(&models.TaxClass{}).Where("code = ? AND id <> ?", code, excludeID).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrDuplicateTaxClassCode
+	}
+	return nil
+}
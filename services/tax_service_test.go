@@ -0,0 +1,32 @@
+package services
+
+import "testing"
+
+func TestCalculateTax(t *testing.T) {
+	tests := []struct {
+		name             string
+		amount           int64
+		rate             float64
+		inclusive        bool
+		wantNet, wantTax int64
+		wantGross        int64
+	}{
+		{name: "exclusive", amount: 10000, rate: 11, wantNet: 10000, wantTax: 1100, wantGross: 11100},
+		{name: "inclusive", amount: 11100, rate: 11, inclusive: true, wantNet: 10000, wantTax: 1100, wantGross: 11100},
+		{name: "exclusive rounds half up", amount: 105, rate: 10, wantNet: 105, wantTax: 11, wantGross: 116},
+		{name: "inclusive rounds net", amount: 100, rate: 11, inclusive: true, wantNet: 90, wantTax: 10, wantGross: 100},
+		{name: "fractional rate", amount: 1000, rate: 7.5, wantNet: 1000, wantTax: 75, wantGross: 1075},
+		{name: "zero rate", amount: 500, rate: 0, wantNet: 500, wantTax: 0, wantGross: 500},
+		{name: "zero rate inclusive", amount: 500, rate: 0, inclusive: true, wantNet: 500, wantTax: 0, wantGross: 500},
+		{name: "zero amount", amount: 0, rate: 11, wantNet: 0, wantTax: 0, wantGross: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			net, tax, gross := calculateTax(tt.amount, tt.rate, tt.inclusive)
+			if net != tt.wantNet || tax != tt.wantTax || gross != tt.wantGross {
+				t.Errorf("calculateTax(%d, %v, %v) = (%d, %d, %d), want (%d, %d, %d)",
+					tt.amount, tt.rate, tt.inclusive, net, tax, gross, tt.wantNet, tt.wantTax, tt.wantGross)
+			}
+		})
+	}
+}
@@ -0,0 +1,171 @@
+package services
+
+import (
+	"errors"
+	"products-api-with-jwt/models"
+	"slices"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrTranslationNotFound dikembalikan jika produk belum punya terjemahan untuk locale yang diminta
+	ErrTranslationNotFound = errors.New("translation not found")
+	// ErrDefaultLocale dikembalikan jika terjemahan diminta untuk DefaultLocale, yang isinya disimpan langsung di produk
+	ErrDefaultLocale = errors.New("content in the default locale is stored on the product itself, update the product instead")
+)
+
+type TranslationService struct {
+	DB *gorm.DB
+}
+
+// NewTranslationService menginisialisasi TranslationService baru
+func NewTranslationService(db *gorm.DB) *TranslationService {
+	return &TranslationService{DB: db}
+}
+
+// GetTranslations mengambil semua terjemahan produk
+func (s *TranslationService) GetTranslations(productID int) ([]models.ProductTranslation, error) {
+	if _, err := findProduct(s.DB, productID); err != nil {
+		return nil, err
+	}
+	var translations []models.ProductTranslation
+	if err := s.DB.Where("product_id = ?", productID).Order("locale").Find(&translations).Error; err != nil {
+		return nil, err
+	}
+	return translations, nil
+}
+
+// SetTranslation menyimpan terjemahan produk untuk satu locale, menimpa terjemahan yang sudah ada
+func (s *TranslationService) SetTranslation(productID int, locale string, input *models.ProductTranslationInput) (*models.ProductTranslation, error) {
+	if locale == models.DefaultLocale {
+		return nil, ErrDefaultLocale
+	}
+
+	var translation models.ProductTranslation
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		if _, err := findProduct(tx, productID); err != nil {
+			return err
+		}
+
+		err := tx.Where("product_id = ? AND locale = ?", productID, locale).First(&translation).Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		translation.ProductID = productID
+		translation.Locale = locale
+		translation.NamaProduk = strings.TrimSpace(input.NamaProduk)
+		translation.Deskripsi = strings.TrimSpace(input.Deskripsi)
+		return tx.Save(&translation).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &translation, nil
+}
+
+// DeleteTranslation menghapus terjemahan produk sehingga produk kembali ditampilkan dalam DefaultLocale
+func (s *TranslationService) DeleteTranslation(productID int, locale string) error {
+	if _, err := findProduct(s.DB, productID); err != nil {
+		return err
+	}
+	result := s.DB.Where("product_id = ? AND locale = ?", productID, locale).Delete(&models.ProductTranslation{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrTranslationNotFound
+	}
+	return nil
+}
+
+// GetMissingTranslations melaporkan produk yang belum punya terjemahan lengkap. Jika locale kosong,
+// semua locale selain DefaultLocale diperiksa. Field yang kosong di produk itu sendiri tidak dilaporkan.
+func (s *TranslationService) GetMissingTranslations(locale string) ([]models.MissingTranslation, error) {
+	locales := []string{locale}
+	if locale == "" {
+		locales = slices.DeleteFunc(slices.Clone(models.SupportedLocales), func(l string) bool {
+			return l == models.DefaultLocale
+		})
+	}
+
+	var products []models.Product
+	if err := s.DB.Select("id", "nama_produk", "deskripsi").Order("id").Find(&products).Error; err != nil {
+		return nil, err
+	}
+	var translations []models.ProductTranslation
+	if err := s.DB.Where("locale IN ?", locales).Find(&translations).Error; err != nil {
+		return nil, err
+	}
+	type key struct {
+		productID int
+		locale    string
+	}
+	existing := make(map[key]models.ProductTranslation, len(translations))
+	for _, translation := range translations {
+		existing[key{translation.ProductID, translation.Locale}] = translation
+	}
+
+	report := []models.MissingTranslation{}
+	for _, l := range locales {
+		for _, product := range products {
+			translation := existing[key{product.ID, l}]
+			var fields []string
+			if translation.NamaProduk == "" {
+				fields = append(fields, "nama_produk")
+			}
+			if translation.Deskripsi == "" && strings.TrimSpace(product.Deskripsi) != "" {
+				fields = append(fields, "deskripsi")
+			}
+			if len(fields) > 0 {
+				report = append(report, models.MissingTranslation{
+					ProductID:     product.ID,
+					NamaProduk:    product.NamaProduk,
+					Locale:        l,
+					MissingFields: fields,
+				})
+			}
+		}
+	}
+	return report, nil
+}
+
+// applyTranslations mengganti NamaProduk dan Deskripsi setiap produk dengan terjemahan locale yang diminta.
+// Field yang belum diterjemahkan jatuh kembali ke isi produk dalam DefaultLocale.
+func applyTranslations(db *gorm.DB, products []models.Product, locale string) error {
+	for i := range products {
+		products[i].Locale = models.DefaultLocale
+	}
+	if len(products) == 0 || locale == "" || locale == models.DefaultLocale {
+		return nil
+	}
+	ids := make([]int, len(products))
+	for i, product := range products {
+		ids[i] = product.ID
+	}
+
+	var translations []models.ProductTranslation
+	if err := db.Where("product_id IN ? AND locale = ?", ids, locale).Find(&translations).Error; err != nil {
+		return err
+	}
+	byProduct := make(map[int]models.ProductTranslation, len(translations))
+	for _, translation := range translations {
+		byProduct[translation.ProductID] = translation
+	}
+
+	for i := range products {
+		translation, ok := byProduct[products[i].ID]
+		if !ok {
+			continue
+		}
+		if translation.NamaProduk != "" {
+			products[i].NamaProduk = translation.NamaProduk
+			products[i].Locale = locale
+		}
+		if translation.Deskripsi != "" {
+			products[i].Deskripsi = translation.Deskripsi
+		}
+	}
+	return nil
+}
@@ -0,0 +1,314 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"products-api-with-jwt/models"
+	"slices"
+	"sort"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrVariantNotFound dikembalikan jika variant tidak ada di produk yang diminta
+	ErrVariantNotFound = errors.New("variant not found")
+	// ErrDuplicateOptionName dikembalikan jika nama option muncul lebih dari sekali di satu produk
+	ErrDuplicateOptionName = errors.New("option names must be unique per product")
+	// ErrInvalidVariantOptions dikembalikan jika options variant tidak cocok dengan definisi option produk
+	ErrInvalidVariantOptions = errors.New("variant options do not match the product options")
+	// ErrDuplicateVariantOptions dikembalikan jika kombinasi options sudah dipakai variant lain di produk yang sama
+	ErrDuplicateVariantOptions = errors.New("another variant already has these options")
+	// ErrOptionsInUse dikembalikan jika definisi option baru membuat variant yang sudah ada tidak valid
+	ErrOptionsInUse = errors.New("existing variants use options or values that would be removed")
+)
+
+type VariantService struct {
+	DB             *gorm.DB
+	ProductService *ProductService // Dipakai untuk mencatat stok awal variant di ledger
+}
+
+// NewVariantService menginisialisasi VariantService baru
+func NewVariantService(db *gorm.DB, productService *ProductService) *VariantService {
+	return &VariantService{DB: db, ProductService: productService}
+}
+
+// GetOptions mengambil definisi option sebuah produk sesuai urutan
+func (s *VariantService) GetOptions(productID int) ([]models.ProductOption, error) {
+	if _, err := findProduct(s.DB, productID); err != nil {
+		return nil, err
+	}
+	return productOptions(s.DB, productID)
+}
+
+// SetOptions menggantikan seluruh definisi option produk. Ditolak jika ada variant yang tidak cocok lagi.
+func (s *VariantService) SetOptions(productID int, input *models.ProductOptionsInput) ([]models.ProductOption, error) {
+	var options []models.ProductOption
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		if _, err := findProduct(tx, productID); err != nil {
+			return err
+		}
+
+		seen := make(map[string]bool, len(input.Options))
+		options = make([]models.ProductOption, 0, len(input.Options))
+		for i, in := range input.Options {
+			name := strings.ToLower(strings.TrimSpace(in.Name))
+			if seen[name] {
+				return ErrDuplicateOptionName
+			}
+			seen[name] = true
+			options = append(options, models.ProductOption{
+				ProductID: productID,
+				Name:      name,
+				Values:    uniqueValues(in.Values),
+				Position:  i,
+			})
+		}
+
+		var variants []models.ProductVariant
+		if err := tx.Where("product_id = ?", productID).Find(&variants).Error; err != nil {
+			return err
+		}
+		for _, variant := range variants {
+			if validateVariantOptions(options, variant.Options) != nil {
+				return ErrOptionsInUse
+			}
+		}
+
+		if err := tx.Where("product_id = ?", productID).Delete(&models.ProductOption{}).Error; err != nil {
+			return err
+		}
+		if len(options) > 0 {
+			return tx.Create(&options).Error
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return options, nil
+}
+
+// GetVariants mengambil semua variant sebuah produk
+func (s *VariantService) GetVariants(productID int) ([]models.ProductVariant, error) {
+	if _, err := findProduct(s.DB, productID); err != nil {
+		return nil, err
+	}
+	var variants []models.ProductVariant
+	if err := s.DB.Where("product_id = ?", productID).Order("id").Find(&variants).Error; err != nil {
+		return nil, err
+	}
+	return variants, nil
+}
+
+// GetVariant mengambil satu variant milik produk
+func (s *VariantService) GetVariant(productID, variantID int) (*models.ProductVariant, error) {
+	if _, err := findProduct(s.DB, productID); err != nil {
+		return nil, err
+	}
+	return findVariant(s.DB, productID, variantID)
+}
+
+// CreateVariant menambah variant baru. Options harus mengisi setiap option produk dengan nilai yang diizinkan.
+// Stok awal dicatat sebagai receipt di ledger.
+func (s *VariantService) CreateVariant(productID int, input *models.CreateVariantInput, userID int) (*models.ProductVariant, error) {
+	variant := models.ProductVariant{
+		ProductID: productID,
+		SKU:       strings.TrimSpace(input.SKU),
+		Barcode:   optionalCode(input.Barcode),
+		Options:   normalizeVariantOptions(input.Options),
+	}
+
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		product, err := findProduct(tx, productID)
+		if err != nil {
+			return err
+		}
+		if input.PriceOverride != nil {
+			override := models.ToMinor(*input.PriceOverride, product.Currency)
+			variant.PriceOverrideMinor = &override
+		}
+		if err := checkVariant(tx, &variant); err != nil {
+			return err
+		}
+		if product.Type == models.ProductBundle && input.Stok != 0 {
+			return ErrBundleStock
+		}
+		if err := tx.Create(&variant).Error; err != nil {
+			return err
+		}
+		if input.Stok == 0 {
+			return nil
+		}
+
+		movement := models.StockMovement{
+			ProductID: productID,
+			VariantID: &variant.ID,
+			Type:      models.StockMovementReceipt,
+			Quantity:  input.Stok,
+			UserID:    userID,
+			Reason:    "initial stock",
+		}
+		movements, err := s.ProductService.postStockMovement(tx, movement)
+		if err != nil {
+			return err
+		}
+		variant.Stok = movements[len(movements)-1].BalanceAfter
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &variant, nil
+}
+
+// UpdateVariant memperbarui variant. Hanya field yang dikirim yang diubah.
+func (s *VariantService) UpdateVariant(productID, variantID int, input *models.UpdateVariantInput) (*models.ProductVariant, error) {
+	var variant *models.ProductVariant
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		product, err := findProduct(tx, productID)
+		if err != nil {
+			return err
+		}
+		if variant, err = findVariant(tx, productID, variantID); err != nil {
+			return err
+		}
+
+		if input.SKU != nil {
+			variant.SKU = strings.TrimSpace(*input.SKU)
+		}
+		if input.PriceOverride != nil {
+			if *input.PriceOverride == 0 {
+				variant.PriceOverrideMinor = nil
+			} else {
+				override := models.ToMinor(*input.PriceOverride, product.Currency)
+				variant.PriceOverrideMinor = &override
+			}
+		}
+		if input.Barcode != nil {
+			variant.Barcode = optionalCode(*input.Barcode)
+		}
+		if input.Options != nil {
+			variant.Options = normalizeVariantOptions(*input.Options)
+		}
+
+		if err := checkVariant(tx, variant); err != nil {
+			return err
+		}
+		return tx.Select("*").Omit("stok").Save(variant).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return variant, nil
+}
+
+// DeleteVariant menghapus variant dari produk beserta stok per warehouse-nya. Entri ledger variant tetap disimpan.
+func (s *VariantService) DeleteVariant(productID, variantID int) error {
+	if _, err := findProduct(s.DB, productID); err != nil {
+		return err
+	}
+	return s.DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("product_id = ?", productID).Delete(&models.ProductVariant{}, variantID)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrVariantNotFound
+		}
+		return tx.Where("variant_id = ?", variantID).Delete(&models.WarehouseStock{}).Error
+	})
+}
+
+// checkVariant memastikan SKU dan barcode unik, serta options variant valid dan belum dipakai variant lain di produk yang sama
+func checkVariant(tx *gorm.DB, variant *models.ProductVariant) error {
+	if err := checkProductCodes(tx, &variant.SKU, variant.Barcode, 0, variant.ID); err != nil {
+		return err
+	}
+
+	options, err := productOptions(tx, variant.ProductID)
+	if err != nil {
+		return err
+	}
+	if err := validateVariantOptions(options, variant.Options); err != nil {
+		return err
+	}
+
+	var siblings []models.ProductVariant
+	if err := tx.Where("product_id = ? AND id <> ?", variant.ProductID, variant.ID).Find(&siblings).Error; err != nil {
+		return err
+	}
+	key := variantOptionsKey(variant.Options)
+	for _, sibling := range siblings {
+		if variantOptionsKey(sibling.Options) == key {
+			return ErrDuplicateVariantOptions
+		}
+	}
+	return nil
+}
+
+// validateVariantOptions memastikan values mengisi tepat setiap option dengan salah satu nilai yang diizinkan
+func validateVariantOptions(options []models.ProductOption, values map[string]string) error {
+	if len(values) != len(options) {
+		return fmt.Errorf("%w: expected %d options, got %d", ErrInvalidVariantOptions, len(options), len(values))
+	}
+	for _, option := range options {
+		value, ok := values[option.Name]
+		if !ok {
+			return fmt.Errorf("%w: missing option %q", ErrInvalidVariantOptions, option.Name)
+		}
+		if !slices.Contains(option.Values, value) {
+			return fmt.Errorf("%w: %q is not a valid %s", ErrInvalidVariantOptions, value, option.Name)
+		}
+	}
+	return nil
+}
+
+func productOptions(db *gorm.DB, productID int) ([]models.ProductOption, error) {
+	var options []models.ProductOption
+	if err := db.Where("product_id = ?", productID).Order("position").Find(&options).Error; err != nil {
+		return nil, err
+	}
+	return options, nil
+}
+
+func findVariant(db *gorm.DB, productID, variantID int) (*models.ProductVariant, error) {
+	var variant models.ProductVariant
+	if err := db.Where("product_id = ?", productID).First(&variant, variantID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrVariantNotFound
+		}
+		return nil, err
+	}
+	return &variant, nil
+}
+
+// normalizeVariantOptions menyamakan nama option (huruf kecil) dan membuang spasi di nilai
+func normalizeVariantOptions(values map[string]string) map[string]string {
+	normalized := make(map[string]string, len(values))
+	for name, value := range values {
+		normalized[strings.ToLower(strings.TrimSpace(name))] = strings.TrimSpace(value)
+	}
+	return normalized
+}
+
+// variantOptionsKey membuat kunci stabil dari kombinasi options untuk membandingkan variant
+func variantOptionsKey(values map[string]string) string {
+	pairs := make([]string, 0, len(values))
+	for name, value := range values {
+		pairs = append(pairs, name+"="+value)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, "\x00")
+}
+
+func uniqueValues(values []string) []string {
+	unique := make([]string, 0, len(values))
+	for _, value := range values {
+		if value = strings.TrimSpace(value); !slices.Contains(unique, value) {
+			unique = append(unique, value)
+		}
+	}
+	return unique
+}
@@ -0,0 +1,168 @@
+package services
+
+import (
+	"errors"
+	"products-api-with-jwt/models"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrWarehouseNotFound dikembalikan jika warehouse dengan ID yang diminta tidak ada
+	ErrWarehouseNotFound = errors.New("warehouse not found")
+	// ErrNoWarehouse dikembalikan jika belum ada warehouse sama sekali untuk menampung stok
+	ErrNoWarehouse = errors.New("no warehouse configured")
+	// ErrDuplicateWarehouseCode dikembalikan jika kode warehouse sudah dipakai
+	ErrDuplicateWarehouseCode = errors.New("warehouse code already exists")
+	// ErrWarehouseInUse dikembalikan jika warehouse yang akan dihapus masih menyimpan stok atau merupakan default
+	ErrWarehouseInUse = errors.New("warehouse still holds stock or is the default warehouse")
+)
+
+type WarehouseService struct {
+	DB *gorm.DB
+}
+
+// NewWarehouseService menginisialisasi WarehouseService baru
+func NewWarehouseService(db *gorm.DB) *WarehouseService {
+	return &WarehouseService{DB: db}
+}
+
+// GetAllWarehouses mengambil semua warehouse
+func (s *WarehouseService) GetAllWarehouses() ([]models.Warehouse, error) {
+	var warehouses []models.Warehouse
+	if err := s.DB.Order("id").Find(&warehouses).Error; err != nil {
+		return nil, err
+	}
+	return warehouses, nil
+}
+
+// GetWarehouseByID mengambil warehouse berdasarkan ID
+func (s *WarehouseService) GetWarehouseByID(id int) (*models.Warehouse, error) {
+	var warehouse models.Warehouse
+	if err := s.DB.First(&warehouse, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrWarehouseNotFound
+		}
+		return nil, err
+	}
+	return &warehouse, nil
+}
+
+// CreateWarehouse menambah warehouse baru
+func (s *WarehouseService) CreateWarehouse(input *models.WarehouseInput) (*models.Warehouse, error) {
+	warehouse := models.Warehouse{
+		Code:      strings.ToUpper(strings.TrimSpace(input.Code)),
+		Name:      strings.TrimSpace(input.Name),
+		Address:   input.Address,
+		IsDefault: input.IsDefault,
+	}
+
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		if err := checkUniqueWarehouseCode(tx, warehouse.Code, 0); err != nil {
+			return err
+		}
+		if warehouse.IsDefault {
+			if err := tx.Model(&models.Warehouse{}).Where("is_default = ?", true).Update("is_default", false).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Create(&warehouse).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &warehouse, nil
+}
+
+// UpdateWarehouse memperbarui data warehouse
+func (s *WarehouseService) UpdateWarehouse(id int, input *models.WarehouseInput) (*models.Warehouse, error) {
+	warehouse, err := s.GetWarehouseByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	warehouse.Code = strings.ToUpper(strings.TrimSpace(input.Code))
+	warehouse.Name = strings.TrimSpace(input.Name)
+	warehouse.Address = input.Address
+
+	err = s.DB.Transaction(func(tx *gorm.DB) error {
+		if err := checkUniqueWarehouseCode(tx, warehouse.Code, warehouse.ID); err != nil {
+			return err
+		}
+		// Default hanya bisa dipindahkan ke warehouse lain, tidak bisa dikosongkan
+		if input.IsDefault && !warehouse.IsDefault {
+			if err := tx.Model(&models.Warehouse{}).Where("is_default = ?", true).Update("is_default", false).Error; err != nil {
+				return err
+			}
+			warehouse.IsDefault = true
+		}
+		return tx.Save(warehouse).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return warehouse, nil
+}
+
+// DeleteWarehouse menghapus warehouse yang sudah kosong dan bukan warehouse default
+func (s *WarehouseService) DeleteWarehouse(id int) error {
+	warehouse, err := s.GetWarehouseByID(id)
+	if err != nil {
+		return err
+	}
+	if warehouse.IsDefault {
+		return ErrWarehouseInUse
+	}
+
+	return s.DB.Transaction(func(tx *gorm.DB) error {
+		var count int64
+		if err := tx.Model(&models.WarehouseStock{}).Where("warehouse_id = ? AND quantity <> 0", id).Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return ErrWarehouseInUse
+		}
+		if err := tx.Where("warehouse_id = ?", id).Delete(&models.WarehouseStock{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.Warehouse{}, id).Error
+	})
+}
+
+// GetWarehouseStock mengambil stok semua produk di sebuah warehouse
+func (s *WarehouseService) GetWarehouseStock(id int) ([]models.WarehouseStock, error) {
+	if _, err := s.GetWarehouseByID(id); err != nil {
+		return nil, err
+	}
+
+	var locations []models.WarehouseStock
+	if err := s.DB.Where("warehouse_id = ? AND quantity <> 0", id).Order("product_id").Find(&locations).Error; err != nil {
+		return nil, err
+	}
+	return locations, nil
+}
+
+func checkUniqueWarehouseCode(tx *gorm.DB, code string, excludeID int) error {
+	var count int64
+	if err := tx.Model(&models.Warehouse{}).Where("code = ? AND id <> ?", code, excludeID).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrDuplicateWarehouseCode
+	}
+	return nil
+}
+
+// defaultWarehouseID mengembalikan warehouse default, atau warehouse pertama jika tidak ada yang ditandai default
+func defaultWarehouseID(tx *gorm.DB) (int, error) {
+	var warehouse models.Warehouse
+	err := tx.Order("is_default desc, id").First(&warehouse).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, ErrNoWarehouse
+	}
+	if err != nil {
+		return 0, err
+	}
+	return warehouse.ID, nil
+}
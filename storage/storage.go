@@ -0,0 +1,16 @@
+package storage
+
+import (
+	"errors"
+	"io"
+)
+
+// ErrNotFound dikembalikan jika file dengan key yang diminta tidak ada
+var ErrNotFound = errors.New("file not found")
+
+// Storage menyimpan file berdasarkan key relatif, misalnya "products/1/abc.jpg"
+type Storage interface {
+	Save(key string, r io.Reader) error
+	Open(key string) (io.ReadCloser, error)
+	Delete(key string) error
+}
@@ -0,0 +1,185 @@
+package validations
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"products-api-with-jwt/models"
+	"reflect"
+	"strings"
+	"unicode"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// Setup mendaftarkan aturan validasi custom ke validator bawaan gin.
+// Harus dipanggil sekali saat aplikasi start, sebelum router menerima request.
+func Setup() error {
+	v, ok := binding.Validator.Engine().(*validator.Validate)
+	if !ok {
+		return errors.New("unexpected validator engine")
+	}
+
+	// Gunakan nama field JSON di pesan error, bukan nama field Go
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+		if name == "-" {
+			return ""
+		}
+		if name == "" {
+			return field.Name
+		}
+		return name
+	})
+
+	if err := v.RegisterValidation("notblank", notBlank); err != nil {
+		return err
+	}
+	if err := v.RegisterValidation("nocontrol", noControl); err != nil {
+		return err
+	}
+	if err := v.RegisterValidation("barcode", barcode); err != nil {
+		return err
+	}
+	return v.RegisterValidation("currency", currency)
+}
+
+// notBlank menolak string yang hanya berisi spasi
+func notBlank(fl validator.FieldLevel) bool {
+	return strings.TrimSpace(fl.Field().String()) != ""
+}
+
+// noControl menolak karakter kontrol seperti CR dan LF, untuk nilai satu baris yang bisa
+// masuk ke header atau log
+func noControl(fl validator.FieldLevel) bool {
+	return !strings.ContainsFunc(fl.Field().String(), unicode.IsControl)
+}
+
+// barcode menerima EAN-8, UPC-A (12 digit) atau EAN-13 dengan check digit yang benar.
+// String kosong diizinkan karena dipakai untuk menghapus barcode saat update.
+func barcode(fl validator.FieldLevel) bool {
+	code := fl.Field().String()
+	return code == "" || ValidBarcode(code)
+}
+
+// currency menerima kode ISO 4217 yang didukung. String kosong diizinkan agar default dipakai.
+func currency(fl validator.FieldLevel) bool {
+	code := fl.Field().String()
+	_, ok := models.CurrencyExponents[code]
+	return code == "" || ok
+}
+
+// ValidBarcode memeriksa panjang dan check digit GS1 sebuah barcode EAN-8, UPC-A atau EAN-13
+func ValidBarcode(code string) bool {
+	if len(code) != 8 && len(code) != 12 && len(code) != 13 {
+		return false
+	}
+	sum := 0
+	for i := len(code) - 1; i >= 0; i-- {
+		c := code[i]
+		if c < '0' || c > '9' {
+			return false
+		}
+		digit := int(c - '0')
+		// Bobot dihitung dari kanan: check digit 1, lalu bergantian 3 dan 1
+		if (len(code)-1-i)%2 == 1 {
+			digit *= 3
+		}
+		sum += digit
+	}
+	return sum%10 == 0
+}
+
+// FieldErrors menerjemahkan error dari ShouldBindJSON menjadi daftar error per field
+func FieldErrors(err error) []models.FieldError {
+	var validationErrors validator.ValidationErrors
+	if errors.As(err, &validationErrors) {
+		fieldErrors := make([]models.FieldError, 0, len(validationErrors))
+		for _, fe := range validationErrors {
+			fieldErrors = append(fieldErrors, translate(fe))
+		}
+		return fieldErrors
+	}
+
+	var typeError *json.UnmarshalTypeError
+	if errors.As(err, &typeError) {
+		return []models.FieldError{{
+			Field:   typeError.Field,
+			Code:    models.ValidationInvalidType,
+			Message: fmt.Sprintf("must be of type %s", typeError.Type.String()),
+		}}
+	}
+
+	message := "request body is not valid JSON"
+	if errors.Is(err, io.EOF) {
+		message = "request body is empty"
+	}
+	return []models.FieldError{{Field: "", Code: models.ValidationMalformed, Message: message}}
+}
+
+func translate(fe validator.FieldError) models.FieldError {
+	field := fieldPath(fe)
+	isString := fe.Kind() == reflect.String
+
+	switch fe.Tag() {
+	case "required", "required_if", "notblank":
+		return models.FieldError{Field: field, Code: models.ValidationRequired, Message: "is required"}
+	case "max":
+		if isString {
+			return models.FieldError{Field: field, Code: models.ValidationTooLong, Message: fmt.Sprintf("must be at most %s characters", fe.Param())}
+		}
+		return models.FieldError{Field: field, Code: models.ValidationOutOfRange, Message: fmt.Sprintf("must be at most %s", fe.Param())}
+	case "min":
+		if isString {
+			return models.FieldError{Field: field, Code: models.ValidationTooShort, Message: fmt.Sprintf("must be at least %s characters", fe.Param())}
+		}
+		return models.FieldError{Field: field, Code: models.ValidationOutOfRange, Message: fmt.Sprintf("must be at least %s", fe.Param())}
+	case "gte":
+		return models.FieldError{Field: field, Code: models.ValidationOutOfRange, Message: fmt.Sprintf("must be greater than or equal to %s", fe.Param())}
+	case "gt":
+		return models.FieldError{Field: field, Code: models.ValidationOutOfRange, Message: fmt.Sprintf("must be greater than %s", fe.Param())}
+	case "lte":
+		return models.FieldError{Field: field, Code: models.ValidationOutOfRange, Message: fmt.Sprintf("must be less than or equal to %s", fe.Param())}
+	case "lt":
+		return models.FieldError{Field: field, Code: models.ValidationOutOfRange, Message: fmt.Sprintf("must be less than %s", fe.Param())}
+	case "gtfield":
+		return models.FieldError{Field: field, Code: models.ValidationOutOfRange, Message: "must be after " + snakeCase(fe.Param())}
+	case "nefield":
+		return models.FieldError{Field: field, Code: models.ValidationInvalidValue, Message: "must be different from " + snakeCase(fe.Param())}
+	case "nocontrol":
+		return models.FieldError{Field: field, Code: models.ValidationInvalidValue, Message: "must not contain line breaks or other control characters"}
+	case "barcode":
+		return models.FieldError{Field: field, Code: models.ValidationInvalidValue, Message: "must be a valid EAN-13, EAN-8 or UPC-A barcode"}
+	case "currency":
+		return models.FieldError{Field: field, Code: models.ValidationInvalidValue, Message: "must be one of: " + strings.Join(models.SupportedCurrencies(), " ")}
+	case "oneof":
+		return models.FieldError{Field: field, Code: models.ValidationInvalidValue, Message: fmt.Sprintf("must be one of: %s", fe.Param())}
+	default:
+		return models.FieldError{Field: field, Code: models.ValidationInvalidValue, Message: fmt.Sprintf("failed the %q rule", fe.Tag())}
+	}
+}
+
+// fieldPath menghasilkan path field tanpa nama struct, misal "items[0].quantity"
+func fieldPath(fe validator.FieldError) string {
+	namespace := fe.Namespace()
+	if i := strings.Index(namespace, "."); i >= 0 {
+		return namespace[i+1:]
+	}
+	return fe.Field()
+}
+
+// snakeCase mengubah nama field Go (misal "FromWarehouseID") menjadi nama JSON ("from_warehouse_id")
+func snakeCase(name string) string {
+	var b strings.Builder
+	runes := []rune(name)
+	for i, r := range runes {
+		upper := unicode.IsUpper(r)
+		if upper && i > 0 && (!unicode.IsUpper(runes[i-1]) || (i+1 < len(runes) && unicode.IsLower(runes[i+1]))) {
+			b.WriteByte('_')
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}